@@ -2,18 +2,94 @@ package gortsp
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"github.com/racoon-devel/gortsp/pkg/format"
+	"github.com/racoon-devel/gortsp/pkg/rtcp"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
 	"github.com/racoon-devel/gortsp/pkg/rtsp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
 	"net"
 	"net/http"
 	urlpkg "net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// TransportMode selects how media packets are delivered
+type TransportMode int
+
+const (
+	// TransportTCP means RTP packets are interleaved with RTSP messages
+	TransportTCP TransportMode = iota
+	// TransportUDP means RTP and RTCP packets are received by UDP port pair
+	TransportUDP
+	// TransportMulticast means RTP and RTCP packets are received from multicast group chosen by server
+	TransportMulticast
+)
+
+// PacketHandler is called for every RTP packet received from the media with specified index. Packets are passed
+// as is, without depacketization, use FrameHandler to receive media frames
+type PacketHandler func(media int, p *rtp.Packet)
+
+// FrameHandler is called for every frame decoded from the media with specified index
+type FrameHandler func(media int, f format.Format, frame *format.Frame)
+
 type Client struct {
 	UserAgent string
 
+	// Transport is a media delivery mode, interleaved TCP by default
+	Transport TransportMode
+
+	// KeyFrameOnLoss enables PLI requests when packets of video media are lost or can't be decoded,
+	// so the server sends a new reference picture instead of waiting for the next key frame
+	KeyFrameOnLoss bool
+
+	// CNAME identifies the client in RTCP reports, random one is generated if empty
+	CNAME string
+
+	// MulticastInterface is a network interface to join multicast groups on, system default is used if nil
+	MulticastInterface *net.Interface
+
+	// OnPacket receives raw RTP packets after PLAY
+	OnPacket PacketHandler
+
+	// OnFrame receives frames decoded by formats of media descriptions after PLAY
+	OnFrame FrameHandler
+
 	url *urlpkg.URL
 	s   *rtsp.Session
+	ctx context.Context
+
+	// methods supported by server
+	methods map[rtsp.Method]bool
+	// aggregate control URL
+	base *urlpkg.URL
+	// session description, announced by server
+	desc *sdp.SessionDescription
+	// control URLs of media streams
+	medias []*urlpkg.URL
+	// interleaved channel -> media index
+	channels map[uint8]int
+	// payload type -> decoder for every media, nil decoder means unsupported payload type
+	decoders []map[uint8]*decoder
+	// media index -> SSRC of the last received packet
+	ssrcs map[int]uint32
+	// maps RTP timestamps to wall clock by sender reports
+	sync *rtcp.Synchronizer
+	// reception statistics of every media
+	receivers []*rtcp.Receiver
+	rtcpMu    sync.Mutex
+	// media index -> the last sequence number and the last PLI time
+	seqs              map[int]uint16
+	keyFrameRequested map[int]time.Time
+
+	mu      sync.Mutex
+	session string
+	timeout time.Duration
 }
 
 func (c *Client) Run(url string) error {
@@ -28,6 +104,9 @@ func (c *Client) RunWithContext(url string, ctx context.Context) error {
 	if u.Scheme != "rtsp" || u.Host == "" {
 		return rtsp.ErrInvalidURL
 	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
 
 	conn, err := net.Dial("tcp", u.Host)
 	if err != nil {
@@ -35,33 +114,595 @@ func (c *Client) RunWithContext(url string, ctx context.Context) error {
 	}
 
 	c.url = u
+	c.ctx = ctx
 	c.s = rtsp.NewSession(conn, ctx)
 
 	return nil
 }
 
+// Receive performs playback sequence (OPTIONS, DESCRIBE, SETUP, PLAY) and forwards received RTP packets
+// to OnPacket and decoded frames to OnFrame until the session fails or the context is canceled
 func (c *Client) Receive() error {
-	_, err := c.do(rtsp.Options, nil, nil)
+	resp, err := c.do(rtsp.Options, c.url, nil, nil)
 	if err != nil {
 		return fmt.Errorf("do OPTIONS failed: %w", err)
 	}
-	// todo: processing options
+	c.methods = parsePublic(resp.Header.Get("Public"))
 
-	resp, err := c.do(rtsp.Describe, http.Header{"Accept": {"application/sdp"}}, nil)
+	resp, err = c.do(rtsp.Describe, c.url, http.Header{"Accept": {sdp.ContentType}}, nil)
 	if err != nil {
 		return fmt.Errorf("do DESCRIBE failed: %w", err)
 	}
+
+	if err = c.describe(resp); err != nil {
+		return fmt.Errorf("process DESCRIBE response failed: %w", err)
+	}
+
+	c.channels = map[uint8]int{}
+	c.ssrcs = map[int]uint32{}
+	c.seqs = map[int]uint16{}
+	c.keyFrameRequested = map[int]time.Time{}
+	c.sync = rtcp.NewSynchronizer()
+	c.receivers = make([]*rtcp.Receiver, len(c.medias))
+	for i := range c.receivers {
+		c.receivers[i] = rtcp.NewReceiver(0)
+	}
+	if c.CNAME == "" {
+		c.CNAME = randomCNAME()
+	}
+	for i := range c.medias {
+		if err = c.setup(i); err != nil {
+			return fmt.Errorf("do SETUP failed: %w", err)
+		}
+	}
+
+	if _, err = c.do(rtsp.Play, c.base, http.Header{"Range": {"npt=0.000-"}}, nil); err != nil {
+		return fmt.Errorf("do PLAY failed: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	go c.keepAlive(ctx)
+	go c.report(ctx)
+
+	return c.receive()
+}
+
+// Description returns session description received by DESCRIBE. Media indexes passed to OnPacket
+// correspond to Medias of the description
+func (c *Client) Description() *sdp.SessionDescription {
+	return c.desc
+}
+
+// WallClock maps RTP timestamp of the media to wall clock time of the server by RTCP sender reports.
+// It returns false until the first sender report is received. It must be called from OnPacket or OnFrame
+func (c *Client) WallClock(media int, timestamp uint32) (time.Time, bool) {
+	ssrc, ok := c.ssrcs[media]
+	if !ok {
+		return time.Time{}, false
+	}
+	return c.sync.Time(ssrc, timestamp)
+}
+
+// Stats returns reception statistics of sources of the media
+func (c *Client) Stats(media int) []rtcp.ReceptionStats {
+	c.rtcpMu.Lock()
+	defer c.rtcpMu.Unlock()
+
+	if media < 0 || media >= len(c.receivers) {
+		return nil
+	}
+	return c.receivers[media].Stats()
+}
+
+// WriteFeedback sends RTCP feedback packets of the media. They are preceded by empty RR and SDES
+// to make compound packet (RFC 4585 section 3.1)
+func (c *Client) WriteFeedback(media int, packets ...rtcp.Packet) error {
+	c.rtcpMu.Lock()
+	if media < 0 || media >= len(c.receivers) {
+		c.rtcpMu.Unlock()
+		return fmt.Errorf("unknown media: %d", media)
+	}
+	ssrc := c.receivers[media].SSRC
+	c.rtcpMu.Unlock()
+
+	channel, ok := c.channel(media)
+	if !ok {
+		return fmt.Errorf("media %d is not set up", media)
+	}
+
+	compound := append([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: ssrc}, rtcp.NewCNAME(ssrc, c.CNAME)}, packets...)
+	data, err := rtcp.Compose(compound...)
+	if err != nil {
+		return err
+	}
+	return c.s.WritePacket(channel+1, data)
+}
+
+// RequestKeyFrame sends PLI to the sender of the media
+func (c *Client) RequestKeyFrame(media int) error {
+	sender, source, err := c.feedbackSSRCs(media)
+	if err != nil {
+		return err
+	}
+	return c.WriteFeedback(media, &rtcp.PictureLossIndication{SenderSSRC: sender, MediaSSRC: source})
+}
+
+// RequestRetransmission sends generic NACK of lost packets of the media, e.g. gaps of rtp.JitterBuffer
+func (c *Client) RequestRetransmission(media int, gaps []rtp.Gap) error {
+	sender, source, err := c.feedbackSSRCs(media)
+	if err != nil {
+		return err
+	}
+	return c.WriteFeedback(media, rtcp.NewNACKFromGaps(sender, source, gaps))
+}
+
+// Close terminates the session
+func (c *Client) Close() {
+	if c.s != nil {
+		c.s.Close()
+	}
+}
+
+func (c *Client) describe(resp *rtsp.Response) error {
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, sdp.ContentType) {
+		return fmt.Errorf("unexpected content type: %s", ct)
+	}
+
+	base := c.url
+	if cb := resp.Header.Get("Content-Base"); cb != "" {
+		u, err := urlpkg.Parse(cb)
+		if err != nil {
+			return fmt.Errorf("parse Content-Base failed: %w", err)
+		}
+		base = u
+	}
+
+	desc, err := sdp.Parse(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(desc.Medias) == 0 {
+		return errNoMedia
+	}
+
+	if c.base, err = controlURL(base, desc.Control()); err != nil {
+		return err
+	}
+
+	c.medias = make([]*urlpkg.URL, 0, len(desc.Medias))
+	for _, m := range desc.Medias {
+		u, err := controlURL(base, m.Control())
+		if err != nil {
+			return err
+		}
+		c.medias = append(c.medias, u)
+	}
+	c.desc = desc
+
+	c.decoders = make([]map[uint8]*decoder, len(desc.Medias))
+	for i := range c.decoders {
+		c.decoders[i] = map[uint8]*decoder{}
+	}
+
+	return nil
+}
+
+func (c *Client) setup(index int) error {
+	// the channel identifies the media for any transport mode
+	channel := uint8(index * 2)
+	transport := rtsp.Transport{
+		Protocol: "RTP",
+		Profile:  "AVP",
+		Delivery: rtsp.Unicast,
+	}
+
+	switch c.Transport {
+	case TransportTCP:
+		transport.LowerTransport = rtsp.TransportTCP
+		transport.Interleaved = &rtsp.PortRange{From: uint16(channel), To: uint16(channel + 1)}
+	case TransportUDP:
+		ports, err := c.s.ListenUDP(channel)
+		if err != nil {
+			return fmt.Errorf("listen UDP failed: %w", err)
+		}
+		transport.ClientPort = &ports
+	case TransportMulticast:
+		transport.Delivery = rtsp.Multicast
+	default:
+		return fmt.Errorf("unsupported transport mode: %d", c.Transport)
+	}
+
+	resp, err := c.do(rtsp.Setup, c.medias[index], http.Header{"Transport": {transport.String()}}, nil)
+	if err != nil {
+		return err
+	}
+
+	transports, err := rtsp.ParseTransports(resp.Header.Get("Transport"))
+	if err != nil {
+		return err
+	}
+	accepted := transports[0]
+
+	session := resp.Header.Get("Session")
+	if session == "" {
+		return errNoSession
+	}
+	id, timeout := parseSession(session)
+
+	c.mu.Lock()
+	c.session = id
+	c.timeout = timeout
+	c.mu.Unlock()
+
+	switch c.Transport {
+	case TransportTCP:
+		if accepted.Interleaved != nil {
+			channel = uint8(accepted.Interleaved.From)
+		}
+	case TransportUDP:
+		if accepted.ServerPort == nil {
+			return fmt.Errorf("server_port is not presented: %s", accepted)
+		}
+		host := accepted.Source
+		if host == "" {
+			host = c.url.Hostname()
+		}
+		if err = c.s.ConnectUDP(channel, host, *accepted.ServerPort); err != nil {
+			return err
+		}
+	case TransportMulticast:
+		group, err := multicastGroup(accepted)
+		if err != nil {
+			return err
+		}
+		group.Interface = c.MulticastInterface
+		if err = c.s.JoinMulticast(channel, group); err != nil {
+			return err
+		}
+	}
+	c.channels[channel] = index
+
+	return nil
+}
+
+// receive dispatches incoming packets until the session is alive
+func (c *Client) receive() error {
+	for item := range c.s.Incoming() {
+		switch t := item.(type) {
+		case *rtsp.IncomingRTP:
+			index, ok := c.channels[t.Channel]
+			if !ok {
+				continue
+			}
+			var p rtp.Packet
+			if err := p.Parse(t.Packet); err != nil {
+				continue
+			}
+			c.bind(index, &p)
+			if c.KeyFrameOnLoss {
+				c.checkLoss(index, &p)
+			}
+
+			c.rtcpMu.Lock()
+			c.receivers[index].ProcessRTP(&p.Header, time.Now())
+			c.rtcpMu.Unlock()
+
+			if c.OnPacket != nil {
+				c.OnPacket(index, &p)
+			}
+			if c.OnFrame != nil {
+				c.decode(index, &p)
+			}
+		case *rtsp.IncomingRTCP:
+			index, ok := c.channels[t.Channel&^1]
+			if !ok {
+				continue
+			}
+			packets, err := rtcp.Parse(t.Packet)
+			if err != nil {
+				continue
+			}
+			c.sync.Process(packets)
+
+			c.rtcpMu.Lock()
+			c.receivers[index].ProcessRTCP(packets, time.Now())
+			c.rtcpMu.Unlock()
+		case error:
+			return t
+		}
+	}
+
+	if err := c.s.Err(); err != nil {
+		return err
+	}
+	return rtsp.ErrSessionClosed
+}
+
+// bind remembers SSRC of the media and passes clock rate of the stream to synchronizer
+func (c *Client) bind(index int, p *rtp.Packet) {
+	if ssrc, ok := c.ssrcs[index]; ok && ssrc == p.Header.SSRC {
+		return
+	}
+	c.rtcpMu.Lock()
+	c.ssrcs[index] = p.Header.SSRC
+	c.rtcpMu.Unlock()
+
+	if f, err := format.New(c.desc.Medias[index], p.Header.PayloadType); err == nil {
+		c.sync.SetClockRate(p.Header.SSRC, f.ClockRate())
+
+		c.rtcpMu.Lock()
+		c.receivers[index].ClockRate = f.ClockRate()
+		c.rtcpMu.Unlock()
+	}
+}
+
+// decode passes the packet to decoder of its payload type, decoders are created by the first packets
+func (c *Client) decode(index int, p *rtp.Packet) {
+	pt := p.Header.PayloadType
+	d, ok := c.decoders[index][pt]
+	if !ok {
+		d = newDecoder(c.desc.Medias[index], pt)
+		c.decoders[index][pt] = d
+	}
+	if d == nil {
+		return
+	}
+
+	// damaged frames are dropped by depacketizers, so errors are not fatal for the stream
+	frames, err := d.Decode(p)
+	if err != nil && c.KeyFrameOnLoss {
+		c.requestKeyFrame(index)
+	}
+	for i := range frames {
+		c.OnFrame(index, d.format, &frames[i])
+	}
+}
+
+// keepAlive prevents session expiration on the server side
+func (c *Client) keepAlive(ctx context.Context) {
+	method := rtsp.Options
+	if c.methods[rtsp.GetParameter] {
+		method = rtsp.GetParameter
+	}
+
+	c.mu.Lock()
+	interval := c.timeout / 2
+	c.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := c.do(method, c.base, nil, nil); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkLoss requests key frame if packets of video media are lost
+func (c *Client) checkLoss(index int, p *rtp.Packet) {
+	last, ok := c.seqs[index]
+	c.seqs[index] = p.Header.SequenceNumber
+	if ok && int16(p.Header.SequenceNumber-last) > 1 {
+		c.requestKeyFrame(index)
+	}
+}
+
+// requestKeyFrame sends PLI of video media with limited rate. It is called by receiving goroutine,
+// so the packet is sent asynchronously to not block the session
+func (c *Client) requestKeyFrame(index int) {
+	if !strings.EqualFold(c.desc.Medias[index].Type, "video") {
+		return
+	}
+	if time.Since(c.keyFrameRequested[index]) < keyFrameRequestInterval {
+		return
+	}
+	c.keyFrameRequested[index] = time.Now()
+
+	go func() {
+		_ = c.RequestKeyFrame(index)
+	}()
+}
+
+// feedbackSSRCs returns SSRC of the client and SSRC of the media sender
+func (c *Client) feedbackSSRCs(media int) (uint32, uint32, error) {
+	c.rtcpMu.Lock()
+	defer c.rtcpMu.Unlock()
+
+	if media < 0 || media >= len(c.receivers) {
+		return 0, 0, fmt.Errorf("unknown media: %d", media)
+	}
+	source, ok := c.ssrcs[media]
+	if !ok {
+		return 0, 0, fmt.Errorf("sender of media %d is unknown", media)
+	}
+	return c.receivers[media].SSRC, source, nil
+}
+
+// channel returns RTP channel of the media
+func (c *Client) channel(media int) (uint8, bool) {
+	for channel, index := range c.channels {
+		if index == media {
+			return channel, true
+		}
+	}
+	return 0, false
+}
+
+// report sends RTCP receiver reports of every media, servers may drop sessions without them
+func (c *Client) report(ctx context.Context) {
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for channel, index := range c.channels {
+				c.rtcpMu.Lock()
+				rr := c.receivers[index].Report(time.Now())
+				c.rtcpMu.Unlock()
+
+				packet, err := rtcp.Compose(rr, rtcp.NewCNAME(rr.SSRC, c.CNAME))
+				if err != nil {
+					continue
+				}
+				if err = c.s.WritePacket(channel+1, packet); err != nil {
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
-func (c *Client) do(method rtsp.Method, headers http.Header, body []byte) (*rtsp.Response, error) {
+func (c *Client) do(method rtsp.Method, url *urlpkg.URL, headers http.Header, body []byte) (*rtsp.Response, error) {
+	if headers == nil {
+		headers = make(http.Header)
+	}
+
 	req := rtsp.Request{
 		Method: method,
-		URL:    c.url,
+		URL:    url,
 		Header: headers,
 		Body:   body,
 	}
 
 	req.Header.Add("User-Agent", c.UserAgent)
 
-	return c.s.Do(&req)
+	c.mu.Lock()
+	if c.session != "" {
+		req.Header.Set("Session", c.session)
+	}
+	c.mu.Unlock()
+
+	resp, err := c.s.Do(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != rtsp.Ok {
+		return nil, ErrUnexpectedStatus{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	return resp, nil
+}
+
+// decoder binds decoder to its format
+type decoder struct {
+	*format.Decoder
+	format format.Format
+}
+
+func newDecoder(m *sdp.Media, pt uint8) *decoder {
+	f, err := format.New(m, pt)
+	if err != nil {
+		return nil
+	}
+	d, err := f.NewDecoder()
+	if err != nil {
+		return nil
+	}
+	return &decoder{Decoder: d, format: f}
+}
+
+// randomCNAME generates short-term persistent CNAME (RFC 7022)
+func randomCNAME() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "gortsp"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// controlURL resolves media control attribute against base URL
+func controlURL(base *urlpkg.URL, control string) (*urlpkg.URL, error) {
+	switch {
+	case control == "" || control == "*":
+		return base, nil
+	case strings.HasPrefix(control, "rtsp://"):
+		return urlpkg.Parse(control)
+	}
+
+	u := *base
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	u.RawPath = ""
+	u.Path += control
+
+	return &u, nil
+}
+
+func parsePublic(public string) map[rtsp.Method]bool {
+	methods := map[rtsp.Method]bool{}
+	for _, m := range strings.Split(public, ",") {
+		methods[rtsp.Method(strings.TrimSpace(m))] = true
+	}
+	return methods
+}
+
+// parseSession splits Session header to identifier and timeout
+func parseSession(session string) (string, time.Duration) {
+	timeout := defaultSessionTimeout
+	parts := strings.Split(session, ";")
+	for _, param := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 && kv[0] == "timeout" {
+			if seconds, err := strconv.Atoi(kv[1]); err == nil && seconds > 0 {
+				timeout = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return strings.TrimSpace(parts[0]), timeout
+}
+
+// multicastGroup extracts multicast group from accepted transport (RFC 2326 or RFC 7826 style)
+func multicastGroup(t rtsp.Transport) (rtsp.MulticastGroup, error) {
+	g := rtsp.MulticastGroup{
+		Group:  net.ParseIP(t.Destination),
+		Source: net.ParseIP(t.Source),
+	}
+
+	if t.Port != nil {
+		g.Ports = *t.Port
+	}
+
+	if len(t.DestinationAddresses) != 0 {
+		host, port, err := net.SplitHostPort(t.DestinationAddresses[0])
+		if err != nil {
+			return g, fmt.Errorf("malformed dest_addr: %w", err)
+		}
+		ports, err := rtsp.ParsePortRange(port)
+		if err != nil {
+			return g, fmt.Errorf("malformed dest_addr: %w", err)
+		}
+		g.Group = net.ParseIP(host)
+		g.Ports = rtsp.PortRange{From: ports.From, To: ports.From + 1}
+	}
+	if len(t.SourceAddresses) != 0 && g.Source == nil {
+		host, _, err := net.SplitHostPort(t.SourceAddresses[0])
+		if err != nil {
+			host = t.SourceAddresses[0]
+		}
+		g.Source = net.ParseIP(host)
+	}
+
+	if g.Group == nil || g.Ports.From == 0 {
+		return g, fmt.Errorf("multicast group is not presented: %s", t)
+	}
+	if g.Ports.From == g.Ports.To {
+		g.Ports.To++
+	}
+
+	return g, nil
 }
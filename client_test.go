@@ -0,0 +1,120 @@
+package gortsp
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/rtsp"
+	"github.com/stretchr/testify/assert"
+	"net"
+	urlpkg "net/url"
+	"testing"
+	"time"
+)
+
+func mustParse(rawURL string) *urlpkg.URL {
+	u, err := urlpkg.Parse(rawURL)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestControlURL(t *testing.T) {
+	type testCase struct {
+		base    string
+		control string
+		result  string
+	}
+
+	testCases := []testCase{
+		{
+			base:   "rtsp://127.0.0.1:554/stream",
+			result: "rtsp://127.0.0.1:554/stream",
+		},
+		{
+			base:    "rtsp://127.0.0.1:554/stream/",
+			control: "*",
+			result:  "rtsp://127.0.0.1:554/stream/",
+		},
+		{
+			base:    "rtsp://127.0.0.1:554/stream",
+			control: "trackID=1",
+			result:  "rtsp://127.0.0.1:554/stream/trackID=1",
+		},
+		{
+			base:    "rtsp://127.0.0.1:554/stream/",
+			control: "trackID=1",
+			result:  "rtsp://127.0.0.1:554/stream/trackID=1",
+		},
+		{
+			base:    "rtsp://127.0.0.1:554/stream/",
+			control: "rtsp://10.0.0.1:8554/video",
+			result:  "rtsp://10.0.0.1:8554/video",
+		},
+	}
+
+	for i, c := range testCases {
+		u, err := controlURL(mustParse(c.base), c.control)
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.result, u.String(), "testCase : %d", i+1)
+	}
+}
+
+func TestParseSession(t *testing.T) {
+	id, timeout := parseSession("12345678")
+	assert.Equal(t, "12345678", id)
+	assert.Equal(t, defaultSessionTimeout, timeout)
+
+	id, timeout = parseSession("12345678; timeout=30")
+	assert.Equal(t, "12345678", id)
+	assert.Equal(t, 30*time.Second, timeout)
+}
+
+func TestMulticastGroup(t *testing.T) {
+	type testCase struct {
+		transport string
+		group     rtsp.MulticastGroup
+		err       bool
+	}
+
+	testCases := []testCase{
+		{
+			transport: "RTP/AVP;multicast;destination=224.2.0.1;port=3456-3457;ttl=16",
+			group: rtsp.MulticastGroup{
+				Group: net.ParseIP("224.2.0.1"),
+				Ports: rtsp.PortRange{From: 3456, To: 3457},
+			},
+		},
+		{
+			transport: "RTP/AVP;multicast;destination=224.2.0.1;source=192.168.1.64;port=3456",
+			group: rtsp.MulticastGroup{
+				Group:  net.ParseIP("224.2.0.1"),
+				Ports:  rtsp.PortRange{From: 3456, To: 3457},
+				Source: net.ParseIP("192.168.1.64"),
+			},
+		},
+		{
+			transport: "RTP/AVP/UDP;multicast;dest_addr=\"239.1.1.1:5000\"/\"239.1.1.1:5001\";src_addr=\"192.0.2.1:4000\"",
+			group: rtsp.MulticastGroup{
+				Group:  net.ParseIP("239.1.1.1"),
+				Ports:  rtsp.PortRange{From: 5000, To: 5001},
+				Source: net.ParseIP("192.0.2.1"),
+			},
+		},
+		{
+			transport: "RTP/AVP;multicast;port=3456-3457",
+			err:       true,
+		},
+	}
+
+	for i, c := range testCases {
+		var tr rtsp.Transport
+		assert.NoError(t, tr.Parse(c.transport), "testCase : %d", i+1)
+
+		group, err := multicastGroup(tr)
+		if c.err {
+			assert.Error(t, err, "testCase : %d", i+1)
+			continue
+		}
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.group, group, "testCase : %d", i+1)
+	}
+}
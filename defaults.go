@@ -5,4 +5,17 @@ import "time"
 const (
 	readTimeout  time.Duration = 15 * time.Second
 	writeTimeout time.Duration = 15 * time.Second
+
+	defaultPort           = "554"
+	defaultSessionTimeout = 60 * time.Second
+
+	// reportInterval is a minimal RTCP report interval (RFC 3550 section 6.2)
+	reportInterval = 5 * time.Second
+
+	// minAcceptDelay and maxAcceptDelay limit delay of Accept retries after temporary errors
+	minAcceptDelay = 5 * time.Millisecond
+	maxAcceptDelay = time.Second
+
+	// keyFrameRequestInterval limits rate of PLI requests of the media
+	keyFrameRequestInterval = time.Second
 )
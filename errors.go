@@ -0,0 +1,22 @@
+package gortsp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/racoon-devel/gortsp/pkg/rtsp"
+)
+
+var (
+	errNoMedia   = errors.New("no media streams announced")
+	errNoSession = errors.New("Session header is not presented")
+)
+
+// ErrUnexpectedStatus happens when server replies with non-successful status code
+type ErrUnexpectedStatus struct {
+	StatusCode rtsp.StatusCode
+	Status     string
+}
+
+func (e ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected status: %d %s", e.StatusCode, e.Status)
+}
@@ -1,5 +1,41 @@
 package main
 
+import (
+	"flag"
+	"log"
+
+	"github.com/racoon-devel/gortsp"
+	"github.com/racoon-devel/gortsp/pkg/format"
+)
+
 func main() {
+	url := flag.String("url", "rtsp://127.0.0.1:554/stream", "RTSP stream URL")
+	udp := flag.Bool("udp", false, "receive media over UDP")
+	multicast := flag.Bool("multicast", false, "receive media from multicast group")
+	flag.Parse()
+
+	transport := gortsp.TransportTCP
+	switch {
+	case *udp:
+		transport = gortsp.TransportUDP
+	case *multicast:
+		transport = gortsp.TransportMulticast
+	}
+
+	c := gortsp.Client{
+		UserAgent: "gortsp",
+		Transport: transport,
+		OnFrame: func(media int, f format.Format, frame *format.Frame) {
+			log.Printf("media %d: codec = %s, pts = %s, key = %t, size = %d", media, f.Codec(), frame.PTS, frame.Key, len(frame.Data))
+		},
+	}
+
+	if err := c.Run(*url); err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
 
+	if err := c.Receive(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -1,5 +1,31 @@
 package main
 
+import (
+	"flag"
+	"log"
+
+	"github.com/racoon-devel/gortsp"
+	"github.com/racoon-devel/gortsp/pkg/rtsp"
+)
+
 func main() {
+	addr := flag.String("addr", ":8554", "address to listen on")
+	flag.Parse()
+
+	s := gortsp.Server{
+		Addr:       *addr,
+		ServerName: "gortsp",
+		Handler: gortsp.HandlerFunc(func(w gortsp.ResponseWriter, r *rtsp.Request) {
+			log.Printf("%s: %s %s", w.Conn().RemoteAddr(), r.Method, r.URL)
+
+			switch r.Method {
+			case rtsp.Options:
+				w.Header().Set("Public", "OPTIONS")
+			default:
+				w.WriteHeader(rtsp.MethodNotAllowed)
+			}
+		}),
+	}
 
+	log.Fatal(s.ListenAndServe())
 }
@@ -2,11 +2,13 @@ module github.com/racoon-devel/gortsp
 
 go 1.17
 
-require github.com/stretchr/testify v1.7.1
+require (
+	github.com/jordwest/mock-conn v0.0.0-20180617021051-4896c6bd1641
+	github.com/stretchr/testify v1.7.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/jordwest/mock-conn v0.0.0-20180617021051-4896c6bd1641 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
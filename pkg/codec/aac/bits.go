@@ -0,0 +1,45 @@
+package aac
+
+// bitReader reads MSB-first bit fields
+type bitReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *bitReader) read(n int) (uint32, error) {
+	if r.pos+n > len(r.buf)*8 {
+		return 0, ErrMalformedData{Reason: "unexpected end of data"}
+	}
+
+	var v uint32
+	for i := 0; i < n; i++ {
+		bit := r.buf[r.pos>>3] >> (7 - r.pos&7) & 1
+		v = v<<1 | uint32(bit)
+		r.pos++
+	}
+	return v, nil
+}
+
+func (r *bitReader) skip(n int) error {
+	if r.pos+n > len(r.buf)*8 {
+		return ErrMalformedData{Reason: "unexpected end of data"}
+	}
+	r.pos += n
+	return nil
+}
+
+// bitWriter writes MSB-first bit fields
+type bitWriter struct {
+	buf []byte
+	pos int
+}
+
+func (w *bitWriter) write(v uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.pos&7 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		w.buf[w.pos>>3] |= byte(v>>i&1) << (7 - w.pos&7)
+		w.pos++
+	}
+}
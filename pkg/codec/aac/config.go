@@ -0,0 +1,223 @@
+package aac
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// ObjectType is an MPEG-4 audio object type
+type ObjectType uint8
+
+const (
+	ObjectTypeAACMain ObjectType = 1
+	ObjectTypeAACLC   ObjectType = 2
+	ObjectTypeAACSSR  ObjectType = 3
+	ObjectTypeAACLTP  ObjectType = 4
+	ObjectTypeSBR     ObjectType = 5
+	ObjectTypePS      ObjectType = 29
+)
+
+const (
+	// SamplesPerFrame is a default count of samples in one AAC frame
+	SamplesPerFrame = 1024
+
+	// ShortSamplesPerFrame is a count of samples in one AAC frame if frameLengthFlag is set
+	ShortSamplesPerFrame = 960
+
+	explicitSampleRate = 15
+	escapeObjectType   = 31
+)
+
+var sampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// AudioSpecificConfig represents MPEG-4 AudioSpecificConfig (ISO/IEC 14496-3 section 1.6.2.1)
+type AudioSpecificConfig struct {
+	ObjectType ObjectType
+	SampleRate int
+	Channels   int
+
+	// ExtensionSampleRate is an output sample rate of SBR, 0 if extension isn't signaled explicitly
+	ExtensionSampleRate int
+
+	// FrameLength is a count of samples in one frame: 1024 or 960
+	FrameLength int
+}
+
+// ParseConfig parses AudioSpecificConfig from hex string (config fmtp parameter of mpeg4-generic)
+func ParseConfig(value string) (AudioSpecificConfig, error) {
+	var c AudioSpecificConfig
+	buf, err := hex.DecodeString(value)
+	if err != nil {
+		return c, fmt.Errorf("decode config failed: %w", err)
+	}
+	err = c.Unmarshal(buf)
+	return c, err
+}
+
+// Unmarshal parses AudioSpecificConfig
+func (c *AudioSpecificConfig) Unmarshal(buf []byte) error {
+	r := &bitReader{buf: buf}
+	return c.read(r)
+}
+
+func (c *AudioSpecificConfig) read(r *bitReader) error {
+	objectType, err := readObjectType(r)
+	if err != nil {
+		return err
+	}
+	c.ObjectType = objectType
+
+	if c.SampleRate, err = readSampleRate(r); err != nil {
+		return err
+	}
+
+	channels, err := r.read(4)
+	if err != nil {
+		return err
+	}
+	c.Channels = int(channels)
+	if c.Channels == 7 {
+		c.Channels = 8
+	}
+
+	c.ExtensionSampleRate = 0
+	if c.ObjectType == ObjectTypeSBR || c.ObjectType == ObjectTypePS {
+		if c.ExtensionSampleRate, err = readSampleRate(r); err != nil {
+			return err
+		}
+		if c.ObjectType, err = readObjectType(r); err != nil {
+			return err
+		}
+	}
+
+	c.FrameLength = SamplesPerFrame
+	switch c.ObjectType {
+	case ObjectTypeAACMain, ObjectTypeAACLC, ObjectTypeAACSSR, ObjectTypeAACLTP:
+		// GASpecificConfig
+		frameLengthFlag, err := r.read(1)
+		if err != nil {
+			return err
+		}
+		if frameLengthFlag != 0 {
+			c.FrameLength = ShortSamplesPerFrame
+		}
+
+		dependsOnCoreCoder, err := r.read(1)
+		if err != nil {
+			return err
+		}
+		if dependsOnCoreCoder != 0 {
+			if err = r.skip(14); err != nil {
+				return err
+			}
+		}
+
+		if _, err = r.read(1); err != nil {
+			return err
+		}
+	default:
+		return ErrUnsupportedConfig{Reason: fmt.Sprintf("object type %d", c.ObjectType)}
+	}
+
+	return nil
+}
+
+// Marshal composes AudioSpecificConfig
+func (c AudioSpecificConfig) Marshal() ([]byte, error) {
+	w := &bitWriter{}
+	if err := c.write(w); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+func (c AudioSpecificConfig) write(w *bitWriter) error {
+	switch c.ObjectType {
+	case ObjectTypeAACMain, ObjectTypeAACLC, ObjectTypeAACSSR, ObjectTypeAACLTP:
+	default:
+		return ErrUnsupportedConfig{Reason: fmt.Sprintf("object type %d", c.ObjectType)}
+	}
+	if c.Channels < 0 || c.Channels > 8 || c.Channels == 7 {
+		return ErrUnsupportedConfig{Reason: fmt.Sprintf("channels %d", c.Channels)}
+	}
+
+	if c.ExtensionSampleRate != 0 {
+		w.write(uint32(ObjectTypeSBR), 5)
+	} else {
+		w.write(uint32(c.ObjectType), 5)
+	}
+	writeSampleRate(w, c.SampleRate)
+
+	channels := c.Channels
+	if channels == 8 {
+		channels = 7
+	}
+	w.write(uint32(channels), 4)
+
+	if c.ExtensionSampleRate != 0 {
+		writeSampleRate(w, c.ExtensionSampleRate)
+		w.write(uint32(c.ObjectType), 5)
+	}
+
+	// GASpecificConfig
+	if c.FrameLength == ShortSamplesPerFrame {
+		w.write(1, 1)
+	} else {
+		w.write(0, 1)
+	}
+	w.write(0, 2)
+
+	return nil
+}
+
+// String returns config in hex format used by fmtp
+func (c AudioSpecificConfig) String() string {
+	buf, _ := c.Marshal()
+	return hex.EncodeToString(buf)
+}
+
+// ClockRate returns RTP clock rate of the stream
+func (c AudioSpecificConfig) ClockRate() int {
+	return c.SampleRate
+}
+
+func readObjectType(r *bitReader) (ObjectType, error) {
+	v, err := r.read(5)
+	if err != nil {
+		return 0, err
+	}
+	if v == escapeObjectType {
+		ext, err := r.read(6)
+		if err != nil {
+			return 0, err
+		}
+		v = 32 + ext
+	}
+	return ObjectType(v), nil
+}
+
+func readSampleRate(r *bitReader) (int, error) {
+	index, err := r.read(4)
+	if err != nil {
+		return 0, err
+	}
+	if index == explicitSampleRate {
+		rate, err := r.read(24)
+		return int(rate), err
+	}
+	if int(index) >= len(sampleRates) {
+		return 0, ErrMalformedData{Reason: fmt.Sprintf("sample rate index %d", index)}
+	}
+	return sampleRates[index], nil
+}
+
+func writeSampleRate(w *bitWriter, rate int) {
+	for i, r := range sampleRates {
+		if r == rate {
+			w.write(uint32(i), 4)
+			return
+		}
+	}
+	w.write(explicitSampleRate, 4)
+	w.write(uint32(rate), 24)
+}
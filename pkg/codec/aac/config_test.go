@@ -0,0 +1,67 @@
+package aac
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	type testCase struct {
+		value  string
+		config AudioSpecificConfig
+		err    bool
+	}
+
+	testCases := []testCase{
+		{
+			value:  "1210",
+			config: AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 44100, Channels: 2, FrameLength: 1024},
+		},
+		{
+			value:  "118c",
+			config: AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 48000, Channels: 1, FrameLength: 960},
+		},
+		{
+			value:  "2b098800",
+			config: AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 24000, Channels: 1, ExtensionSampleRate: 48000, FrameLength: 1024},
+		},
+		{
+			value:  "17801f4010",
+			config: AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 16000, Channels: 2, FrameLength: 1024},
+		},
+		{
+			value: "12",
+			err:   true,
+		},
+		{
+			value: "zz",
+			err:   true,
+		},
+	}
+
+	for i, c := range testCases {
+		config, err := ParseConfig(c.value)
+		if c.err {
+			assert.Error(t, err, "testCase : %d", i+1)
+			continue
+		}
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.config, config, "testCase : %d", i+1)
+	}
+}
+
+func TestAudioSpecificConfig_Marshal(t *testing.T) {
+	c := AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 44100, Channels: 2}
+	assert.Equal(t, "1210", c.String())
+
+	c = AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 16000, Channels: 2}
+	buf, err := c.Marshal()
+	assert.NoError(t, err)
+
+	var parsed AudioSpecificConfig
+	assert.NoError(t, parsed.Unmarshal(buf))
+	assert.Equal(t, AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 16000, Channels: 2, FrameLength: 1024}, parsed)
+
+	_, err = AudioSpecificConfig{ObjectType: ObjectTypePS}.Marshal()
+	assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package aac
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+// MaxAccessUnitSize limits memory consumed by fragmented access unit
+const MaxAccessUnitSize = 64 * 1024
+
+// Depacketizer extracts AAC access units from mpeg4-generic RTP packets (RFC 3640).
+// Output frames contain raw AAC data without ADTS headers
+type Depacketizer struct {
+	Config AudioSpecificConfig
+
+	// AU-header fields lengths in bits
+	SizeLength       int
+	IndexLength      int
+	IndexDeltaLength int
+
+	// AuxiliaryDataSizeLength is a length of auxiliary section size in bits, the section is skipped
+	AuxiliaryDataSizeLength int
+
+	// fragmented access unit
+	fragments []byte
+	size      int
+	timestamp uint32
+	fragment  bool
+	broken    bool
+
+	// lost is set if packets are lost since the last packet with marker bit
+	lost    bool
+	seq     uint16
+	started bool
+}
+
+// NewDepacketizer creates depacketizer with parameters from SDP fmtp
+func NewDepacketizer(fmtp sdp.FMTP) (*Depacketizer, error) {
+	if mode, ok := fmtp.Get("mode"); ok && !strings.EqualFold(mode, "AAC-hbr") && !strings.EqualFold(mode, "AAC-lbr") {
+		return nil, ErrUnsupportedConfig{Reason: "mode " + mode}
+	}
+
+	value, ok := fmtp.Get("config")
+	if !ok {
+		return nil, ErrUnsupportedConfig{Reason: "config is absent"}
+	}
+	config, err := ParseConfig(value)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Depacketizer{Config: config}
+	d.SizeLength, _ = fmtp.Int("sizelength")
+	d.IndexLength, _ = fmtp.Int("indexlength")
+	d.IndexDeltaLength, _ = fmtp.Int("indexdeltalength")
+	d.AuxiliaryDataSizeLength, _ = fmtp.Int("auxiliarydatasizelength")
+
+	if d.SizeLength <= 0 || d.SizeLength > 32 || d.IndexLength < 0 || d.IndexLength > 32 ||
+		d.IndexDeltaLength < 0 || d.IndexDeltaLength > 32 {
+		return nil, ErrUnsupportedConfig{Reason: "invalid AU-header lengths"}
+	}
+	return d, nil
+}
+
+// Depacketize processes RTP packet and returns access units completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	if d.started && p.Header.SequenceNumber != d.seq+1 {
+		d.lost = true
+	}
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+	if p.Header.Marker {
+		defer func() { d.lost = false }()
+	}
+
+	if d.fragment && d.timestamp != p.Header.Timestamp {
+		// the rest of the previous access unit is lost
+		d.resetFragment()
+	}
+
+	sizes, indexes, data, err := d.parseHeaders(p.Payload)
+	if err != nil {
+		d.resetFragment()
+		return nil, err
+	}
+
+	// AU is fragmented if it is the only one and doesn't fit into packet
+	if len(sizes) == 1 && (sizes[0] > len(data) || d.fragment) {
+		return d.appendFragment(p, sizes[0], data)
+	}
+	d.resetFragment()
+
+	frameLength := uint32(d.Config.FrameLength)
+	if frameLength == 0 {
+		frameLength = SamplesPerFrame
+	}
+
+	frames := make([]codec.Frame, 0, len(sizes))
+	for i, size := range sizes {
+		if size > len(data) {
+			return frames, ErrMalformedData{Reason: "access unit is truncated"}
+		}
+
+		au := make([]byte, size)
+		copy(au, data)
+		data = data[size:]
+
+		frames = append(frames, codec.Frame{
+			Timestamp: p.Header.Timestamp + indexes[i]*frameLength,
+			Data:      au,
+			Key:       true,
+		})
+	}
+
+	return frames, nil
+}
+
+// parseHeaders parses AU-headers and auxiliary sections. Indexes are counted from the first access unit
+func (d *Depacketizer) parseHeaders(payload []byte) (sizes []int, indexes []uint32, data []byte, err error) {
+	if len(payload) < 2 {
+		return nil, nil, nil, ErrMalformedData{Reason: "AU-headers-length is truncated"}
+	}
+	headersLength := int(binary.BigEndian.Uint16(payload))
+	headersSize := (headersLength + 7) / 8
+	payload = payload[2:]
+	if headersSize > len(payload) {
+		return nil, nil, nil, ErrMalformedData{Reason: "AU-headers are truncated"}
+	}
+
+	r := &bitReader{buf: payload[:headersSize]}
+	var index uint32
+	for first := true; r.pos < headersLength; first = false {
+		size, err := r.read(d.SizeLength)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if first {
+			// AU-index of the first unit is the base for the packet timestamp
+			if _, err = r.read(d.IndexLength); err != nil {
+				return nil, nil, nil, err
+			}
+		} else {
+			delta, err := r.read(d.IndexDeltaLength)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			index += delta + 1
+		}
+
+		sizes = append(sizes, int(size))
+		indexes = append(indexes, index)
+	}
+	if len(sizes) == 0 {
+		return nil, nil, nil, ErrMalformedData{Reason: "no AU-headers"}
+	}
+	payload = payload[headersSize:]
+
+	if d.AuxiliaryDataSizeLength > 0 {
+		r = &bitReader{buf: payload}
+		auxSize, err := r.read(d.AuxiliaryDataSizeLength)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		skip := (d.AuxiliaryDataSizeLength + int(auxSize) + 7) / 8
+		if skip > len(payload) {
+			return nil, nil, nil, ErrMalformedData{Reason: "auxiliary section is truncated"}
+		}
+		payload = payload[skip:]
+	}
+
+	return sizes, indexes, payload, nil
+}
+
+func (d *Depacketizer) appendFragment(p *rtp.Packet, size int, data []byte) ([]codec.Frame, error) {
+	if size > MaxAccessUnitSize {
+		d.resetFragment()
+		return nil, ErrAccessUnitTooLarge{Size: size}
+	}
+
+	switch {
+	case !d.fragment:
+		// the first fragment may be lost
+		d.fragment = true
+		d.broken = d.lost
+		d.timestamp = p.Header.Timestamp
+		d.size = size
+		d.fragments = d.fragments[:0]
+	case d.lost || d.size != size:
+		d.broken = true
+	}
+
+	d.fragments = append(d.fragments, data...)
+	if len(d.fragments) > d.size {
+		d.resetFragment()
+		return nil, ErrMalformedData{Reason: fmt.Sprintf("fragments exceed access unit size %d", size)}
+	}
+
+	if !p.Header.Marker {
+		return nil, nil
+	}
+
+	if d.broken || len(d.fragments) != d.size {
+		d.resetFragment()
+		return nil, nil
+	}
+
+	au := make([]byte, len(d.fragments))
+	copy(au, d.fragments)
+	d.resetFragment()
+
+	return []codec.Frame{{Timestamp: p.Header.Timestamp, Data: au, Key: true}}, nil
+}
+
+func (d *Depacketizer) resetFragment() {
+	d.fragments = d.fragments[:0]
+	d.fragment = false
+	d.broken = false
+	d.size = 0
+}
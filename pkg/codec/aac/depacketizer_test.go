@@ -0,0 +1,149 @@
+package aac
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testPacket(seq uint16, ts uint32, marker bool, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Marker:         marker,
+			PayloadType:    97,
+			SequenceNumber: seq,
+			Timestamp:      ts,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDepacketizer_Depacketize(t *testing.T) {
+	type testCase struct {
+		packets []*rtp.Packet
+		frames  []codec.Frame
+	}
+
+	testCases := []testCase{
+		// several AUs in one packet, the second has index delta 1
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 1000, true, 0x00, 0x20, 0x00, 0x10, 0x00, 0x09, 0x01, 0x02, 0x03),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 1000, Data: []byte{0x01, 0x02}, Key: true},
+				{Timestamp: 1000 + 2*1024, Data: []byte{0x03}, Key: true},
+			},
+		},
+		// fragmented AU
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 1000, false, 0x00, 0x10, 0x00, 0x20, 0x01, 0x02),
+				testPacket(2, 1000, false, 0x00, 0x10, 0x00, 0x20, 0x03),
+				testPacket(3, 1000, true, 0x00, 0x10, 0x00, 0x20, 0x04),
+				testPacket(4, 2024, true, 0x00, 0x10, 0x00, 0x08, 0x05),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 1000, Data: []byte{0x01, 0x02, 0x03, 0x04}, Key: true},
+				{Timestamp: 2024, Data: []byte{0x05}, Key: true},
+			},
+		},
+		// lost fragment drops AU
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 1000, false, 0x00, 0x10, 0x00, 0x20, 0x01, 0x02),
+				testPacket(3, 1000, true, 0x00, 0x10, 0x00, 0x20, 0x04),
+				testPacket(4, 2024, false, 0x00, 0x10, 0x00, 0x18, 0x05),
+				testPacket(5, 2024, true, 0x00, 0x10, 0x00, 0x18, 0x06, 0x07),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 2024, Data: []byte{0x05, 0x06, 0x07}, Key: true},
+			},
+		},
+		// lost first fragment
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 1000, true, 0x00, 0x10, 0x00, 0x08, 0x01),
+				testPacket(3, 2024, true, 0x00, 0x10, 0x00, 0x18, 0x03),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 1000, Data: []byte{0x01}, Key: true},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		d := &Depacketizer{
+			Config:           AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 44100, Channels: 2, FrameLength: 1024},
+			SizeLength:       HBRSizeLength,
+			IndexLength:      HBRIndexLength,
+			IndexDeltaLength: HBRIndexDeltaLength,
+		}
+		var frames []codec.Frame
+		for _, p := range c.packets {
+			result, err := d.Depacketize(p)
+			assert.NoError(t, err, "testCase : %d", i+1)
+			frames = append(frames, result...)
+		}
+		assert.Equal(t, c.frames, frames, "testCase : %d", i+1)
+	}
+}
+
+func TestNewDepacketizer(t *testing.T) {
+	fmtp := sdp.ParseFMTP("streamtype=5; profile-level-id=15; mode=AAC-hbr; config=1210; SizeLength=13; IndexLength=3; IndexDeltaLength=3")
+	d, err := NewDepacketizer(fmtp)
+	assert.NoError(t, err)
+	assert.Equal(t, 44100, d.Config.SampleRate)
+	assert.Equal(t, HBRSizeLength, d.SizeLength)
+	assert.Equal(t, HBRIndexLength, d.IndexLength)
+	assert.Equal(t, HBRIndexDeltaLength, d.IndexDeltaLength)
+
+	_, err = NewDepacketizer(sdp.ParseFMTP("mode=CELP-cbr;config=1210;sizelength=6"))
+	assert.IsType(t, ErrUnsupportedConfig{}, err)
+
+	_, err = NewDepacketizer(sdp.ParseFMTP("mode=AAC-hbr;config=1210"))
+	assert.IsType(t, ErrUnsupportedConfig{}, err)
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	config := AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 48000, Channels: 2, FrameLength: 1024}
+	p := NewPacketizer(97, config)
+	p.MTU = 100
+
+	d, err := NewDepacketizer(p.FMTP())
+	assert.NoError(t, err)
+
+	for i, size := range []int{10, 300, 88} {
+		au := make([]byte, size)
+		for j := range au {
+			au[j] = byte(i + j)
+		}
+
+		packets, err := p.Packetize(codec.Frame{Timestamp: uint32(i * 1024), Data: au})
+		assert.NoError(t, err)
+		assert.Len(t, packets, (size+83)/84)
+
+		var frames []codec.Frame
+		for j := range packets {
+			assert.True(t, packets[j].Size() <= p.MTU)
+			result, err := d.Depacketize(&packets[j])
+			assert.NoError(t, err)
+			frames = append(frames, result...)
+		}
+
+		if assert.Len(t, frames, 1) {
+			assert.Equal(t, p.InitialTimestamp+uint32(i*1024), frames[0].Timestamp)
+			assert.Equal(t, au, frames[0].Data)
+		}
+	}
+
+	_, err = p.Packetize(codec.Frame{Data: make([]byte, 1<<13)})
+	assert.IsType(t, ErrAccessUnitTooLarge{}, err)
+
+	// size limit of the widest field doesn't overflow
+	p.SizeLength = 32
+	_, err = p.Packetize(codec.Frame{Data: make([]byte, 1<<13)})
+	assert.NoError(t, err)
+}
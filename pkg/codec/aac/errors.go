@@ -0,0 +1,30 @@
+package aac
+
+import "fmt"
+
+// ErrMalformedData happens when payload or configuration cannot be parsed
+type ErrMalformedData struct {
+	Reason string
+}
+
+func (e ErrMalformedData) Error() string {
+	return fmt.Sprintf("malformed AAC data: %s", e.Reason)
+}
+
+// ErrUnsupportedConfig happens when stream uses features which are not supported
+type ErrUnsupportedConfig struct {
+	Reason string
+}
+
+func (e ErrUnsupportedConfig) Error() string {
+	return fmt.Sprintf("unsupported AAC configuration: %s", e.Reason)
+}
+
+// ErrAccessUnitTooLarge happens when access unit cannot be described by AU-header
+type ErrAccessUnitTooLarge struct {
+	Size int
+}
+
+func (e ErrAccessUnitTooLarge) Error() string {
+	return fmt.Sprintf("access unit too large: %d", e.Size)
+}
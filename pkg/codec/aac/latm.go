@@ -0,0 +1,354 @@
+package aac
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+const latmBufferFullnessVBR = 0xFF
+
+// StreamMuxConfig represents LATM StreamMuxConfig (ISO/IEC 14496-3 section 1.7.3). Only audioMuxVersion 0
+// with single program and layer and frameLengthType 0 is supported
+type StreamMuxConfig struct {
+	// SubFrames is a count of PayloadMux elements in one AudioMuxElement
+	SubFrames int
+
+	Config AudioSpecificConfig
+
+	// OtherDataLenBits is a length of other data in AudioMuxElement
+	OtherDataLenBits int
+}
+
+// ParseStreamMuxConfig parses StreamMuxConfig from hex string (config fmtp parameter of MP4A-LATM)
+func ParseStreamMuxConfig(value string) (StreamMuxConfig, error) {
+	var c StreamMuxConfig
+	buf, err := hex.DecodeString(value)
+	if err != nil {
+		return c, fmt.Errorf("decode config failed: %w", err)
+	}
+	err = c.Unmarshal(buf)
+	return c, err
+}
+
+// Unmarshal parses StreamMuxConfig
+func (c *StreamMuxConfig) Unmarshal(buf []byte) error {
+	r := &bitReader{buf: buf}
+
+	audioMuxVersion, err := r.read(1)
+	if err != nil {
+		return err
+	}
+	if audioMuxVersion != 0 {
+		return ErrUnsupportedConfig{Reason: "audioMuxVersion 1"}
+	}
+
+	allStreamsSameTimeFraming, err := r.read(1)
+	if err != nil {
+		return err
+	}
+	if allStreamsSameTimeFraming != 1 {
+		return ErrUnsupportedConfig{Reason: "allStreamsSameTimeFraming 0"}
+	}
+
+	subFrames, err := r.read(6)
+	if err != nil {
+		return err
+	}
+	c.SubFrames = int(subFrames) + 1
+
+	programs, err := r.read(4)
+	if err != nil {
+		return err
+	}
+	layers, err := r.read(3)
+	if err != nil {
+		return err
+	}
+	if programs != 0 || layers != 0 {
+		return ErrUnsupportedConfig{Reason: "multiple programs or layers"}
+	}
+
+	if err = c.Config.read(r); err != nil {
+		return err
+	}
+
+	frameLengthType, err := r.read(3)
+	if err != nil {
+		return err
+	}
+	if frameLengthType != 0 {
+		return ErrUnsupportedConfig{Reason: fmt.Sprintf("frameLengthType %d", frameLengthType)}
+	}
+	// latmBufferFullness
+	if err = r.skip(8); err != nil {
+		return err
+	}
+
+	otherDataPresent, err := r.read(1)
+	if err != nil {
+		return err
+	}
+	c.OtherDataLenBits = 0
+	if otherDataPresent != 0 {
+		for {
+			esc, err := r.read(1)
+			if err != nil {
+				return err
+			}
+			tmp, err := r.read(8)
+			if err != nil {
+				return err
+			}
+			c.OtherDataLenBits = c.OtherDataLenBits<<8 + int(tmp)
+			if esc == 0 {
+				break
+			}
+		}
+	}
+
+	crcCheckPresent, err := r.read(1)
+	if err != nil {
+		return err
+	}
+	if crcCheckPresent != 0 {
+		return r.skip(8)
+	}
+	return nil
+}
+
+// Marshal composes StreamMuxConfig
+func (c StreamMuxConfig) Marshal() ([]byte, error) {
+	if c.SubFrames < 1 || c.SubFrames > 64 {
+		return nil, ErrUnsupportedConfig{Reason: fmt.Sprintf("%d subframes", c.SubFrames)}
+	}
+	if c.OtherDataLenBits < 0 || c.OtherDataLenBits > 0xFF {
+		return nil, ErrUnsupportedConfig{Reason: fmt.Sprintf("other data length %d", c.OtherDataLenBits)}
+	}
+
+	w := &bitWriter{}
+	w.write(0, 1)
+	w.write(1, 1)
+	w.write(uint32(c.SubFrames-1), 6)
+	w.write(0, 4)
+	w.write(0, 3)
+	if err := c.Config.write(w); err != nil {
+		return nil, err
+	}
+	w.write(0, 3)
+	w.write(latmBufferFullnessVBR, 8)
+	if c.OtherDataLenBits != 0 {
+		w.write(1, 1)
+		w.write(0, 1)
+		w.write(uint32(c.OtherDataLenBits), 8)
+	} else {
+		w.write(0, 1)
+	}
+	w.write(0, 1)
+
+	return w.buf, nil
+}
+
+// String returns config in hex format used by fmtp
+func (c StreamMuxConfig) String() string {
+	buf, _ := c.Marshal()
+	return hex.EncodeToString(buf)
+}
+
+// LATMDepacketizer extracts AAC access units from MP4A-LATM RTP packets (RFC 6416) with in-band configuration
+// disabled (cpresent=0). Output is the same as Depacketizer output
+type LATMDepacketizer struct {
+	StreamMuxConfig
+
+	// AudioMuxElement may be fragmented to several packets
+	buf       []byte
+	timestamp uint32
+	fragment  bool
+	broken    bool
+
+	// lost is set if packets are lost since the last packet with marker bit
+	lost    bool
+	seq     uint16
+	started bool
+}
+
+// NewLATMDepacketizer creates depacketizer with parameters from SDP fmtp
+func NewLATMDepacketizer(fmtp sdp.FMTP) (*LATMDepacketizer, error) {
+	if cpresent, ok := fmtp.Int("cpresent"); !ok || cpresent != 0 {
+		return nil, ErrUnsupportedConfig{Reason: "in-band StreamMuxConfig"}
+	}
+
+	value, ok := fmtp.Get("config")
+	if !ok {
+		return nil, ErrUnsupportedConfig{Reason: "config is absent"}
+	}
+	config, err := ParseStreamMuxConfig(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LATMDepacketizer{StreamMuxConfig: config}, nil
+}
+
+// Depacketize processes RTP packet and returns access units completed by it
+func (d *LATMDepacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	if d.started && p.Header.SequenceNumber != d.seq+1 {
+		d.lost = true
+	}
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+	if p.Header.Marker {
+		defer func() { d.lost = false }()
+	}
+
+	switch {
+	case !d.fragment || d.timestamp != p.Header.Timestamp:
+		// the first fragment may be lost
+		d.fragment = true
+		d.broken = d.lost
+		d.timestamp = p.Header.Timestamp
+		d.buf = d.buf[:0]
+	case d.lost:
+		d.broken = true
+	}
+
+	d.buf = append(d.buf, p.Payload...)
+	if len(d.buf) > MaxAccessUnitSize {
+		d.broken = true
+		d.buf = d.buf[:0]
+	}
+
+	if !p.Header.Marker {
+		return nil, nil
+	}
+
+	d.fragment = false
+	if d.broken {
+		return nil, nil
+	}
+
+	return d.parseElements(d.buf)
+}
+
+// parseElements parses AudioMuxElements with muxConfigPresent = 0
+func (d *LATMDepacketizer) parseElements(buf []byte) ([]codec.Frame, error) {
+	frameLength := uint32(d.Config.FrameLength)
+	if frameLength == 0 {
+		frameLength = SamplesPerFrame
+	}
+
+	var frames []codec.Frame
+	for len(buf) != 0 {
+		for i := 0; i < d.SubFrames; i++ {
+			// PayloadLengthInfo
+			size := 0
+			for {
+				if len(buf) == 0 {
+					return frames, ErrMalformedData{Reason: "PayloadLengthInfo is truncated"}
+				}
+				tmp := buf[0]
+				buf = buf[1:]
+				size += int(tmp)
+				if tmp != 0xFF {
+					break
+				}
+			}
+
+			// PayloadMux
+			if size > len(buf) {
+				return frames, ErrMalformedData{Reason: "PayloadMux is truncated"}
+			}
+			au := make([]byte, size)
+			copy(au, buf)
+			buf = buf[size:]
+
+			frames = append(frames, codec.Frame{
+				Timestamp: d.timestamp + uint32(len(frames))*frameLength,
+				Data:      au,
+				Key:       true,
+			})
+		}
+
+		otherData := (d.OtherDataLenBits + 7) / 8
+		if otherData > len(buf) {
+			return frames, ErrMalformedData{Reason: "other data is truncated"}
+		}
+		buf = buf[otherData:]
+	}
+
+	return frames, nil
+}
+
+// LATMPacketizer makes MP4A-LATM RTP packets (RFC 6416) from AAC access units. Every access unit is sent
+// in a separate AudioMuxElement which is fragmented if it doesn't fit into MTU
+type LATMPacketizer struct {
+	codec.Sequencer
+
+	Config AudioSpecificConfig
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewLATMPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewLATMPacketizer(payloadType uint8, config AudioSpecificConfig) *LATMPacketizer {
+	return &LATMPacketizer{
+		Sequencer: codec.NewSequencer(payloadType),
+		Config:    config,
+	}
+}
+
+// FMTP returns format parameters of the stream for SDP
+func (p *LATMPacketizer) FMTP() sdp.FMTP {
+	return sdp.FMTP{
+		"profile-level-id": "30",
+		"object":           strconv.Itoa(int(p.Config.ObjectType)),
+		"cpresent":         "0",
+		"config":           StreamMuxConfig{SubFrames: 1, Config: p.Config}.String(),
+	}
+}
+
+// Packetize makes RTP packets from AAC access unit without ADTS header. Frame timestamp is counted in sample
+// rate units from the stream start. Returned packets are valid until the next call
+func (p *LATMPacketizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+	maxPayload := mtu - rtp.HeaderLength
+	if maxPayload <= 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	// AudioMuxElement: PayloadLengthInfo and PayloadMux
+	p.buf = p.buf[:0]
+	size := len(f.Data)
+	for ; size >= 0xFF; size -= 0xFF {
+		p.buf = append(p.buf, 0xFF)
+	}
+	p.buf = append(p.buf, byte(size))
+	p.buf = append(p.buf, f.Data...)
+
+	p.packets = p.packets[:0]
+	for data := p.buf; len(data) != 0; {
+		n := maxPayload
+		if n > len(data) {
+			n = len(data)
+		}
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(f.Timestamp, n == len(data)),
+			Payload: data[:n:n],
+		})
+		data = data[n:]
+	}
+
+	return p.packets, nil
+}
@@ -0,0 +1,122 @@
+package aac
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseStreamMuxConfig(t *testing.T) {
+	c, err := ParseStreamMuxConfig("400026103fc0")
+	assert.NoError(t, err)
+	assert.Equal(t, StreamMuxConfig{
+		SubFrames: 1,
+		Config:    AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 24000, Channels: 1, FrameLength: 1024},
+	}, c)
+	assert.Equal(t, "400026103fc0", c.String())
+
+	c.SubFrames = 2
+	c.OtherDataLenBits = 8
+	buf, err := c.Marshal()
+	assert.NoError(t, err)
+
+	var parsed StreamMuxConfig
+	assert.NoError(t, parsed.Unmarshal(buf))
+	assert.Equal(t, c, parsed)
+
+	_, err = ParseStreamMuxConfig("c00026103fc0")
+	assert.IsType(t, ErrUnsupportedConfig{}, err)
+
+	_, err = ParseStreamMuxConfig("4000")
+	assert.IsType(t, ErrMalformedData{}, err)
+}
+
+func TestLATMDepacketizer_Depacketize(t *testing.T) {
+	type testCase struct {
+		config  StreamMuxConfig
+		packets []*rtp.Packet
+		frames  []codec.Frame
+	}
+
+	config := AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 24000, Channels: 1, FrameLength: 1024}
+
+	testCases := []testCase{
+		// two subframes with other data
+		{
+			config: StreamMuxConfig{SubFrames: 2, Config: config, OtherDataLenBits: 8},
+			packets: []*rtp.Packet{
+				testPacket(1, 1000, true, 0x02, 0x01, 0x02, 0x01, 0x03, 0xee),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 1000, Data: []byte{0x01, 0x02}, Key: true},
+				{Timestamp: 2024, Data: []byte{0x03}, Key: true},
+			},
+		},
+		// fragmented AudioMuxElement, lost fragment drops it
+		{
+			config: StreamMuxConfig{SubFrames: 1, Config: config},
+			packets: []*rtp.Packet{
+				testPacket(1, 1000, false, 0x03, 0x01),
+				testPacket(2, 1000, true, 0x02, 0x03),
+				testPacket(3, 2024, false, 0x02, 0x04),
+				testPacket(5, 3048, false, 0x02),
+				testPacket(6, 3048, true, 0x05, 0x06),
+				testPacket(7, 4072, true, 0x01, 0x07),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 1000, Data: []byte{0x01, 0x02, 0x03}, Key: true},
+				{Timestamp: 4072, Data: []byte{0x07}, Key: true},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		d := &LATMDepacketizer{StreamMuxConfig: c.config}
+		var frames []codec.Frame
+		for _, p := range c.packets {
+			result, err := d.Depacketize(p)
+			assert.NoError(t, err, "testCase : %d", i+1)
+			frames = append(frames, result...)
+		}
+		assert.Equal(t, c.frames, frames, "testCase : %d", i+1)
+	}
+}
+
+func TestLATMPacketizer_RoundTrip(t *testing.T) {
+	config := AudioSpecificConfig{ObjectType: ObjectTypeAACLC, SampleRate: 48000, Channels: 2, FrameLength: 1024}
+	p := NewLATMPacketizer(96, config)
+	p.MTU = 100
+
+	fmtp := sdp.ParseFMTP(p.FMTP().String())
+	d, err := NewLATMDepacketizer(fmtp)
+	assert.NoError(t, err)
+	assert.Equal(t, config, d.Config)
+
+	for i, size := range []int{10, 600, 255} {
+		au := make([]byte, size)
+		for j := range au {
+			au[j] = byte(i + j)
+		}
+
+		packets, err := p.Packetize(codec.Frame{Timestamp: uint32(i * 1024), Data: au})
+		assert.NoError(t, err)
+
+		var frames []codec.Frame
+		for j := range packets {
+			assert.True(t, packets[j].Size() <= p.MTU)
+			result, err := d.Depacketize(&packets[j])
+			assert.NoError(t, err)
+			frames = append(frames, result...)
+		}
+
+		if assert.Len(t, frames, 1) {
+			assert.Equal(t, p.InitialTimestamp+uint32(i*1024), frames[0].Timestamp)
+			assert.Equal(t, au, frames[0].Data)
+		}
+	}
+
+	_, err = NewLATMDepacketizer(sdp.ParseFMTP("cpresent=1"))
+	assert.IsType(t, ErrUnsupportedConfig{}, err)
+}
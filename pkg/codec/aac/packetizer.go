@@ -0,0 +1,109 @@
+package aac
+
+import (
+	"strconv"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+// AAC-hbr mode AU-header fields lengths (RFC 3640 section 3.3.6)
+const (
+	HBRSizeLength       = 13
+	HBRIndexLength      = 3
+	HBRIndexDeltaLength = 3
+)
+
+// Packetizer makes mpeg4-generic RTP packets (RFC 3640) from AAC access units. Every access unit is sent
+// in a separate packet and is fragmented if it doesn't fit into MTU
+type Packetizer struct {
+	codec.Sequencer
+
+	Config AudioSpecificConfig
+
+	// AU-header fields lengths in bits
+	SizeLength  int
+	IndexLength int
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates AAC-hbr packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer(payloadType uint8, config AudioSpecificConfig) *Packetizer {
+	return &Packetizer{
+		Sequencer:   codec.NewSequencer(payloadType),
+		Config:      config,
+		SizeLength:  HBRSizeLength,
+		IndexLength: HBRIndexLength,
+	}
+}
+
+// FMTP returns format parameters of the stream for SDP
+func (p *Packetizer) FMTP() sdp.FMTP {
+	return sdp.FMTP{
+		"streamtype":       "5",
+		"profile-level-id": "1",
+		"mode":             "AAC-hbr",
+		"sizelength":       strconv.Itoa(p.SizeLength),
+		"indexlength":      strconv.Itoa(p.IndexLength),
+		"indexdeltalength": strconv.Itoa(HBRIndexDeltaLength),
+		"config":           p.Config.String(),
+	}
+}
+
+// Packetize makes RTP packets from AAC access unit without ADTS header. Frame timestamp is counted in sample
+// rate units from the stream start. Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+
+	headersLength := p.SizeLength + p.IndexLength
+	headersSize := 2 + (headersLength+7)/8
+	maxPayload := mtu - rtp.HeaderLength - headersSize
+	if maxPayload <= 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+	if p.SizeLength <= 0 || p.SizeLength > 32 || uint64(len(f.Data)) >= 1<<uint64(p.SizeLength) {
+		return nil, ErrAccessUnitTooLarge{Size: len(f.Data)}
+	}
+
+	count := (len(f.Data) + maxPayload - 1) / maxPayload
+	size := len(f.Data) + count*headersSize
+	if cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	data := f.Data
+	for len(data) != 0 {
+		n := maxPayload
+		if n > len(data) {
+			n = len(data)
+		}
+
+		start := len(p.buf)
+		w := &bitWriter{}
+		w.write(uint32(headersLength), 16)
+		w.write(uint32(len(f.Data)), p.SizeLength)
+		w.write(0, p.IndexLength)
+		p.buf = append(p.buf, w.buf...)
+		p.buf = append(p.buf, data[:n]...)
+		data = data[n:]
+
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(f.Timestamp, len(data) == 0),
+			Payload: p.buf[start:len(p.buf):len(p.buf)],
+		})
+	}
+
+	return p.packets, nil
+}
@@ -0,0 +1,138 @@
+package av1
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLEB128(t *testing.T) {
+	type testCase struct {
+		value uint64
+		buf   []byte
+	}
+
+	testCases := []testCase{
+		{value: 0, buf: []byte{0x00}},
+		{value: 127, buf: []byte{0x7f}},
+		{value: 128, buf: []byte{0x80, 0x01}},
+		{value: 624485, buf: []byte{0xe5, 0x8e, 0x26}},
+	}
+
+	for i, c := range testCases {
+		assert.Equal(t, c.buf, AppendLEB128(nil, c.value), "testCase : %d", i+1)
+		assert.Equal(t, len(c.buf), LEB128Size(c.value), "testCase : %d", i+1)
+
+		value, n, err := ReadLEB128(c.buf)
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.value, value, "testCase : %d", i+1)
+		assert.Equal(t, len(c.buf), n, "testCase : %d", i+1)
+	}
+
+	_, _, err := ReadLEB128([]byte{0x80})
+	assert.Error(t, err)
+}
+
+func TestAggregationHeader(t *testing.T) {
+	h := ParseAggregationHeader(0xe8)
+	assert.Equal(t, AggregationHeader{Z: true, Y: true, W: 2, N: true}, h)
+	assert.Equal(t, byte(0xe8), h.Byte())
+}
+
+func testPacket(seq uint16, ts uint32, marker bool, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Marker:         marker,
+			PayloadType:    96,
+			SequenceNumber: seq,
+			Timestamp:      ts,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDepacketizer_Depacketize(t *testing.T) {
+	type testCase struct {
+		packets []*rtp.Packet
+		frames  []codec.Frame
+	}
+
+	testCases := []testCase{
+		// W = 2: sequence header with length field and frame without it
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 0, true, 0x28, 0x02, 0x08, 0x01, 0x30, 0xaa, 0xbb),
+			},
+			frames: []codec.Frame{
+				{Data: []byte{0x12, 0x00, 0x0a, 0x01, 0x01, 0x32, 0x02, 0xaa, 0xbb}, Key: true},
+			},
+		},
+		// fragmented OBU, W = 0
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x40, 0x02, 0x30, 0x01),
+				testPacket(2, 3000, false, 0xc0, 0x01, 0x02),
+				testPacket(3, 3000, true, 0x80, 0x01, 0x03, 0x02, 0x28, 0x04),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 3000, Data: []byte{0x12, 0x00, 0x32, 0x03, 0x01, 0x02, 0x03, 0x2a, 0x01, 0x04}},
+			},
+		},
+		// lost fragment drops temporal unit, temporal delimiter is ignored
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x40, 0x02, 0x30, 0x01),
+				testPacket(3, 3000, true, 0x80, 0x01, 0x03),
+				testPacket(4, 6000, true, 0x20, 0x01, 0x10, 0x30, 0x05),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 6000, Data: []byte{0x12, 0x00, 0x32, 0x01, 0x05}},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		d := &Depacketizer{}
+		var frames []codec.Frame
+		for _, p := range c.packets {
+			result, err := d.Depacketize(p)
+			assert.NoError(t, err, "testCase : %d", i+1)
+			frames = append(frames, result...)
+		}
+		assert.Equal(t, c.frames, frames, "testCase : %d", i+1)
+	}
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	// temporal delimiter, sequence header, frame of 1000 bytes, metadata
+	unit := []byte{0x12, 0x00, 0x0a, 0x03, 0x01, 0x02, 0x03, 0x32, 0xe8, 0x07}
+	for i := 0; i < 1000; i++ {
+		unit = append(unit, byte(i))
+	}
+	unit = append(unit, 0x2a, 0x01, 0xff)
+
+	p := NewPacketizer(96)
+	p.MTU = 100
+	d := &Depacketizer{}
+
+	packets, err := p.Packetize(codec.Frame{Timestamp: 3000, Data: unit})
+	assert.NoError(t, err)
+	assert.True(t, ParseAggregationHeader(packets[0].Payload[0]).N)
+
+	var frames []codec.Frame
+	for i := range packets {
+		assert.True(t, packets[i].Size() <= p.MTU)
+		result, err := d.Depacketize(&packets[i])
+		assert.NoError(t, err)
+		frames = append(frames, result...)
+	}
+
+	if assert.Len(t, frames, 1) {
+		assert.True(t, frames[0].Key)
+		assert.Equal(t, unit, frames[0].Data)
+	}
+
+	_, err = p.Packetize(codec.Frame{Data: []byte{0x32, 0x05, 0x01}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package av1
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// ClockRate is an RTP clock rate of AV1 streams
+	ClockRate = 90000
+
+	// MaxTemporalUnitSize limits memory consumed by single temporal unit
+	MaxTemporalUnitSize = 8 * 1024 * 1024
+
+	zBit                  = 0x80
+	yBit                  = 0x40
+	wMask                 = 0x30
+	wShift                = 4
+	nBit                  = 0x08
+	aggregationHeaderSize = 1
+)
+
+// temporalDelimiter is an OBU which starts every temporal unit in low-overhead bitstream format
+var temporalDelimiter = []byte{byte(OBUTypeTemporalDelimiter)<<obuTypeShift | obuHasSizeField, 0x00}
+
+// AggregationHeader represents the first byte of AV1 RTP payload
+type AggregationHeader struct {
+	// Z is set if the first OBU element is a continuation of OBU fragment from the previous packet
+	Z bool
+	// Y is set if the last OBU element will continue in the next packet
+	Y bool
+	// W is a count of OBU elements, 0 means that every element has length field
+	W uint8
+	// N is set on the first packet of coded video sequence
+	N bool
+}
+
+// ParseAggregationHeader parses aggregation header byte
+func ParseAggregationHeader(b byte) AggregationHeader {
+	return AggregationHeader{
+		Z: b&zBit != 0,
+		Y: b&yBit != 0,
+		W: b & wMask >> wShift,
+		N: b&nBit != 0,
+	}
+}
+
+// Byte composes aggregation header byte
+func (h AggregationHeader) Byte() byte {
+	b := h.W << wShift & wMask
+	if h.Z {
+		b |= zBit
+	}
+	if h.Y {
+		b |= yBit
+	}
+	if h.N {
+		b |= nBit
+	}
+	return b
+}
+
+// Depacketizer reassembles AV1 temporal units from RTP packets (AV1 RTP payload specification).
+// Temporal units are returned in low-overhead bitstream format starting with temporal delimiter.
+// Temporal units with lost packets are dropped
+type Depacketizer struct {
+	// current temporal unit
+	unit      []byte
+	timestamp uint32
+	key       bool
+	broken    bool
+
+	// fragmented OBU
+	fragment       []byte
+	fragmentActive bool
+
+	seq     uint16
+	started bool
+}
+
+// Depacketize processes RTP packet and returns temporal unit completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	var frames []codec.Frame
+
+	// lost packet may belong to both the current and the next temporal units
+	lost := d.started && p.Header.SequenceNumber != d.seq+1
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+	if lost {
+		d.broken = true
+	}
+
+	// the previous temporal unit hasn't got marker bit
+	if p.Header.Timestamp != d.timestamp && (len(d.unit) != 0 || d.broken || d.fragmentActive) {
+		frames = d.flush(frames)
+	}
+	d.timestamp = p.Header.Timestamp
+	if lost {
+		d.broken = true
+		d.resetFragment()
+	}
+
+	if err := d.parsePayload(p.Payload); err != nil {
+		d.broken = true
+		d.resetFragment()
+		return frames, err
+	}
+
+	if size := len(d.unit) + len(d.fragment); size > MaxTemporalUnitSize {
+		d.broken = true
+		d.resetUnit()
+		return frames, ErrTemporalUnitTooLarge{Size: size}
+	}
+
+	if p.Header.Marker {
+		frames = d.flush(frames)
+	}
+
+	return frames, nil
+}
+
+func (d *Depacketizer) parsePayload(payload []byte) error {
+	if len(payload) < aggregationHeaderSize {
+		return ErrMalformedPacket{Reason: "aggregation header is absent"}
+	}
+	h := ParseAggregationHeader(payload[0])
+	if h.N {
+		d.key = true
+	}
+
+	buf := payload[aggregationHeaderSize:]
+	for i := 0; len(buf) != 0; i++ {
+		if h.W != 0 && i >= int(h.W) {
+			return ErrMalformedPacket{Reason: "extra OBU elements"}
+		}
+
+		// the last element of W elements hasn't got length field
+		element := buf
+		if h.W == 0 || i < int(h.W)-1 {
+			size, n, err := ReadLEB128(buf)
+			if err != nil {
+				return err
+			}
+			if uint64(len(buf)-n) < size {
+				return ErrMalformedPacket{Reason: "OBU element is truncated"}
+			}
+			element = buf[n : n+int(size)]
+			buf = buf[n+int(size):]
+		} else {
+			buf = nil
+		}
+
+		first, last := i == 0, len(buf) == 0
+		switch {
+		case first && h.Z:
+			if !d.fragmentActive {
+				// the beginning of OBU is lost
+				d.broken = true
+			}
+			d.fragment = append(d.fragment, element...)
+		case d.fragmentActive:
+			// the end of OBU is lost
+			d.broken = true
+			d.resetFragment()
+			d.fragment = append(d.fragment, element...)
+		default:
+			d.fragment = append(d.fragment, element...)
+		}
+		d.fragmentActive = true
+
+		if last && h.Y {
+			break
+		}
+
+		obu := d.fragment
+		d.resetFragment()
+		if err := d.appendOBU(obu); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Depacketizer) appendOBU(obu []byte) error {
+	if d.broken || len(obu) == 0 {
+		return nil
+	}
+
+	switch TypeOf(obu) {
+	case OBUTypeTemporalDelimiter, OBUTypeTileList, OBUTypePadding:
+		return nil
+	case OBUTypeSequenceHeader:
+		d.key = true
+	}
+
+	unit, err := appendOBU(d.unit, obu)
+	if err != nil {
+		return err
+	}
+	d.unit = unit
+	return nil
+}
+
+// flush completes current temporal unit
+func (d *Depacketizer) flush(frames []codec.Frame) []codec.Frame {
+	if d.fragmentActive {
+		// the end of OBU is lost
+		d.broken = true
+	}
+
+	if d.broken || len(d.unit) == 0 {
+		d.resetUnit()
+		return frames
+	}
+
+	unit := make([]byte, 0, len(temporalDelimiter)+len(d.unit))
+	unit = append(unit, temporalDelimiter...)
+	unit = append(unit, d.unit...)
+	frames = append(frames, codec.Frame{
+		Timestamp: d.timestamp,
+		Data:      unit,
+		Key:       d.key,
+	})
+
+	d.resetUnit()
+	return frames
+}
+
+func (d *Depacketizer) resetUnit() {
+	d.unit = d.unit[:0]
+	d.key = false
+	d.broken = false
+	d.resetFragment()
+}
+
+func (d *Depacketizer) resetFragment() {
+	d.fragment = d.fragment[:0]
+	d.fragmentActive = false
+}
@@ -0,0 +1,21 @@
+package av1
+
+import "fmt"
+
+// ErrMalformedPacket happens when payload or OBU cannot be parsed
+type ErrMalformedPacket struct {
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed AV1 payload: %s", e.Reason)
+}
+
+// ErrTemporalUnitTooLarge happens when temporal unit exceeds MaxTemporalUnitSize
+type ErrTemporalUnitTooLarge struct {
+	Size int
+}
+
+func (e ErrTemporalUnitTooLarge) Error() string {
+	return fmt.Sprintf("temporal unit too large: %d > %d", e.Size, MaxTemporalUnitSize)
+}
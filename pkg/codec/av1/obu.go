@@ -0,0 +1,139 @@
+package av1
+
+// OBUType is a type of open bitstream unit (AV1 specification section 6.2.2)
+type OBUType uint8
+
+const (
+	OBUTypeSequenceHeader       OBUType = 1
+	OBUTypeTemporalDelimiter    OBUType = 2
+	OBUTypeFrameHeader          OBUType = 3
+	OBUTypeTileGroup            OBUType = 4
+	OBUTypeMetadata             OBUType = 5
+	OBUTypeFrame                OBUType = 6
+	OBUTypeRedundantFrameHeader OBUType = 7
+	OBUTypeTileList             OBUType = 8
+	OBUTypePadding              OBUType = 15
+)
+
+const (
+	obuTypeShift     = 3
+	obuTypeMask      = 0x0F
+	obuExtensionFlag = 0x04
+	obuHasSizeField  = 0x02
+
+	maxLEB128Length = 8
+)
+
+// TypeOf returns type of OBU by its header
+func TypeOf(obu []byte) OBUType {
+	return OBUType(obu[0] >> obuTypeShift & obuTypeMask)
+}
+
+// headerSize returns size of OBU header including extension
+func headerSize(obu []byte) int {
+	if obu[0]&obuExtensionFlag != 0 {
+		return 2
+	}
+	return 1
+}
+
+// ReadLEB128 decodes unsigned LEB128 value and returns it with its length
+func ReadLEB128(buf []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < maxLEB128Length; i++ {
+		if i >= len(buf) {
+			return 0, 0, ErrMalformedPacket{Reason: "LEB128 is truncated"}
+		}
+		v |= uint64(buf[i]&0x7F) << (7 * i)
+		if buf[i]&0x80 == 0 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, ErrMalformedPacket{Reason: "LEB128 is too long"}
+}
+
+// AppendLEB128 encodes unsigned LEB128 value to the end of buffer
+func AppendLEB128(buf []byte, v uint64) []byte {
+	for {
+		b := byte(v & 0x7F)
+		v >>= 7
+		if v == 0 {
+			return append(buf, b)
+		}
+		buf = append(buf, b|0x80)
+	}
+}
+
+// LEB128Size returns length of encoded LEB128 value
+func LEB128Size(v uint64) int {
+	n := 1
+	for ; v >= 0x80; v >>= 7 {
+		n++
+	}
+	return n
+}
+
+// SplitOBUs splits temporal unit in low-overhead bitstream format (all OBUs have size field) to OBUs
+func SplitOBUs(buf []byte) ([][]byte, error) {
+	var obus [][]byte
+	for len(buf) != 0 {
+		obu, n, err := readOBU(buf)
+		if err != nil {
+			return nil, err
+		}
+		obus = append(obus, obu)
+		buf = buf[n:]
+	}
+	return obus, nil
+}
+
+// readOBU reads OBU with size field and returns it with consumed size
+func readOBU(buf []byte) ([]byte, int, error) {
+	if len(buf) == 0 || len(buf) < headerSize(buf) {
+		return nil, 0, ErrMalformedPacket{Reason: "OBU header is truncated"}
+	}
+	if buf[0]&obuHasSizeField == 0 {
+		return nil, 0, ErrMalformedPacket{Reason: "OBU has no size field"}
+	}
+
+	header := headerSize(buf)
+	size, n, err := ReadLEB128(buf[header:])
+	if err != nil {
+		return nil, 0, err
+	}
+	end := uint64(header+n) + size
+	if end > uint64(len(buf)) {
+		return nil, 0, ErrMalformedPacket{Reason: "OBU is truncated"}
+	}
+	return buf[:end], int(end), nil
+}
+
+// appendOBU appends OBU in low-overhead format, size field is added if OBU hasn't got it
+func appendOBU(buf []byte, obu []byte) ([]byte, error) {
+	if len(obu) == 0 || len(obu) < headerSize(obu) {
+		return nil, ErrMalformedPacket{Reason: "OBU header is truncated"}
+	}
+
+	if obu[0]&obuHasSizeField != 0 {
+		obu, _, err := readOBU(obu)
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, obu...), nil
+	}
+
+	header := headerSize(obu)
+	buf = append(buf, obu[0]|obuHasSizeField)
+	buf = append(buf, obu[1:header]...)
+	buf = AppendLEB128(buf, uint64(len(obu)-header))
+	return append(buf, obu[header:]...), nil
+}
+
+// appendElement appends OBU without size field as OBU element of RTP payload
+func appendElement(buf []byte, obu []byte) []byte {
+	header := headerSize(obu)
+	_, n, _ := ReadLEB128(obu[header:])
+	buf = append(buf, obu[0]&^obuHasSizeField)
+	buf = append(buf, obu[1:header]...)
+	return append(buf, obu[header+n:]...)
+}
@@ -0,0 +1,130 @@
+package av1
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// minElementSize is a minimal space for OBU element: length field and one byte of data
+const minElementSize = 2
+
+// Packetizer splits AV1 temporal units in low-overhead bitstream format to RTP packets. Every OBU element
+// has length field (W = 0), temporal delimiters and tile lists are dropped
+type Packetizer struct {
+	codec.Sequencer
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	elements []byte
+	buf      []byte
+	packets  []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer(payloadType uint8) *Packetizer {
+	return &Packetizer{Sequencer: codec.NewSequencer(payloadType)}
+}
+
+// Packetize splits temporal unit to RTP packets. Frame timestamp is counted in 90 kHz units from the stream start.
+// Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+	maxPayload := mtu - rtp.HeaderLength
+	if maxPayload < aggregationHeaderSize+minElementSize {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	obus, err := SplitOBUs(f.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// OBU elements without size fields
+	var (
+		elements       [][]byte
+		sequenceHeader bool
+		elementsSize   = 0
+	)
+	p.elements = p.elements[:0]
+	for _, obu := range obus {
+		switch TypeOf(obu) {
+		case OBUTypeTemporalDelimiter, OBUTypeTileList:
+			continue
+		case OBUTypeSequenceHeader:
+			sequenceHeader = true
+		}
+		elementsSize += len(obu)
+	}
+	if cap(p.elements) < elementsSize {
+		p.elements = make([]byte, 0, elementsSize)
+	}
+	for _, obu := range obus {
+		switch TypeOf(obu) {
+		case OBUTypeTemporalDelimiter, OBUTypeTileList:
+			continue
+		}
+		start := len(p.elements)
+		p.elements = appendElement(p.elements, obu)
+		elements = append(elements, p.elements[start:])
+	}
+
+	// every packet has aggregation header and at least one length field per element
+	count := elementsSize/(maxPayload-aggregationHeaderSize-maxLEB128Length) + len(elements) + 1
+	if size := elementsSize + count*(aggregationHeaderSize+maxLEB128Length); cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	header := AggregationHeader{N: sequenceHeader}
+	start := len(p.buf)
+	p.buf = append(p.buf, 0)
+
+	for i, element := range elements {
+		for len(element) != 0 {
+			space := maxPayload - (len(p.buf) - start)
+			if space < minElementSize {
+				p.flush(f.Timestamp, start, header, false)
+				header = AggregationHeader{Z: header.Y}
+				start = len(p.buf)
+				p.buf = append(p.buf, 0)
+				continue
+			}
+
+			n := space - LEB128Size(uint64(space))
+			if n >= len(element) {
+				n = len(element)
+			}
+			p.buf = AppendLEB128(p.buf, uint64(n))
+			p.buf = append(p.buf, element[:n]...)
+			element = element[n:]
+
+			header.Y = len(element) != 0
+			if header.Y {
+				p.flush(f.Timestamp, start, header, false)
+				header = AggregationHeader{Z: true}
+				start = len(p.buf)
+				p.buf = append(p.buf, 0)
+			}
+		}
+
+		if i == len(elements)-1 {
+			p.flush(f.Timestamp, start, header, true)
+		}
+	}
+
+	return p.packets, nil
+}
+
+func (p *Packetizer) flush(timestamp uint32, start int, header AggregationHeader, marker bool) {
+	p.buf[start] = header.Byte()
+	p.packets = append(p.packets, rtp.Packet{
+		Header:  p.Next(timestamp, marker),
+		Payload: p.buf[start:len(p.buf):len(p.buf)],
+	})
+}
@@ -0,0 +1,27 @@
+package codec
+
+import "github.com/racoon-devel/gortsp/pkg/rtp"
+
+// Frame is a media unit which is carried by one or more RTP packets
+type Frame struct {
+	// Timestamp is an RTP timestamp of the frame
+	Timestamp uint32
+
+	// Data is a frame content in codec specific format
+	Data []byte
+
+	// Key is true if the frame can be decoded independently
+	Key bool
+}
+
+// Depacketizer reassembles frames from RTP packets of single stream
+type Depacketizer interface {
+	// Depacketize processes RTP packet and returns frames completed by it
+	Depacketize(p *rtp.Packet) ([]Frame, error)
+}
+
+// Packetizer splits frames of single stream to RTP packets
+type Packetizer interface {
+	// Packetize splits frame to RTP packets. Frame timestamp is counted in clock rate units from the stream start
+	Packetize(f Frame) ([]rtp.Packet, error)
+}
@@ -0,0 +1,12 @@
+package codec
+
+import "fmt"
+
+// ErrMTUTooSmall happens when MTU doesn't fit even minimal RTP packet
+type ErrMTUTooSmall struct {
+	MTU int
+}
+
+func (e ErrMTUTooSmall) Error() string {
+	return fmt.Sprintf("MTU too small: %d", e.MTU)
+}
@@ -0,0 +1,256 @@
+package h264
+
+import (
+	"encoding/binary"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// ClockRate is an RTP clock rate of H.264 streams
+	ClockRate = 90000
+
+	// MaxAccessUnitSize limits memory consumed by single access unit
+	MaxAccessUnitSize = 8 * 1024 * 1024
+)
+
+// Format is an output format of access units
+type Format int
+
+const (
+	// AnnexB means NAL units are prefixed by start code 00 00 00 01
+	AnnexB Format = iota
+	// AVCC means NAL units are prefixed by 4-byte length
+	AVCC
+)
+
+// Depacketizer reassembles access units from RTP packets (RFC 6184), single NAL unit and non-interleaved
+// modes are supported. Access units with lost packets are dropped
+type Depacketizer struct {
+	// Format is an output format of access units, Annex-B by default
+	Format Format
+
+	// SPS and PPS are inserted before IDR pictures if access unit doesn't contain them.
+	// They are initialized by sprop-parameter-sets and updated by in-band parameter sets
+	SPS []byte
+	PPS []byte
+
+	// current access unit
+	nalus     [][]byte
+	size      int
+	timestamp uint32
+	broken    bool
+
+	// FU-A reassembly buffer
+	fragments []byte
+	fragment  bool
+
+	seq     uint16
+	started bool
+}
+
+// NewDepacketizer creates depacketizer with parameter sets from SDP fmtp (sprop-parameter-sets)
+func NewDepacketizer(spropParameterSets string) (*Depacketizer, error) {
+	d := &Depacketizer{}
+	if spropParameterSets == "" {
+		return d, nil
+	}
+
+	var err error
+	d.SPS, d.PPS, err = ParseSpropParameterSets(spropParameterSets)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Depacketize processes RTP packet and returns access units completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	var frames []codec.Frame
+
+	// lost packet may belong to both the current and the next access units
+	lost := d.started && p.Header.SequenceNumber != d.seq+1
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+	if lost {
+		d.broken = true
+	}
+
+	// the previous access unit hasn't got marker bit
+	if p.Header.Timestamp != d.timestamp && (len(d.nalus) != 0 || d.broken || d.fragment) {
+		frames = d.flush(frames)
+	}
+	d.timestamp = p.Header.Timestamp
+	if lost {
+		d.broken = true
+		d.resetFragment()
+	}
+
+	if err := d.parsePayload(p.Payload); err != nil {
+		d.broken = true
+		d.resetFragment()
+		return frames, err
+	}
+
+	if d.size > MaxAccessUnitSize {
+		size := d.size
+		d.broken = true
+		d.resetAccessUnit()
+		return frames, ErrAccessUnitTooLarge{Size: size}
+	}
+
+	if p.Header.Marker {
+		frames = d.flush(frames)
+	}
+
+	return frames, nil
+}
+
+func (d *Depacketizer) parsePayload(payload []byte) error {
+	if len(payload) == 0 {
+		return ErrMalformedPacket{Reason: "empty payload"}
+	}
+
+	switch t := TypeOf(payload); {
+	case t >= 1 && t <= 23:
+		if d.fragment {
+			// FU-A end is lost
+			d.broken = true
+			d.resetFragment()
+		}
+		d.appendNALU(payload)
+
+	case t == NALUTypeSTAPA:
+		buf := payload[1:]
+		for len(buf) != 0 {
+			if len(buf) < 2 {
+				return ErrMalformedPacket{Reason: "STAP-A size is truncated"}
+			}
+			size := int(binary.BigEndian.Uint16(buf))
+			buf = buf[2:]
+			if size == 0 || size > len(buf) {
+				return ErrMalformedPacket{Reason: "STAP-A NAL unit is truncated"}
+			}
+			d.appendNALU(buf[:size])
+			buf = buf[size:]
+		}
+
+	case t == NALUTypeFUA:
+		if len(payload) < 3 {
+			return ErrMalformedPacket{Reason: "FU-A is too short"}
+		}
+		indicator, header := payload[0], payload[1]
+		start, end := header&fuStartMask != 0, header&fuEndMask != 0
+
+		switch {
+		case start:
+			if d.fragment {
+				d.broken = true
+			}
+			d.fragments = append(d.fragments[:0], indicator&nriMask|header&naluTypeMask)
+			d.fragment = true
+		case !d.fragment:
+			// the first fragment has been lost
+			d.broken = true
+			return nil
+		}
+
+		d.fragments = append(d.fragments, payload[2:]...)
+		d.size += len(payload) - 2
+
+		if end {
+			nalu := make([]byte, len(d.fragments))
+			copy(nalu, d.fragments)
+			d.resetFragment()
+			d.nalus = append(d.nalus, nalu)
+		}
+
+	default:
+		return ErrUnsupportedNALUType{Type: t}
+	}
+
+	return nil
+}
+
+func (d *Depacketizer) appendNALU(nalu []byte) {
+	buf := make([]byte, len(nalu))
+	copy(buf, nalu)
+	d.nalus = append(d.nalus, buf)
+	d.size += len(nalu)
+}
+
+// flush completes current access unit
+func (d *Depacketizer) flush(frames []codec.Frame) []codec.Frame {
+	if d.fragment {
+		// FU-A end is lost
+		d.broken = true
+	}
+
+	if d.broken || len(d.nalus) == 0 {
+		d.resetAccessUnit()
+		return frames
+	}
+
+	var (
+		key         bool
+		hasSPS      bool
+		hasPPS      bool
+		nalus       = make([][]byte, 0, len(d.nalus)+2)
+		parameterAt = 0
+	)
+
+	for _, nalu := range d.nalus {
+		switch TypeOf(nalu) {
+		case NALUTypeIDR:
+			key = true
+		case NALUTypeSPS:
+			hasSPS = true
+			d.SPS = nalu
+		case NALUTypePPS:
+			hasPPS = true
+			d.PPS = nalu
+		case NALUTypeAUD:
+			// parameter sets should follow access unit delimiter
+			parameterAt = 1
+		}
+	}
+
+	if key && (!hasSPS || !hasPPS) && d.SPS != nil && d.PPS != nil {
+		nalus = append(nalus, d.nalus[:parameterAt]...)
+		if !hasSPS {
+			nalus = append(nalus, d.SPS)
+		}
+		if !hasPPS {
+			nalus = append(nalus, d.PPS)
+		}
+		nalus = append(nalus, d.nalus[parameterAt:]...)
+	} else {
+		nalus = d.nalus
+	}
+
+	frame := codec.Frame{
+		Timestamp: d.timestamp,
+		Key:       key,
+	}
+	if d.Format == AVCC {
+		frame.Data = JoinAVCC(nalus)
+	} else {
+		frame.Data = JoinAnnexB(nalus)
+	}
+
+	d.resetAccessUnit()
+	return append(frames, frame)
+}
+
+func (d *Depacketizer) resetAccessUnit() {
+	d.nalus = nil
+	d.size = 0
+	d.broken = false
+	d.resetFragment()
+}
+
+func (d *Depacketizer) resetFragment() {
+	d.fragments = d.fragments[:0]
+	d.fragment = false
+}
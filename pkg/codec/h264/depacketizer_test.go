@@ -0,0 +1,142 @@
+package h264
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	testSPS = []byte{0x67, 0x42, 0x00, 0x29}
+	testPPS = []byte{0x68, 0xce, 0x3c, 0x80}
+)
+
+func testPacket(seq uint16, ts uint32, marker bool, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Marker:         marker,
+			PayloadType:    96,
+			SequenceNumber: seq,
+			Timestamp:      ts,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDepacketizer_Depacketize(t *testing.T) {
+	type testCase struct {
+		format  Format
+		packets []*rtp.Packet
+		frames  []codec.Frame
+	}
+
+	testCases := []testCase{
+		// single NAL units and marker
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x06, 0x01),
+				testPacket(2, 3000, true, 0x41, 0x02),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 3000, Data: []byte{0, 0, 0, 1, 0x06, 0x01, 0, 0, 0, 1, 0x41, 0x02}},
+			},
+		},
+		// access unit without marker is completed by timestamp change
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x41, 0x01),
+				testPacket(2, 6000, false, 0x41, 0x02),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 3000, Data: []byte{0, 0, 0, 1, 0x41, 0x01}},
+			},
+		},
+		// STAP-A with parameter sets, AVCC output
+		{
+			format: AVCC,
+			packets: []*rtp.Packet{
+				testPacket(65535, 0, false, 0x78, 0x00, 0x04, 0x67, 0x42, 0x00, 0x29, 0x00, 0x04, 0x68, 0xce, 0x3c, 0x80),
+				testPacket(0, 0, true, 0x65, 0x88),
+			},
+			frames: []codec.Frame{
+				{Key: true, Data: []byte{0, 0, 0, 4, 0x67, 0x42, 0x00, 0x29, 0, 0, 0, 4, 0x68, 0xce, 0x3c, 0x80, 0, 0, 0, 2, 0x65, 0x88}},
+			},
+		},
+		// FU-A, parameter sets from sprop are inserted after AUD
+		{
+			packets: []*rtp.Packet{
+				testPacket(10, 90000, false, 0x09, 0xf0),
+				testPacket(11, 90000, false, 0x7c, 0x85, 0x01, 0x02),
+				testPacket(12, 90000, false, 0x7c, 0x05, 0x03),
+				testPacket(13, 90000, true, 0x7c, 0x45, 0x04),
+			},
+			frames: []codec.Frame{
+				{
+					Timestamp: 90000,
+					Key:       true,
+					Data: []byte{0, 0, 0, 1, 0x09, 0xf0, 0, 0, 0, 1, 0x67, 0x42, 0x00, 0x29, 0, 0, 0, 1, 0x68, 0xce, 0x3c, 0x80,
+						0, 0, 0, 1, 0x65, 0x01, 0x02, 0x03, 0x04},
+				},
+			},
+		},
+		// lost fragment drops access unit
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x5c, 0x81, 0x01),
+				testPacket(3, 3000, true, 0x5c, 0x41, 0x03),
+				testPacket(4, 6000, true, 0x41, 0x04),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 6000, Data: []byte{0, 0, 0, 1, 0x41, 0x04}},
+			},
+		},
+		// lost first fragment
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, true, 0x41, 0x01),
+				testPacket(3, 6000, true, 0x5c, 0x41, 0x03),
+				testPacket(4, 9000, true, 0x41, 0x04),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 3000, Data: []byte{0, 0, 0, 1, 0x41, 0x01}},
+				{Timestamp: 9000, Data: []byte{0, 0, 0, 1, 0x41, 0x04}},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		d := &Depacketizer{Format: c.format, SPS: testSPS, PPS: testPPS}
+		var frames []codec.Frame
+		for _, p := range c.packets {
+			result, err := d.Depacketize(p)
+			assert.NoError(t, err, "testCase : %d", i+1)
+			frames = append(frames, result...)
+		}
+		assert.Equal(t, c.frames, frames, "testCase : %d", i+1)
+	}
+}
+
+func TestDepacketizer_Errors(t *testing.T) {
+	d := &Depacketizer{}
+
+	_, err := d.Depacketize(testPacket(1, 0, true, 0x79, 0x00))
+	assert.ErrorIs(t, err, ErrUnsupportedNALUType{Type: NALUTypeSTAPB})
+
+	_, err = d.Depacketize(testPacket(2, 0, true, 0x78, 0x00, 0x05, 0x41))
+	assert.IsType(t, ErrMalformedPacket{}, err)
+
+	frames, err := d.Depacketize(testPacket(3, 3000, true, 0x41, 0x01))
+	assert.NoError(t, err)
+	assert.Len(t, frames, 1)
+}
+
+func TestNewDepacketizer(t *testing.T) {
+	d, err := NewDepacketizer("Z0IAKeKQFAe2AtwEBAaQeJEV,aM48gA==")
+	assert.NoError(t, err)
+	assert.Equal(t, testPPS, d.PPS)
+	assert.NotNil(t, d.SPS)
+
+	_, err = NewDepacketizer("!!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package h264
+
+import "fmt"
+
+// ErrUnsupportedNALUType happens when packet uses packetization mode which is not supported (interleaved mode)
+type ErrUnsupportedNALUType struct {
+	Type NALUType
+}
+
+func (e ErrUnsupportedNALUType) Error() string {
+	return fmt.Sprintf("unsupported NAL unit type: %d", e.Type)
+}
+
+// ErrMalformedPacket happens when payload cannot be parsed
+type ErrMalformedPacket struct {
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed H.264 payload: %s", e.Reason)
+}
+
+// ErrAccessUnitTooLarge happens when access unit exceeds MaxAccessUnitSize
+type ErrAccessUnitTooLarge struct {
+	Size int
+}
+
+func (e ErrAccessUnitTooLarge) Error() string {
+	return fmt.Sprintf("access unit too large: %d > %d", e.Size, MaxAccessUnitSize)
+}
@@ -0,0 +1,122 @@
+package h264
+
+import "encoding/binary"
+
+// NALUType is a type of H.264 NAL unit (RFC 6184 section 5.2)
+type NALUType uint8
+
+const (
+	NALUTypeSlice  NALUType = 1
+	NALUTypeIDR    NALUType = 5
+	NALUTypeSEI    NALUType = 6
+	NALUTypeSPS    NALUType = 7
+	NALUTypePPS    NALUType = 8
+	NALUTypeAUD    NALUType = 9
+	NALUTypeSTAPA  NALUType = 24
+	NALUTypeSTAPB  NALUType = 25
+	NALUTypeMTAP16 NALUType = 26
+	NALUTypeMTAP24 NALUType = 27
+	NALUTypeFUA    NALUType = 28
+	NALUTypeFUB    NALUType = 29
+)
+
+const (
+	naluTypeMask = 0x1F
+	nriMask      = 0x60
+	fuStartMask  = 0x80
+	fuEndMask    = 0x40
+)
+
+// TypeOf returns type of NAL unit
+func TypeOf(nalu []byte) NALUType {
+	return NALUType(nalu[0] & naluTypeMask)
+}
+
+// annexBStartCode is a NAL unit delimiter of Annex-B byte stream
+var annexBStartCode = []byte{0x00, 0x00, 0x00, 0x01}
+
+// SplitAnnexB splits Annex-B byte stream to NAL units
+func SplitAnnexB(buf []byte) [][]byte {
+	var (
+		nalus [][]byte
+		start = -1
+		zeros = 0
+	)
+
+	for i, b := range buf {
+		switch {
+		case b == 0:
+			zeros++
+			continue
+		case b == 1 && zeros >= 2:
+			if start >= 0 {
+				nalus = appendNALU(nalus, buf[start:i-zeros])
+			}
+			start = i + 1
+		}
+		zeros = 0
+	}
+
+	if start >= 0 {
+		nalus = appendNALU(nalus, buf[start:])
+	}
+
+	return nalus
+}
+
+func appendNALU(nalus [][]byte, nalu []byte) [][]byte {
+	if len(nalu) == 0 {
+		return nalus
+	}
+	return append(nalus, nalu)
+}
+
+// JoinAnnexB makes Annex-B byte stream from NAL units
+func JoinAnnexB(nalus [][]byte) []byte {
+	size := 0
+	for _, nalu := range nalus {
+		size += len(annexBStartCode) + len(nalu)
+	}
+
+	buf := make([]byte, 0, size)
+	for _, nalu := range nalus {
+		buf = append(buf, annexBStartCode...)
+		buf = append(buf, nalu...)
+	}
+	return buf
+}
+
+// SplitAVCC splits AVCC buffer (NAL units prefixed by 4-byte length) to NAL units
+func SplitAVCC(buf []byte) ([][]byte, error) {
+	var nalus [][]byte
+	for len(buf) != 0 {
+		if len(buf) < 4 {
+			return nil, ErrMalformedPacket{Reason: "AVCC length is truncated"}
+		}
+		length := int(binary.BigEndian.Uint32(buf))
+		buf = buf[4:]
+		if length > len(buf) {
+			return nil, ErrMalformedPacket{Reason: "AVCC NAL unit is truncated"}
+		}
+		nalus = appendNALU(nalus, buf[:length])
+		buf = buf[length:]
+	}
+	return nalus, nil
+}
+
+// JoinAVCC makes AVCC buffer from NAL units
+func JoinAVCC(nalus [][]byte) []byte {
+	size := 0
+	for _, nalu := range nalus {
+		size += 4 + len(nalu)
+	}
+
+	buf := make([]byte, size)
+	offset := 0
+	for _, nalu := range nalus {
+		binary.BigEndian.PutUint32(buf[offset:], uint32(len(nalu)))
+		copy(buf[offset+4:], nalu)
+		offset += 4 + len(nalu)
+	}
+	return buf
+}
@@ -0,0 +1,60 @@
+package h264
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitAnnexB(t *testing.T) {
+	type testCase struct {
+		buf   []byte
+		nalus [][]byte
+	}
+
+	testCases := []testCase{
+		{
+			buf:   []byte{0x00, 0x00, 0x00, 0x01, 0x67, 0x01, 0x00, 0x00, 0x01, 0x68, 0x02},
+			nalus: [][]byte{{0x67, 0x01}, {0x68, 0x02}},
+		},
+		{
+			buf:   []byte{0x00, 0x00, 0x01, 0x65, 0x00, 0x00, 0x03, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x06, 0x05},
+			nalus: [][]byte{{0x65, 0x00, 0x00, 0x03, 0x01}, {0x06, 0x05}},
+		},
+		{
+			buf:   []byte{0x00, 0x00, 0x01, 0x00, 0x00, 0x01, 0x41},
+			nalus: [][]byte{{0x41}},
+		},
+		{
+			buf:   []byte{0x41, 0x01},
+			nalus: nil,
+		},
+	}
+
+	for i, c := range testCases {
+		assert.Equal(t, c.nalus, SplitAnnexB(c.buf), "testCase : %d", i+1)
+	}
+}
+
+func TestAVCC(t *testing.T) {
+	nalus := [][]byte{{0x67, 0x01}, {0x65, 0x01, 0x02}}
+	buf := JoinAVCC(nalus)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x02, 0x67, 0x01, 0x00, 0x00, 0x00, 0x03, 0x65, 0x01, 0x02}, buf)
+
+	result, err := SplitAVCC(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, nalus, result)
+
+	_, err = SplitAVCC(buf[:len(buf)-1])
+	assert.Error(t, err)
+}
+
+func TestParseSpropParameterSets(t *testing.T) {
+	sps, pps, err := ParseSpropParameterSets("Z0IAKeKQFAe2AtwEBAaQeJEV,aM48gA==")
+	assert.NoError(t, err)
+	assert.Equal(t, NALUTypeSPS, TypeOf(sps))
+	assert.Equal(t, []byte{0x68, 0xce, 0x3c, 0x80}, pps)
+	assert.Equal(t, "Z0IAKeKQFAe2AtwEBAaQeJEV,aM48gA==", SpropParameterSets(sps, pps))
+
+	_, _, err = ParseSpropParameterSets("Z0IAKeKQ!!,aM48gA==")
+	assert.Error(t, err)
+}
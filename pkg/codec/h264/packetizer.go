@@ -0,0 +1,161 @@
+package h264
+
+import (
+	"encoding/binary"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	fuHeaderLength    = 2
+	stapHeaderLength  = 1
+	stapLengthLength  = 2
+	minPayloadForFUA  = fuHeaderLength + 1
+	nalHeaderFBitMask = 0x80
+)
+
+// Packetizer splits access units to RTP packets (RFC 6184 non-interleaved mode). Large NAL units are fragmented
+// with FU-A, small ones are aggregated with STAP-A
+type Packetizer struct {
+	codec.Sequencer
+
+	// Format is an input format of access units, Annex-B by default
+	Format Format
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer(payloadType uint8) *Packetizer {
+	return &Packetizer{Sequencer: codec.NewSequencer(payloadType)}
+}
+
+// Packetize splits access unit to RTP packets. Frame timestamp is counted in 90 kHz units from the stream start.
+// Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+	maxPayload := mtu - rtp.HeaderLength
+	if maxPayload < minPayloadForFUA {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	var nalus [][]byte
+	if p.Format == AVCC {
+		var err error
+		if nalus, err = SplitAVCC(f.Data); err != nil {
+			return nil, err
+		}
+	} else {
+		nalus = SplitAnnexB(f.Data)
+	}
+
+	// reserve enough space for all payloads, so slices of the buffer aren't moved by append
+	size := 0
+	for _, nalu := range nalus {
+		size += len(nalu) + stapHeaderLength + stapLengthLength + (len(nalu)/(maxPayload-fuHeaderLength)+1)*fuHeaderLength
+	}
+	if cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	var aggregated [][]byte
+	aggregatedSize := stapHeaderLength
+
+	for _, nalu := range nalus {
+		if len(nalu) > maxPayload {
+			p.aggregate(f.Timestamp, aggregated)
+			aggregated, aggregatedSize = aggregated[:0], stapHeaderLength
+			p.fragment(f.Timestamp, nalu, maxPayload)
+			continue
+		}
+
+		if aggregatedSize+stapLengthLength+len(nalu) > maxPayload {
+			p.aggregate(f.Timestamp, aggregated)
+			aggregated, aggregatedSize = aggregated[:0], stapHeaderLength
+		}
+		aggregated = append(aggregated, nalu)
+		aggregatedSize += stapLengthLength + len(nalu)
+	}
+	p.aggregate(f.Timestamp, aggregated)
+
+	if len(p.packets) != 0 {
+		p.packets[len(p.packets)-1].Header.Marker = true
+	}
+
+	return p.packets, nil
+}
+
+// aggregate makes single NAL unit packet or STAP-A packet
+func (p *Packetizer) aggregate(timestamp uint32, nalus [][]byte) {
+	switch len(nalus) {
+	case 0:
+		return
+	case 1:
+		p.appendPacket(timestamp, p.alloc(nalus[0]))
+		return
+	}
+
+	var header byte
+	for _, nalu := range nalus {
+		header |= nalu[0] & nalHeaderFBitMask
+		if nri := nalu[0] & nriMask; nri > header&nriMask {
+			header = header&^nriMask | nri
+		}
+	}
+
+	start := len(p.buf)
+	p.buf = append(p.buf, header|byte(NALUTypeSTAPA))
+	for _, nalu := range nalus {
+		p.buf = append(p.buf, 0, 0)
+		binary.BigEndian.PutUint16(p.buf[len(p.buf)-stapLengthLength:], uint16(len(nalu)))
+		p.buf = append(p.buf, nalu...)
+	}
+	p.appendPacket(timestamp, p.buf[start:len(p.buf):len(p.buf)])
+}
+
+// fragment splits NAL unit to FU-A packets
+func (p *Packetizer) fragment(timestamp uint32, nalu []byte, maxPayload int) {
+	indicator := nalu[0]&^naluTypeMask | byte(NALUTypeFUA)
+	header := nalu[0]&naluTypeMask | fuStartMask
+
+	data := nalu[1:]
+	for len(data) != 0 {
+		n := maxPayload - fuHeaderLength
+		if n >= len(data) {
+			n = len(data)
+			header |= fuEndMask
+		}
+
+		start := len(p.buf)
+		p.buf = append(p.buf, indicator, header)
+		p.buf = append(p.buf, data[:n]...)
+		p.appendPacket(timestamp, p.buf[start:len(p.buf):len(p.buf)])
+
+		data = data[n:]
+		header &^= fuStartMask
+	}
+}
+
+func (p *Packetizer) alloc(data []byte) []byte {
+	start := len(p.buf)
+	p.buf = append(p.buf, data...)
+	return p.buf[start:len(p.buf):len(p.buf)]
+}
+
+func (p *Packetizer) appendPacket(timestamp uint32, payload []byte) {
+	p.packets = append(p.packets, rtp.Packet{
+		Header:  p.Next(timestamp, false),
+		Payload: payload,
+	})
+}
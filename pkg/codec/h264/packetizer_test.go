@@ -0,0 +1,106 @@
+package h264
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPacketizer_Packetize(t *testing.T) {
+	type testCase struct {
+		mtu      int
+		frame    []byte
+		payloads [][]byte
+	}
+
+	testCases := []testCase{
+		// single NAL unit
+		{
+			frame:    []byte{0, 0, 0, 1, 0x41, 0x01, 0x02},
+			payloads: [][]byte{{0x41, 0x01, 0x02}},
+		},
+		// STAP-A
+		{
+			frame: []byte{0, 0, 0, 1, 0x67, 0x01, 0, 0, 0, 1, 0x68, 0x02, 0, 0, 1, 0x25, 0x03},
+			payloads: [][]byte{
+				{0x78, 0x00, 0x02, 0x67, 0x01, 0x00, 0x02, 0x68, 0x02, 0x00, 0x02, 0x25, 0x03},
+			},
+		},
+		// STAP-A is split by MTU
+		{
+			mtu:   rtp.HeaderLength + 9,
+			frame: []byte{0, 0, 0, 1, 0x67, 0x01, 0, 0, 0, 1, 0x68, 0x02, 0, 0, 1, 0x25, 0x03},
+			payloads: [][]byte{
+				{0x78, 0x00, 0x02, 0x67, 0x01, 0x00, 0x02, 0x68, 0x02},
+				{0x25, 0x03},
+			},
+		},
+		// FU-A
+		{
+			mtu:   rtp.HeaderLength + 4,
+			frame: []byte{0, 0, 0, 1, 0x09, 0xf0, 0, 0, 0, 1, 0x65, 0x01, 0x02, 0x03, 0x04, 0x05},
+			payloads: [][]byte{
+				{0x09, 0xf0},
+				{0x7c, 0x85, 0x01, 0x02},
+				{0x7c, 0x05, 0x03, 0x04},
+				{0x7c, 0x45, 0x05},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		p := &Packetizer{
+			Sequencer: codec.Sequencer{PayloadType: 96, SSRC: 1, SequenceNumber: 65535, InitialTimestamp: 1000},
+			MTU:       c.mtu,
+		}
+		packets, err := p.Packetize(codec.Frame{Timestamp: 3000, Data: c.frame})
+		assert.NoError(t, err, "testCase : %d", i+1)
+		if !assert.Len(t, packets, len(c.payloads), "testCase : %d", i+1) {
+			continue
+		}
+
+		for j, packet := range packets {
+			assert.Equal(t, c.payloads[j], packet.Payload, "testCase : %d", i+1)
+			assert.Equal(t, uint16(65535+j), packet.Header.SequenceNumber, "testCase : %d", i+1)
+			assert.Equal(t, uint32(4000), packet.Header.Timestamp, "testCase : %d", i+1)
+			assert.Equal(t, uint8(96), packet.Header.PayloadType, "testCase : %d", i+1)
+			assert.Equal(t, j == len(packets)-1, packet.Header.Marker, "testCase : %d", i+1)
+		}
+	}
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	frame := []byte{0, 0, 0, 4, 0x67, 0x42, 0x00, 0x29, 0, 0, 0, 4, 0x68, 0xce, 0x3c, 0x80, 0, 0, 0x10, 0x00, 0x65}
+	frame = append(frame, make([]byte, 0x1000-1)...)
+
+	p := NewPacketizer(96)
+	p.Format = AVCC
+	d := &Depacketizer{Format: AVCC}
+
+	for i := 0; i < 3; i++ {
+		packets, err := p.Packetize(codec.Frame{Timestamp: uint32(i) * 3000, Data: frame})
+		assert.NoError(t, err)
+		assert.True(t, len(packets) > 1)
+
+		var frames []codec.Frame
+		for _, packet := range packets {
+			buf, err := packet.Compose()
+			assert.NoError(t, err)
+
+			var parsed rtp.Packet
+			assert.NoError(t, parsed.Parse(buf))
+			result, err := d.Depacketize(&parsed)
+			assert.NoError(t, err)
+			frames = append(frames, result...)
+		}
+
+		if assert.Len(t, frames, 1) {
+			assert.True(t, frames[0].Key)
+			assert.Equal(t, frame, frames[0].Data)
+		}
+	}
+
+	_, err := (&Packetizer{MTU: rtp.HeaderLength}).Packetize(codec.Frame{Data: frame})
+	assert.IsType(t, codec.ErrMTUTooSmall{}, err)
+}
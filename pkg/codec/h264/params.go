@@ -0,0 +1,43 @@
+package h264
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ParseSpropParameterSets decodes sprop-parameter-sets fmtp parameter and returns the first SPS and PPS
+func ParseSpropParameterSets(value string) (sps []byte, pps []byte, err error) {
+	for _, encoded := range strings.Split(value, ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+
+		nalu, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode parameter set failed: %w", err)
+		}
+		if len(nalu) == 0 {
+			continue
+		}
+
+		switch TypeOf(nalu) {
+		case NALUTypeSPS:
+			if sps == nil {
+				sps = nalu
+			}
+		case NALUTypePPS:
+			if pps == nil {
+				pps = nalu
+			}
+		}
+	}
+
+	return sps, pps, nil
+}
+
+// SpropParameterSets encodes SPS and PPS to sprop-parameter-sets fmtp parameter
+func SpropParameterSets(sps []byte, pps []byte) string {
+	return base64.StdEncoding.EncodeToString(sps) + "," + base64.StdEncoding.EncodeToString(pps)
+}
@@ -0,0 +1,305 @@
+package h265
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+const (
+	// ClockRate is an RTP clock rate of H.265 streams
+	ClockRate = 90000
+
+	// MaxAccessUnitSize limits memory consumed by single access unit
+	MaxAccessUnitSize = 8 * 1024 * 1024
+)
+
+type nalu struct {
+	data []byte
+	don  uint16
+}
+
+// Depacketizer reassembles access units from RTP packets (RFC 7798). Access units with lost packets are dropped
+type Depacketizer struct {
+	// Format is an output format of access units, Annex-B by default
+	Format Format
+
+	// MaxDONDiff is sprop-max-don-diff value, payloads contain DONL fields if it is greater than 0.
+	// NAL units of access unit are reordered by decoding order number
+	MaxDONDiff int
+
+	// ParameterSets are inserted before IRAP pictures if access unit doesn't contain them.
+	// They are initialized by sprop-vps/sps/pps and updated by in-band parameter sets
+	ParameterSets
+
+	// current access unit
+	nalus     []nalu
+	size      int
+	timestamp uint32
+	broken    bool
+
+	// FU reassembly buffer
+	fragments []byte
+	fragment  bool
+	don       uint16
+
+	seq     uint16
+	started bool
+}
+
+// NewDepacketizer creates depacketizer with parameters from SDP fmtp
+func NewDepacketizer(fmtp sdp.FMTP) (*Depacketizer, error) {
+	ps, err := ParseParameterSets(fmtp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Depacketizer{
+		MaxDONDiff:    MaxDONDiff(fmtp),
+		ParameterSets: ps,
+	}, nil
+}
+
+// Depacketize processes RTP packet and returns access units completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	var frames []codec.Frame
+
+	// lost packet may belong to both the current and the next access units
+	lost := d.started && p.Header.SequenceNumber != d.seq+1
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+	if lost {
+		d.broken = true
+	}
+
+	// the previous access unit hasn't got marker bit
+	if p.Header.Timestamp != d.timestamp && (len(d.nalus) != 0 || d.broken || d.fragment) {
+		frames = d.flush(frames)
+	}
+	d.timestamp = p.Header.Timestamp
+	if lost {
+		d.broken = true
+		d.resetFragment()
+	}
+
+	if err := d.parsePayload(p.Payload); err != nil {
+		d.broken = true
+		d.resetFragment()
+		return frames, err
+	}
+
+	if d.size > MaxAccessUnitSize {
+		size := d.size
+		d.broken = true
+		d.resetAccessUnit()
+		return frames, ErrAccessUnitTooLarge{Size: size}
+	}
+
+	if p.Header.Marker {
+		frames = d.flush(frames)
+	}
+
+	return frames, nil
+}
+
+func (d *Depacketizer) parsePayload(payload []byte) error {
+	if len(payload) < NALUHeaderLength {
+		return ErrMalformedPacket{Reason: "payload is too short"}
+	}
+	donl := d.MaxDONDiff > 0
+
+	switch t := TypeOf(payload); t {
+	case NALUTypeAP:
+		buf := payload[NALUHeaderLength:]
+		var don uint16
+		for first := true; len(buf) != 0; first = false {
+			if donl {
+				if first {
+					if len(buf) < donLength {
+						return ErrMalformedPacket{Reason: "AP DONL is truncated"}
+					}
+					don = binary.BigEndian.Uint16(buf)
+					buf = buf[donLength:]
+				} else {
+					if len(buf) < dondLength {
+						return ErrMalformedPacket{Reason: "AP DOND is truncated"}
+					}
+					don += uint16(buf[0]) + 1
+					buf = buf[dondLength:]
+				}
+			}
+
+			if len(buf) < lengthLength {
+				return ErrMalformedPacket{Reason: "AP size is truncated"}
+			}
+			size := int(binary.BigEndian.Uint16(buf))
+			buf = buf[lengthLength:]
+			if size < NALUHeaderLength || size > len(buf) {
+				return ErrMalformedPacket{Reason: "AP NAL unit is truncated"}
+			}
+			d.appendNALU(buf[:size], don)
+			buf = buf[size:]
+		}
+
+	case NALUTypeFU:
+		if len(payload) < fuHeaderSize {
+			return ErrMalformedPacket{Reason: "FU is too short"}
+		}
+		header := payload[NALUHeaderLength]
+		start, end := header&fuStartMask != 0, header&fuEndMask != 0
+		data := payload[fuHeaderSize:]
+
+		switch {
+		case start:
+			if d.fragment {
+				d.broken = true
+			}
+			d.don = 0
+			if donl {
+				if len(data) < donLength {
+					return ErrMalformedPacket{Reason: "FU DONL is truncated"}
+				}
+				d.don = binary.BigEndian.Uint16(data)
+				data = data[donLength:]
+			}
+			d.fragments = append(d.fragments[:0], payload[0]&^naluTypeMask|(header&fuTypeMask)<<1, payload[1])
+			d.fragment = true
+		case !d.fragment:
+			// the first fragment has been lost
+			d.broken = true
+			return nil
+		}
+
+		d.fragments = append(d.fragments, data...)
+		d.size += len(data)
+
+		if end {
+			data := make([]byte, len(d.fragments))
+			copy(data, d.fragments)
+			d.resetFragment()
+			d.nalus = append(d.nalus, nalu{data: data, don: d.don})
+		}
+
+	case NALUTypePACI:
+		return ErrUnsupportedNALUType{Type: t}
+
+	default:
+		if d.fragment {
+			// FU end is lost
+			d.broken = true
+			d.resetFragment()
+		}
+
+		var don uint16
+		if donl {
+			if len(payload) < NALUHeaderLength+donLength {
+				return ErrMalformedPacket{Reason: "DONL is truncated"}
+			}
+			don = binary.BigEndian.Uint16(payload[NALUHeaderLength:])
+			data := make([]byte, 0, len(payload)-donLength)
+			data = append(data, payload[:NALUHeaderLength]...)
+			data = append(data, payload[NALUHeaderLength+donLength:]...)
+			d.nalus = append(d.nalus, nalu{data: data, don: don})
+			d.size += len(data)
+			return nil
+		}
+		d.appendNALU(payload, don)
+	}
+
+	return nil
+}
+
+func (d *Depacketizer) appendNALU(data []byte, don uint16) {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	d.nalus = append(d.nalus, nalu{data: buf, don: don})
+	d.size += len(data)
+}
+
+// flush completes current access unit
+func (d *Depacketizer) flush(frames []codec.Frame) []codec.Frame {
+	if d.fragment {
+		// FU end is lost
+		d.broken = true
+	}
+
+	if d.broken || len(d.nalus) == 0 {
+		d.resetAccessUnit()
+		return frames
+	}
+
+	if d.MaxDONDiff > 0 {
+		first := d.nalus[0].don
+		sort.SliceStable(d.nalus, func(i, j int) bool {
+			return int16(d.nalus[i].don-first) < int16(d.nalus[j].don-first)
+		})
+	}
+
+	var (
+		key         bool
+		hasVPS      bool
+		hasSPS      bool
+		hasPPS      bool
+		parameterAt = 0
+		nalus       = make([][]byte, 0, len(d.nalus)+3)
+	)
+
+	for _, n := range d.nalus {
+		switch t := TypeOf(n.data); {
+		case t.IsIRAP():
+			key = true
+		case t == NALUTypeVPS:
+			hasVPS = true
+			d.VPS = n.data
+		case t == NALUTypeSPS:
+			hasSPS = true
+			d.SPS = n.data
+		case t == NALUTypePPS:
+			hasPPS = true
+			d.PPS = n.data
+		case t == NALUTypeAUD:
+			// parameter sets should follow access unit delimiter
+			parameterAt = 1
+		}
+	}
+
+	insert := key && d.VPS != nil && d.SPS != nil && d.PPS != nil
+	for i, n := range d.nalus {
+		if insert && i == parameterAt {
+			if !hasVPS {
+				nalus = append(nalus, d.VPS)
+			}
+			if !hasSPS {
+				nalus = append(nalus, d.SPS)
+			}
+			if !hasPPS {
+				nalus = append(nalus, d.PPS)
+			}
+		}
+		nalus = append(nalus, n.data)
+	}
+
+	frames = append(frames, codec.Frame{
+		Timestamp: d.timestamp,
+		Data:      join(d.Format, nalus),
+		Key:       key,
+	})
+
+	d.resetAccessUnit()
+	return frames
+}
+
+func (d *Depacketizer) resetAccessUnit() {
+	d.nalus = nil
+	d.size = 0
+	d.broken = false
+	d.resetFragment()
+}
+
+func (d *Depacketizer) resetFragment() {
+	d.fragments = d.fragments[:0]
+	d.fragment = false
+}
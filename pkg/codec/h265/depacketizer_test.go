@@ -0,0 +1,138 @@
+package h265
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	testVPS = []byte{0x40, 0x01, 0x0c}
+	testSPS = []byte{0x42, 0x01, 0x01}
+	testPPS = []byte{0x44, 0x01, 0xc0}
+)
+
+func testPacket(seq uint16, ts uint32, marker bool, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Marker:         marker,
+			PayloadType:    96,
+			SequenceNumber: seq,
+			Timestamp:      ts,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDepacketizer_Depacketize(t *testing.T) {
+	type testCase struct {
+		maxDONDiff int
+		packets    []*rtp.Packet
+		frames     []codec.Frame
+	}
+
+	testCases := []testCase{
+		// single NAL units
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x02, 0x01, 0xaa),
+				testPacket(2, 3000, true, 0x02, 0x01, 0xbb),
+			},
+			frames: []codec.Frame{
+				{Timestamp: 3000, Data: []byte{0, 0, 0, 1, 0x02, 0x01, 0xaa, 0, 0, 0, 1, 0x02, 0x01, 0xbb}},
+			},
+		},
+		// AP with parameter sets and FU with IDR
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 0, false, 0x60, 0x01, 0x00, 0x03, 0x40, 0x01, 0x0c, 0x00, 0x03, 0x42, 0x01, 0x01, 0x00, 0x03, 0x44, 0x01, 0xc0),
+				testPacket(2, 0, false, 0x62, 0x01, 0x93, 0x01, 0x02),
+				testPacket(3, 0, true, 0x62, 0x01, 0x53, 0x03),
+			},
+			frames: []codec.Frame{
+				{
+					Key: true,
+					Data: []byte{0, 0, 0, 1, 0x40, 0x01, 0x0c, 0, 0, 0, 1, 0x42, 0x01, 0x01, 0, 0, 0, 1, 0x44, 0x01, 0xc0,
+						0, 0, 0, 1, 0x26, 0x01, 0x01, 0x02, 0x03},
+				},
+			},
+		},
+		// parameter sets from sprop are inserted before IRAP, lost FU drops access unit
+		{
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x62, 0x01, 0x81, 0x01),
+				testPacket(3, 3000, true, 0x62, 0x01, 0x41, 0x03),
+				testPacket(4, 6000, true, 0x2a, 0x01, 0x04),
+			},
+			frames: []codec.Frame{
+				{
+					Timestamp: 6000,
+					Key:       true,
+					Data: []byte{0, 0, 0, 1, 0x40, 0x01, 0x0c, 0, 0, 0, 1, 0x42, 0x01, 0x01, 0, 0, 0, 1, 0x44, 0x01, 0xc0,
+						0, 0, 0, 1, 0x2a, 0x01, 0x04},
+				},
+			},
+		},
+		// DONL reorders NAL units
+		{
+			maxDONDiff: 2,
+			packets: []*rtp.Packet{
+				testPacket(1, 3000, false, 0x02, 0x01, 0x00, 0x07, 0xbb),
+				testPacket(2, 3000, false, 0x60, 0x01, 0x00, 0x05, 0x00, 0x03, 0x02, 0x01, 0xaa, 0x01, 0x00, 0x03, 0x02, 0x01, 0xcc),
+				testPacket(3, 3000, false, 0x62, 0x01, 0x81, 0x00, 0x09, 0xdd),
+				testPacket(4, 3000, true, 0x62, 0x01, 0x41, 0xee),
+			},
+			frames: []codec.Frame{
+				{
+					Timestamp: 3000,
+					Data: []byte{0, 0, 0, 1, 0x02, 0x01, 0xaa, 0, 0, 0, 1, 0x02, 0x01, 0xbb, 0, 0, 0, 1, 0x02, 0x01, 0xcc,
+						0, 0, 0, 1, 0x02, 0x01, 0xdd, 0xee},
+				},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		d := &Depacketizer{
+			MaxDONDiff:    c.maxDONDiff,
+			ParameterSets: ParameterSets{VPS: testVPS, SPS: testSPS, PPS: testPPS},
+		}
+		var frames []codec.Frame
+		for _, p := range c.packets {
+			result, err := d.Depacketize(p)
+			assert.NoError(t, err, "testCase : %d", i+1)
+			frames = append(frames, result...)
+		}
+		assert.Equal(t, c.frames, frames, "testCase : %d", i+1)
+	}
+}
+
+func TestDepacketizer_Errors(t *testing.T) {
+	d := &Depacketizer{}
+
+	_, err := d.Depacketize(testPacket(1, 0, true, 0x64, 0x01, 0x00))
+	assert.ErrorIs(t, err, ErrUnsupportedNALUType{Type: NALUTypePACI})
+
+	_, err = d.Depacketize(testPacket(2, 0, true, 0x60, 0x01, 0x00, 0x05, 0x02))
+	assert.IsType(t, ErrMalformedPacket{}, err)
+
+	_, err = d.Depacketize(testPacket(3, 0, true, 0x02))
+	assert.IsType(t, ErrMalformedPacket{}, err)
+}
+
+func TestNewDepacketizer(t *testing.T) {
+	fmtp := sdp.ParseFMTP("sprop-max-don-diff=1;sprop-vps=QAEM;sprop-sps=QgEB;sprop-pps=RAHA")
+	d, err := NewDepacketizer(fmtp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, d.MaxDONDiff)
+	assert.Equal(t, ParameterSets{VPS: testVPS, SPS: testSPS, PPS: testPPS}, d.ParameterSets)
+
+	composed := sdp.FMTP{}
+	d.ParameterSets.FMTP(composed)
+	assert.Equal(t, "sprop-pps=RAHA;sprop-sps=QgEB;sprop-vps=QAEM", composed.String())
+
+	_, err = NewDepacketizer(sdp.ParseFMTP("sprop-vps=!!"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package h265
+
+import "github.com/racoon-devel/gortsp/pkg/codec/h264"
+
+// NALUType is a type of H.265 NAL unit (RFC 7798 section 1.1.4)
+type NALUType uint8
+
+const (
+	NALUTypeTrailN    NALUType = 0
+	NALUTypeTrailR    NALUType = 1
+	NALUTypeBLAWLP    NALUType = 16
+	NALUTypeIDRWRADL  NALUType = 19
+	NALUTypeIDRNLP    NALUType = 20
+	NALUTypeCRA       NALUType = 21
+	NALUTypeReserved  NALUType = 23
+	NALUTypeVPS       NALUType = 32
+	NALUTypeSPS       NALUType = 33
+	NALUTypePPS       NALUType = 34
+	NALUTypeAUD       NALUType = 35
+	NALUTypePrefixSEI NALUType = 39
+	NALUTypeAP        NALUType = 48
+	NALUTypeFU        NALUType = 49
+	NALUTypePACI      NALUType = 50
+)
+
+const (
+	// NALUHeaderLength is a size of H.265 NAL unit header
+	NALUHeaderLength = 2
+
+	naluTypeMask = 0x7E
+	layerIDMask  = 0x01F8
+	tidMask      = 0x07
+	fBitMask     = 0x80
+	fuStartMask  = 0x80
+	fuEndMask    = 0x40
+	fuTypeMask   = 0x3F
+	donLength    = 2
+	dondLength   = 1
+	lengthLength = 2
+	fuHeaderSize = NALUHeaderLength + 1
+	apHeaderSize = NALUHeaderLength
+)
+
+// TypeOf returns type of NAL unit
+func TypeOf(nalu []byte) NALUType {
+	return NALUType(nalu[0]&naluTypeMask) >> 1
+}
+
+// IsIRAP returns true if NAL unit is a part of intra random access point picture
+func (t NALUType) IsIRAP() bool {
+	return t >= NALUTypeBLAWLP && t <= NALUTypeReserved
+}
+
+// Format is an input or output format of access units, the same as for H.264
+type Format = h264.Format
+
+const (
+	// AnnexB means NAL units are prefixed by start code 00 00 00 01
+	AnnexB = h264.AnnexB
+	// AVCC means NAL units are prefixed by 4-byte length (HVCC)
+	AVCC = h264.AVCC
+)
+
+func join(format Format, nalus [][]byte) []byte {
+	if format == AVCC {
+		return h264.JoinAVCC(nalus)
+	}
+	return h264.JoinAnnexB(nalus)
+}
+
+func split(format Format, buf []byte) ([][]byte, error) {
+	if format == AVCC {
+		return h264.SplitAVCC(buf)
+	}
+	return h264.SplitAnnexB(buf), nil
+}
@@ -0,0 +1,198 @@
+package h265
+
+import (
+	"encoding/binary"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// Packetizer splits access units to RTP packets (RFC 7798). Large NAL units are fragmented with FU,
+// small ones are aggregated with AP
+type Packetizer struct {
+	codec.Sequencer
+
+	// Format is an input format of access units, Annex-B by default
+	Format Format
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// MaxDONDiff is sprop-max-don-diff value, DONL fields are added if it is greater than 0.
+	// NAL units are sent in decoding order, so DON is incremented by one for each NAL unit
+	MaxDONDiff int
+
+	don uint16
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer(payloadType uint8) *Packetizer {
+	return &Packetizer{Sequencer: codec.NewSequencer(payloadType)}
+}
+
+// Packetize splits access unit to RTP packets. Frame timestamp is counted in 90 kHz units from the stream start.
+// Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+
+	donl := 0
+	if p.MaxDONDiff > 0 {
+		donl = donLength
+	}
+
+	maxPayload := mtu - rtp.HeaderLength
+	if maxPayload < fuHeaderSize+donl+1 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	nalus, err := split(p.Format, f.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// reserve enough space for all payloads, so slices of the buffer aren't moved by append
+	size := 0
+	for _, nalu := range nalus {
+		size += len(nalu) + apHeaderSize + donl + lengthLength +
+			(len(nalu)/(maxPayload-fuHeaderSize-donl)+1)*(fuHeaderSize+donl)
+	}
+	if cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	var aggregated [][]byte
+	aggregatedSize := apHeaderSize
+
+	for _, nalu := range nalus {
+		if len(nalu) < NALUHeaderLength {
+			return nil, ErrMalformedPacket{Reason: "NAL unit is too short"}
+		}
+
+		if len(nalu)+donl > maxPayload {
+			p.aggregate(f.Timestamp, aggregated, donl)
+			aggregated, aggregatedSize = aggregated[:0], apHeaderSize
+			p.fragment(f.Timestamp, nalu, maxPayload, donl)
+			continue
+		}
+
+		// DONL of the first unit and DOND of the next ones are counted as DONL for simplicity
+		if aggregatedSize+donl+lengthLength+len(nalu) > maxPayload {
+			p.aggregate(f.Timestamp, aggregated, donl)
+			aggregated, aggregatedSize = aggregated[:0], apHeaderSize
+		}
+		aggregated = append(aggregated, nalu)
+		aggregatedSize += donl + lengthLength + len(nalu)
+	}
+	p.aggregate(f.Timestamp, aggregated, donl)
+
+	if len(p.packets) != 0 {
+		p.packets[len(p.packets)-1].Header.Marker = true
+	}
+
+	return p.packets, nil
+}
+
+// aggregate makes single NAL unit packet or AP packet
+func (p *Packetizer) aggregate(timestamp uint32, nalus [][]byte, donl int) {
+	start := len(p.buf)
+
+	switch len(nalus) {
+	case 0:
+		return
+	case 1:
+		p.buf = append(p.buf, nalus[0][:NALUHeaderLength]...)
+		p.appendDON(donl)
+		p.buf = append(p.buf, nalus[0][NALUHeaderLength:]...)
+		p.appendPacket(timestamp, p.buf[start:len(p.buf):len(p.buf)])
+		return
+	}
+
+	// F bit is set if any unit has it, LayerId and TID are the lowest ones
+	var (
+		f     byte
+		layer = uint16(layerIDMask)
+		tid   = byte(tidMask)
+	)
+	for _, nalu := range nalus {
+		f |= nalu[0] & fBitMask
+		if l := binary.BigEndian.Uint16(nalu) & layerIDMask; l < layer {
+			layer = l
+		}
+		if t := nalu[1] & tidMask; t < tid {
+			tid = t
+		}
+	}
+	header := uint16(f)<<8 | uint16(NALUTypeAP)<<9 | layer | uint16(tid)
+	p.buf = append(p.buf, byte(header>>8), byte(header))
+
+	for i, nalu := range nalus {
+		if donl != 0 {
+			if i == 0 {
+				p.appendDON(donl)
+			} else {
+				// NAL units are consecutive in decoding order
+				p.buf = append(p.buf, 0)
+				p.don++
+			}
+		}
+		p.buf = append(p.buf, byte(len(nalu)>>8), byte(len(nalu)))
+		p.buf = append(p.buf, nalu...)
+	}
+	p.appendPacket(timestamp, p.buf[start:len(p.buf):len(p.buf)])
+}
+
+// fragment splits NAL unit to FU packets
+func (p *Packetizer) fragment(timestamp uint32, nalu []byte, maxPayload int, donl int) {
+	indicator := []byte{nalu[0]&^naluTypeMask | byte(NALUTypeFU)<<1, nalu[1]}
+	header := byte(TypeOf(nalu)) | fuStartMask
+
+	data := nalu[NALUHeaderLength:]
+	for len(data) != 0 {
+		start := len(p.buf)
+		p.buf = append(p.buf, indicator...)
+
+		n := maxPayload - fuHeaderSize
+		if header&fuStartMask != 0 {
+			n -= donl
+		}
+		if n >= len(data) {
+			n = len(data)
+			header |= fuEndMask
+		}
+		p.buf = append(p.buf, header)
+		if header&fuStartMask != 0 {
+			p.appendDON(donl)
+		}
+
+		p.buf = append(p.buf, data[:n]...)
+		p.appendPacket(timestamp, p.buf[start:len(p.buf):len(p.buf)])
+
+		data = data[n:]
+		header &^= fuStartMask
+	}
+}
+
+// appendDON writes DONL field of the next NAL unit
+func (p *Packetizer) appendDON(donl int) {
+	if donl == 0 {
+		return
+	}
+	p.buf = append(p.buf, byte(p.don>>8), byte(p.don))
+	p.don++
+}
+
+func (p *Packetizer) appendPacket(timestamp uint32, payload []byte) {
+	p.packets = append(p.packets, rtp.Packet{
+		Header:  p.Next(timestamp, false),
+		Payload: payload,
+	})
+}
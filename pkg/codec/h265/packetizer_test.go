@@ -0,0 +1,95 @@
+package h265
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPacketizer_Packetize(t *testing.T) {
+	type testCase struct {
+		mtu        int
+		maxDONDiff int
+		frame      []byte
+		payloads   [][]byte
+	}
+
+	testCases := []testCase{
+		// AP
+		{
+			frame: []byte{0, 0, 0, 1, 0x40, 0x01, 0x0c, 0, 0, 0, 1, 0x42, 0x01, 0x01},
+			payloads: [][]byte{
+				{0x60, 0x01, 0x00, 0x03, 0x40, 0x01, 0x0c, 0x00, 0x03, 0x42, 0x01, 0x01},
+			},
+		},
+		// FU
+		{
+			mtu:   rtp.HeaderLength + 5,
+			frame: []byte{0, 0, 0, 1, 0x26, 0x01, 0x01, 0x02, 0x03, 0x04, 0x05},
+			payloads: [][]byte{
+				{0x62, 0x01, 0x93, 0x01, 0x02},
+				{0x62, 0x01, 0x13, 0x03, 0x04},
+				{0x62, 0x01, 0x53, 0x05},
+			},
+		},
+		// DONL
+		{
+			mtu:        rtp.HeaderLength + 7,
+			maxDONDiff: 1,
+			frame:      []byte{0, 0, 0, 1, 0x02, 0x01, 0xaa, 0, 0, 0, 1, 0x26, 0x01, 0x01, 0x02, 0x03, 0x04},
+			payloads: [][]byte{
+				{0x02, 0x01, 0x00, 0x00, 0xaa},
+				{0x62, 0x01, 0x93, 0x00, 0x01, 0x01, 0x02},
+				{0x62, 0x01, 0x53, 0x03, 0x04},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		p := &Packetizer{
+			Sequencer:  codec.Sequencer{PayloadType: 96, SequenceNumber: 10},
+			MTU:        c.mtu,
+			MaxDONDiff: c.maxDONDiff,
+		}
+		packets, err := p.Packetize(codec.Frame{Timestamp: 3000, Data: c.frame})
+		assert.NoError(t, err, "testCase : %d", i+1)
+		if !assert.Len(t, packets, len(c.payloads), "testCase : %d", i+1) {
+			continue
+		}
+
+		for j, packet := range packets {
+			assert.Equal(t, c.payloads[j], packet.Payload, "testCase : %d", i+1)
+			assert.Equal(t, uint16(10+j), packet.Header.SequenceNumber, "testCase : %d", i+1)
+			assert.Equal(t, j == len(packets)-1, packet.Header.Marker, "testCase : %d", i+1)
+		}
+	}
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	frame := []byte{0, 0, 0, 1, 0x40, 0x01, 0x0c, 0, 0, 0, 1, 0x42, 0x01, 0x01, 0, 0, 0, 1, 0x44, 0x01, 0xc0, 0, 0, 0, 1, 0x26, 0x01}
+	for i := 0; i < 5000; i++ {
+		frame = append(frame, byte(i%200+1))
+	}
+
+	for _, maxDONDiff := range []int{0, 3} {
+		p := NewPacketizer(96)
+		p.MaxDONDiff = maxDONDiff
+		d := &Depacketizer{MaxDONDiff: maxDONDiff}
+
+		packets, err := p.Packetize(codec.Frame{Timestamp: 3000, Data: frame})
+		assert.NoError(t, err)
+
+		var frames []codec.Frame
+		for i := range packets {
+			result, err := d.Depacketize(&packets[i])
+			assert.NoError(t, err)
+			frames = append(frames, result...)
+		}
+
+		if assert.Len(t, frames, 1) {
+			assert.True(t, frames[0].Key)
+			assert.Equal(t, frame, frames[0].Data)
+		}
+	}
+}
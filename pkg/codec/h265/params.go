@@ -0,0 +1,74 @@
+package h265
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+// MaxDONDiff returns sprop-max-don-diff fmtp parameter. DONL fields are presented in payloads if it is greater than 0
+func MaxDONDiff(fmtp sdp.FMTP) int {
+	v, _ := fmtp.Int("sprop-max-don-diff")
+	return v
+}
+
+// ParameterSets contains parameter sets of the stream
+type ParameterSets struct {
+	VPS []byte
+	SPS []byte
+	PPS []byte
+}
+
+// ParseParameterSets decodes sprop-vps, sprop-sps and sprop-pps fmtp parameters. Only the first parameter set
+// of each type is used
+func ParseParameterSets(fmtp sdp.FMTP) (ParameterSets, error) {
+	var (
+		ps  ParameterSets
+		err error
+	)
+
+	if ps.VPS, err = parseParameterSet(fmtp, "sprop-vps"); err != nil {
+		return ps, err
+	}
+	if ps.SPS, err = parseParameterSet(fmtp, "sprop-sps"); err != nil {
+		return ps, err
+	}
+	if ps.PPS, err = parseParameterSet(fmtp, "sprop-pps"); err != nil {
+		return ps, err
+	}
+	return ps, nil
+}
+
+func parseParameterSet(fmtp sdp.FMTP, key string) ([]byte, error) {
+	value, ok := fmtp.Get(key)
+	if !ok || value == "" {
+		return nil, nil
+	}
+	if i := strings.IndexByte(value, ','); i >= 0 {
+		value = value[:i]
+	}
+
+	nalu, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s failed: %w", key, err)
+	}
+	if len(nalu) < NALUHeaderLength {
+		return nil, ErrMalformedPacket{Reason: key + " is too short"}
+	}
+	return nalu, nil
+}
+
+// FMTP sets sprop-vps, sprop-sps and sprop-pps fmtp parameters
+func (ps ParameterSets) FMTP(fmtp sdp.FMTP) {
+	if ps.VPS != nil {
+		fmtp["sprop-vps"] = base64.StdEncoding.EncodeToString(ps.VPS)
+	}
+	if ps.SPS != nil {
+		fmtp["sprop-sps"] = base64.StdEncoding.EncodeToString(ps.SPS)
+	}
+	if ps.PPS != nil {
+		fmtp["sprop-pps"] = base64.StdEncoding.EncodeToString(ps.PPS)
+	}
+}
@@ -0,0 +1,143 @@
+package mjpeg
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// ClockRate is an RTP clock rate of JPEG streams
+	ClockRate = 90000
+
+	// MaxFrameSize limits memory consumed by single frame
+	MaxFrameSize = 16 * 1024 * 1024
+)
+
+type quantization struct {
+	precision uint8
+	tables    [][]byte
+}
+
+// Depacketizer reassembles JPEG frames from RTP packets (RFC 2435) and rebuilds JFIF images with standard
+// Huffman tables. Frames with lost packets are dropped
+type Depacketizer struct {
+	// Header is a header of the first packet of the current frame
+	Header Header
+
+	// tables received in-band for Q 128-254, they may be omitted in the following frames
+	tables map[uint8]quantization
+
+	img       image
+	timestamp uint32
+	active    bool
+	broken    bool
+
+	seq     uint16
+	started bool
+}
+
+// Depacketize processes RTP packet and returns JPEG image completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	lost := d.started && p.Header.SequenceNumber != d.seq+1
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+
+	var h Header
+	n, err := h.Parse(p.Payload)
+	if err != nil {
+		d.broken = true
+		return nil, err
+	}
+	payload := p.Payload[n:]
+
+	switch {
+	case h.FragmentOffset == 0:
+		// the previous frame is dropped if it hasn't been completed by marker bit
+		if err = d.start(h, p.Header.Timestamp); err != nil {
+			d.reset()
+			return nil, err
+		}
+	case !d.active:
+		return nil, nil
+	case lost || d.timestamp != p.Header.Timestamp || int(h.FragmentOffset) != len(d.img.scan):
+		d.broken = true
+	}
+
+	if !d.broken {
+		d.img.scan = append(d.img.scan, payload...)
+		if len(d.img.scan) > MaxFrameSize {
+			size := len(d.img.scan)
+			d.reset()
+			return nil, ErrFrameTooLarge{Size: size}
+		}
+	}
+
+	if !p.Header.Marker {
+		return nil, nil
+	}
+
+	defer d.reset()
+	if d.broken {
+		return nil, nil
+	}
+
+	return []codec.Frame{{Timestamp: d.timestamp, Data: buildJPEG(nil, &d.img), Key: true}}, nil
+}
+
+// start begins new frame
+func (d *Depacketizer) start(h Header, timestamp uint32) error {
+	if h.TypeSpecific != 0 {
+		return ErrUnsupportedImage{Reason: "interlaced images"}
+	}
+	if t := h.BaseType(); t != Type422 && t != Type420 {
+		return ErrUnsupportedImage{Reason: "unknown type"}
+	}
+	if h.Width == 0 || h.Height == 0 {
+		return ErrUnsupportedImage{Reason: "zero dimension"}
+	}
+
+	d.Header = h
+	d.img = image{
+		typ:             h.BaseType(),
+		width:           h.Width,
+		height:          h.Height,
+		restartInterval: h.RestartInterval,
+		scan:            d.img.scan[:0],
+	}
+
+	switch {
+	case h.Q < QDynamic:
+		luma, chroma := MakeTables(h.Q)
+		d.img.tables = [][]byte{luma, chroma}
+	case len(h.Tables) != 0:
+		d.img.precision = h.Precision
+		d.img.tables = make([][]byte, len(h.Tables))
+		for i, table := range h.Tables {
+			d.img.tables[i] = append([]byte(nil), table...)
+		}
+		if h.Q != 255 {
+			if d.tables == nil {
+				d.tables = map[uint8]quantization{}
+			}
+			d.tables[h.Q] = quantization{precision: d.img.precision, tables: d.img.tables}
+		}
+	default:
+		cached, ok := d.tables[h.Q]
+		if !ok {
+			return ErrMalformedPacket{Reason: "quantization tables are absent"}
+		}
+		d.img.precision = cached.precision
+		d.img.tables = cached.tables
+	}
+
+	d.timestamp = timestamp
+	d.active = true
+	d.broken = false
+	return nil
+}
+
+func (d *Depacketizer) reset() {
+	d.img.scan = d.img.scan[:0]
+	d.active = false
+	d.broken = false
+}
@@ -0,0 +1,30 @@
+package mjpeg
+
+import "fmt"
+
+// ErrMalformedPacket happens when payload cannot be parsed
+type ErrMalformedPacket struct {
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed JPEG payload: %s", e.Reason)
+}
+
+// ErrUnsupportedImage happens when image cannot be described by RFC 2435 headers
+type ErrUnsupportedImage struct {
+	Reason string
+}
+
+func (e ErrUnsupportedImage) Error() string {
+	return fmt.Sprintf("unsupported JPEG image: %s", e.Reason)
+}
+
+// ErrFrameTooLarge happens when frame exceeds MaxFrameSize
+type ErrFrameTooLarge struct {
+	Size int
+}
+
+func (e ErrFrameTooLarge) Error() string {
+	return fmt.Sprintf("frame too large: %d > %d", e.Size, MaxFrameSize)
+}
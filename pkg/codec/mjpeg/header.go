@@ -0,0 +1,157 @@
+package mjpeg
+
+import "encoding/binary"
+
+const (
+	// HeaderLength is a size of JPEG main header
+	HeaderLength = 8
+	// RestartHeaderLength is a size of restart marker header
+	RestartHeaderLength = 4
+	// QuantizationHeaderLength is a size of quantization table header without tables
+	QuantizationHeaderLength = 4
+
+	// MaxDimension is a maximum width and height which can be described by main header
+	MaxDimension = 2040
+
+	// TypeRestartFlag is added to type if restart marker header is presented
+	TypeRestartFlag = 64
+
+	// QDynamic is a minimal Q value which means that quantization table header is presented
+	QDynamic = 128
+
+	restartCountMask = 0x3FFF
+	restartFirst     = 0x8000
+	restartLast      = 0x4000
+)
+
+// Type is a JPEG type of main header (RFC 2435 section 4.1)
+type Type uint8
+
+const (
+	// Type422 means YUV 4:2:2 sampling: Y is 2x1, U and V are 1x1
+	Type422 Type = 0
+	// Type420 means YUV 4:2:0 sampling: Y is 2x2, U and V are 1x1
+	Type420 Type = 1
+)
+
+// Header represents JPEG main header, restart marker header and quantization table header
+type Header struct {
+	TypeSpecific   uint8
+	FragmentOffset uint32
+	Type           Type
+	Q              uint8
+	Width          int
+	Height         int
+
+	// RestartInterval is presented if type is 64-127
+	RestartInterval uint16
+	RestartFirst    bool
+	RestartLast     bool
+	RestartCount    uint16
+
+	// Precision is a bit mask of 16-bit tables
+	Precision uint8
+	// Tables are quantization tables in zig-zag order, they are presented in the first packet if Q >= 128
+	Tables [][]byte
+}
+
+// HasRestart returns true if restart marker header is presented
+func (h Header) HasRestart() bool {
+	return h.Type >= TypeRestartFlag && h.Type < QDynamic
+}
+
+// BaseType returns type without restart flag
+func (h Header) BaseType() Type {
+	if h.HasRestart() {
+		return h.Type - TypeRestartFlag
+	}
+	return h.Type
+}
+
+// Parse parses headers of RTP payload and returns their size
+func (h *Header) Parse(buf []byte) (int, error) {
+	*h = Header{}
+	if len(buf) < HeaderLength {
+		return 0, ErrMalformedPacket{Reason: "main header is truncated"}
+	}
+
+	h.TypeSpecific = buf[0]
+	h.FragmentOffset = uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3])
+	h.Type = Type(buf[4])
+	h.Q = buf[5]
+	h.Width = int(buf[6]) * 8
+	h.Height = int(buf[7]) * 8
+	n := HeaderLength
+
+	if h.HasRestart() {
+		if len(buf) < n+RestartHeaderLength {
+			return 0, ErrMalformedPacket{Reason: "restart marker header is truncated"}
+		}
+		h.RestartInterval = binary.BigEndian.Uint16(buf[n:])
+		v := binary.BigEndian.Uint16(buf[n+2:])
+		h.RestartFirst = v&restartFirst != 0
+		h.RestartLast = v&restartLast != 0
+		h.RestartCount = v & restartCountMask
+		n += RestartHeaderLength
+	}
+
+	if h.Q >= QDynamic && h.FragmentOffset == 0 {
+		if len(buf) < n+QuantizationHeaderLength {
+			return 0, ErrMalformedPacket{Reason: "quantization table header is truncated"}
+		}
+		h.Precision = buf[n+1]
+		length := int(binary.BigEndian.Uint16(buf[n+2:]))
+		n += QuantizationHeaderLength
+		if len(buf) < n+length {
+			return 0, ErrMalformedPacket{Reason: "quantization tables are truncated"}
+		}
+
+		tables := buf[n : n+length]
+		for i := 0; len(tables) != 0; i++ {
+			size := 64
+			if h.Precision>>i&1 != 0 {
+				size = 128
+			}
+			if len(tables) < size {
+				return 0, ErrMalformedPacket{Reason: "quantization table is truncated"}
+			}
+			h.Tables = append(h.Tables, tables[:size])
+			tables = tables[size:]
+		}
+		n += length
+	}
+
+	return n, nil
+}
+
+// Append composes headers to the end of buffer. Quantization tables are added if they aren't empty
+func (h Header) Append(buf []byte) []byte {
+	buf = append(buf,
+		h.TypeSpecific,
+		byte(h.FragmentOffset>>16), byte(h.FragmentOffset>>8), byte(h.FragmentOffset),
+		byte(h.Type), h.Q, byte(h.Width/8), byte(h.Height/8))
+
+	if h.HasRestart() {
+		v := h.RestartCount & restartCountMask
+		if h.RestartFirst {
+			v |= restartFirst
+		}
+		if h.RestartLast {
+			v |= restartLast
+		}
+		buf = append(buf, byte(h.RestartInterval>>8), byte(h.RestartInterval), byte(v>>8), byte(v))
+	}
+
+	if h.Q >= QDynamic && len(h.Tables) != 0 {
+		length := 0
+		for _, table := range h.Tables {
+			length += len(table)
+		}
+		buf = append(buf, 0, h.Precision, byte(length>>8), byte(length))
+		for _, table := range h.Tables {
+			buf = append(buf, table...)
+		}
+	}
+
+	return buf
+}
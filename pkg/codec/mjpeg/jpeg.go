@@ -0,0 +1,191 @@
+package mjpeg
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// JPEG markers
+const (
+	markerPrefix = 0xFF
+	markerSOI    = 0xD8
+	markerEOI    = 0xD9
+	markerSOF0   = 0xC0
+	markerSOF1   = 0xC1
+	markerDHT    = 0xC4
+	markerDQT    = 0xDB
+	markerDRI    = 0xDD
+	markerSOS    = 0xDA
+	markerAPP0   = 0xE0
+)
+
+var jfifHeader = []byte{'J', 'F', 'I', 'F', 0x00, 0x01, 0x01, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00}
+
+// image describes baseline JPEG image in terms of RFC 2435
+type image struct {
+	typ             Type
+	width           int
+	height          int
+	restartInterval uint16
+	precision       uint8
+	// tables are luma and chroma quantization tables in zig-zag order
+	tables [][]byte
+	scan   []byte
+}
+
+// buildJPEG composes JFIF image with standard Huffman tables
+func buildJPEG(buf []byte, img *image) []byte {
+	buf = append(buf, markerPrefix, markerSOI)
+	buf = appendSegment(buf, markerAPP0, jfifHeader)
+
+	for i, table := range img.tables {
+		buf = append(buf, markerPrefix, markerDQT)
+		buf = appendUint16(buf, uint16(2+1+len(table)))
+		buf = append(buf, img.precision>>i&1<<4|byte(i))
+		buf = append(buf, table...)
+	}
+
+	// components use the first table for luma and the second one for chroma if it is presented
+	chromaTable := byte(len(img.tables) - 1)
+	if chromaTable > 1 {
+		chromaTable = 1
+	}
+	lumaSampling := byte(0x21)
+	if img.typ == Type420 {
+		lumaSampling = 0x22
+	}
+	buf = appendSegment(buf, markerSOF0, []byte{
+		8,
+		byte(img.height >> 8), byte(img.height),
+		byte(img.width >> 8), byte(img.width),
+		3,
+		0, lumaSampling, 0,
+		1, 0x11, chromaTable,
+		2, 0x11, chromaTable,
+	})
+
+	for _, t := range standardHuffmanTables {
+		buf = append(buf, markerPrefix, markerDHT)
+		buf = appendUint16(buf, uint16(2+1+len(t.lengths)+len(t.symbols)))
+		buf = append(buf, t.class<<4|t.id)
+		buf = append(buf, t.lengths[:]...)
+		buf = append(buf, t.symbols...)
+	}
+
+	if img.restartInterval != 0 {
+		buf = appendSegment(buf, markerDRI, []byte{byte(img.restartInterval >> 8), byte(img.restartInterval)})
+	}
+
+	buf = appendSegment(buf, markerSOS, []byte{3, 0, 0x00, 1, 0x11, 2, 0x11, 0, 63, 0})
+	buf = append(buf, img.scan...)
+
+	if n := len(buf); n < 2 || buf[n-2] != markerPrefix || buf[n-1] != markerEOI {
+		buf = append(buf, markerPrefix, markerEOI)
+	}
+	return buf
+}
+
+func appendSegment(buf []byte, marker byte, data []byte) []byte {
+	buf = append(buf, markerPrefix, marker)
+	buf = appendUint16(buf, uint16(2+len(data)))
+	return append(buf, data...)
+}
+
+// parseJPEG parses baseline JPEG image with 3 components. Huffman tables are supposed to be standard ones
+func parseJPEG(buf []byte) (*image, error) {
+	if len(buf) < 2 || buf[0] != markerPrefix || buf[1] != markerSOI {
+		return nil, ErrUnsupportedImage{Reason: "SOI marker is absent"}
+	}
+	buf = buf[2:]
+
+	var (
+		img        image
+		tables     [4][]byte
+		precisions [4]bool
+		selectors  [2]byte
+		hasFrame   bool
+	)
+
+	for {
+		if len(buf) < 4 || buf[0] != markerPrefix {
+			return nil, ErrUnsupportedImage{Reason: "marker is expected"}
+		}
+		marker := buf[1]
+		length := int(binary.BigEndian.Uint16(buf[2:]))
+		if length < 2 || len(buf) < 2+length {
+			return nil, ErrUnsupportedImage{Reason: fmt.Sprintf("segment 0x%X is truncated", marker)}
+		}
+		data := buf[4 : 2+length]
+		buf = buf[2+length:]
+
+		switch marker {
+		case markerDQT:
+			for len(data) != 0 {
+				precision, id := data[0]>>4, data[0]&0x0F
+				size := 64 * (int(precision) + 1)
+				if id > 3 || precision > 1 || len(data) < 1+size {
+					return nil, ErrUnsupportedImage{Reason: "malformed DQT"}
+				}
+				tables[id] = data[1 : 1+size]
+				precisions[id] = precision == 1
+				data = data[1+size:]
+			}
+
+		case markerSOF0, markerSOF1:
+			if len(data) < 15 || data[0] != 8 || data[5] != 3 {
+				return nil, ErrUnsupportedImage{Reason: "only 8-bit images with 3 components are supported"}
+			}
+			img.height = int(binary.BigEndian.Uint16(data[1:]))
+			img.width = int(binary.BigEndian.Uint16(data[3:]))
+			switch data[7] {
+			case 0x21:
+				img.typ = Type422
+			case 0x22:
+				img.typ = Type420
+			default:
+				return nil, ErrUnsupportedImage{Reason: fmt.Sprintf("luma sampling 0x%02X", data[7])}
+			}
+			if data[10] != 0x11 || data[13] != 0x11 || data[11] != data[14] {
+				return nil, ErrUnsupportedImage{Reason: "chroma components must have the same 1x1 sampling"}
+			}
+			selectors = [2]byte{data[8] & 0x03, data[11] & 0x03}
+			hasFrame = true
+
+		case markerDRI:
+			if len(data) < 2 {
+				return nil, ErrUnsupportedImage{Reason: "malformed DRI"}
+			}
+			img.restartInterval = binary.BigEndian.Uint16(data)
+
+		case markerSOS:
+			if !hasFrame {
+				return nil, ErrUnsupportedImage{Reason: "SOS before baseline SOF"}
+			}
+			for i, id := range selectors {
+				if tables[id] == nil {
+					return nil, ErrUnsupportedImage{Reason: "quantization table is absent"}
+				}
+				if precisions[id] {
+					img.precision |= 1 << i
+				}
+				img.tables = append(img.tables, tables[id])
+			}
+
+			// entropy coded data lasts until EOI
+			if n := len(buf); n >= 2 && buf[n-2] == markerPrefix && buf[n-1] == markerEOI {
+				buf = buf[:n-2]
+			}
+			img.scan = buf
+			return &img, nil
+
+		default:
+			if marker >= 0xC2 && marker <= 0xCF && marker != markerDHT && marker != 0xC8 && marker != 0xCC {
+				return nil, ErrUnsupportedImage{Reason: "only baseline sequential images are supported"}
+			}
+		}
+	}
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
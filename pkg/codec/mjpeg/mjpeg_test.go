@@ -0,0 +1,149 @@
+package mjpeg
+
+import (
+	"bytes"
+	goimage "image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func testImage(t *testing.T, quality int) ([]byte, goimage.Image) {
+	img := goimage.NewRGBA(goimage.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 5), B: uint8(x * y), A: 0xFF})
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}))
+
+	decoded, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	return buf.Bytes(), decoded
+}
+
+func depacketize(t *testing.T, d *Depacketizer, packets []rtp.Packet) []codec.Frame {
+	var frames []codec.Frame
+	for i := range packets {
+		result, err := d.Depacketize(&packets[i])
+		assert.NoError(t, err)
+		frames = append(frames, result...)
+	}
+	return frames
+}
+
+func TestHeader_Parse(t *testing.T) {
+	type testCase struct {
+		header Header
+		size   int
+	}
+
+	testCases := []testCase{
+		{
+			header: Header{FragmentOffset: 0x010203, Type: Type420, Q: 50, Width: 640, Height: 480},
+			size:   HeaderLength,
+		},
+		{
+			header: Header{
+				Type:            Type422 + TypeRestartFlag,
+				Q:               QInBand,
+				Width:           320,
+				Height:          240,
+				RestartInterval: 10,
+				RestartFirst:    true,
+				RestartCount:    5,
+				Precision:       2,
+				Tables:          [][]byte{make([]byte, 64), make([]byte, 128)},
+			},
+			size: HeaderLength + RestartHeaderLength + QuantizationHeaderLength + 192,
+		},
+	}
+
+	for i, c := range testCases {
+		buf := c.header.Append(nil)
+		assert.Len(t, buf, c.size, "testCase : %d", i+1)
+
+		var h Header
+		n, err := h.Parse(append(buf, 0x01))
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.size, n, "testCase : %d", i+1)
+		assert.Equal(t, c.header, h, "testCase : %d", i+1)
+	}
+
+	var h Header
+	_, err := h.Parse([]byte{0, 0, 0, 0, 1, 255, 8, 8, 0, 0, 0, 64})
+	assert.IsType(t, ErrMalformedPacket{}, err)
+}
+
+func TestMakeTables(t *testing.T) {
+	luma, chroma := MakeTables(50)
+	assert.Equal(t, []byte{16, 11, 12, 14, 12, 10, 16, 14}, luma[:8])
+	assert.Equal(t, []byte{17, 18, 18, 24, 21, 24, 47, 26}, chroma[:8])
+
+	luma, chroma = MakeTables(5)
+	assert.Equal(t, byte(160), luma[0])
+	assert.Equal(t, byte(255), chroma[63])
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	data, expected := testImage(t, 75)
+
+	p := NewPacketizer()
+	p.MTU = 200
+	packets, err := p.Packetize(codec.Frame{Timestamp: 3000, Data: data})
+	assert.NoError(t, err)
+	assert.True(t, len(packets) > 1)
+
+	frames := depacketize(t, &Depacketizer{}, packets)
+	if !assert.Len(t, frames, 1) {
+		return
+	}
+	assert.Equal(t, p.InitialTimestamp+3000, frames[0].Timestamp)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(frames[0].Data))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, decoded)
+}
+
+func TestDepacketizer_QFactor(t *testing.T) {
+	// Go encoder scales standard tables by the same formula
+	data, expected := testImage(t, 75)
+	img, err := parseJPEG(data)
+	assert.NoError(t, err)
+
+	h := Header{Type: img.typ, Q: 75, Width: img.width, Height: img.height}
+	var packets []rtp.Packet
+	for i, scan := 0, img.scan; len(scan) != 0; i++ {
+		n := 100
+		if n > len(scan) {
+			n = len(scan)
+		}
+		packets = append(packets, rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: uint16(i), Timestamp: 9000, Marker: n == len(scan)},
+			Payload: append(h.Append(nil), scan[:n]...),
+		})
+		h.FragmentOffset += uint32(n)
+		scan = scan[n:]
+	}
+
+	d := &Depacketizer{}
+	frames := depacketize(t, d, packets)
+	if assert.Len(t, frames, 1) {
+		decoded, err := jpeg.Decode(bytes.NewReader(frames[0].Data))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, decoded)
+	}
+
+	// lost packet drops frame
+	for i := range packets {
+		packets[i].Header.SequenceNumber += 100
+	}
+	frames = depacketize(t, d, append(packets[:1:1], packets[2:]...))
+	assert.Empty(t, frames)
+}
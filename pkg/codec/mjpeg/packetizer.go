@@ -0,0 +1,92 @@
+package mjpeg
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// QInBand is a Q value which means that quantization tables are sent with every frame
+const QInBand = 255
+
+// Packetizer splits baseline JPEG images to RTP packets (RFC 2435). Quantization tables are sent in-band,
+// Huffman tables must be standard ones
+type Packetizer struct {
+	codec.Sequencer
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer() *Packetizer {
+	return &Packetizer{Sequencer: codec.NewSequencer(rtp.PayloadTypeJPEG)}
+}
+
+// Packetize splits JPEG image to RTP packets. Frame timestamp is counted in 90 kHz units from the stream start.
+// Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+
+	img, err := parseJPEG(f.Data)
+	if err != nil {
+		return nil, err
+	}
+	if img.width == 0 || img.height == 0 || img.width > MaxDimension || img.height > MaxDimension ||
+		img.width%8 != 0 || img.height%8 != 0 {
+		return nil, ErrUnsupportedImage{Reason: "dimensions must be multiple of 8 up to 2040"}
+	}
+
+	h := Header{
+		Type:      img.typ,
+		Q:         QInBand,
+		Width:     img.width,
+		Height:    img.height,
+		Precision: img.precision,
+		Tables:    img.tables,
+	}
+	if img.restartInterval != 0 {
+		// fragments aren't aligned to restart intervals
+		h.Type += TypeRestartFlag
+		h.RestartInterval = img.restartInterval
+		h.RestartFirst = true
+		h.RestartLast = true
+		h.RestartCount = restartCountMask
+	}
+
+	firstSize := len(h.Append(nil))
+	if mtu-rtp.HeaderLength-firstSize <= 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	for scan := img.scan; len(scan) != 0; {
+		start := len(p.buf)
+		p.buf = h.Append(p.buf)
+
+		n := mtu - rtp.HeaderLength - (len(p.buf) - start)
+		if n > len(scan) {
+			n = len(scan)
+		}
+		p.buf = append(p.buf, scan[:n]...)
+		scan = scan[n:]
+
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(f.Timestamp, len(scan) == 0),
+			Payload: p.buf[start:len(p.buf):len(p.buf)],
+		})
+
+		h.FragmentOffset += uint32(n)
+		h.Tables = nil
+	}
+
+	return p.packets, nil
+}
@@ -0,0 +1,315 @@
+package mpegts
+
+import "sort"
+
+const (
+	tableIDPAT = 0x00
+	tableIDPMT = 0x02
+
+	// MaxPESSize limits memory consumed by single PES packet
+	MaxPESSize = 8 * 1024 * 1024
+
+	ptsMask = 1<<33 - 1
+)
+
+// Stream describes elementary stream of program
+type Stream struct {
+	PID  uint16
+	Type StreamType
+}
+
+// PES is a reassembled PES packet of elementary stream
+type PES struct {
+	Stream
+
+	// PTS and DTS are counted in 90 kHz units, DTS equals PTS if it is absent
+	HasPTS bool
+	PTS    int64
+	DTS    int64
+
+	// RandomAccess is set if the first TS packet of PES has random access indicator
+	RandomAccess bool
+
+	// Data is an elementary stream data
+	Data []byte
+}
+
+type pesBuffer struct {
+	data         []byte
+	length       int
+	randomAccess bool
+	active       bool
+}
+
+// Demuxer extracts PES packets of elementary streams from transport stream. Programs are discovered with PAT
+// and PMT tables. PES packets with lost TS packets are dropped
+type Demuxer struct {
+	pmts    map[uint16]bool
+	streams map[uint16]StreamType
+	pes     map[uint16]*pesBuffer
+
+	// sections are PSI sections which are being assembled
+	sections map[uint16][]byte
+
+	counters map[uint16]uint8
+}
+
+// NewDemuxer creates transport stream demuxer
+func NewDemuxer() *Demuxer {
+	return &Demuxer{
+		pmts:     map[uint16]bool{},
+		streams:  map[uint16]StreamType{},
+		pes:      map[uint16]*pesBuffer{},
+		sections: map[uint16][]byte{},
+		counters: map[uint16]uint8{},
+	}
+}
+
+// Streams returns elementary streams discovered by PMT tables
+func (d *Demuxer) Streams() []Stream {
+	streams := make([]Stream, 0, len(d.streams))
+	for pid, t := range d.streams {
+		streams = append(streams, Stream{PID: pid, Type: t})
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].PID < streams[j].PID })
+	return streams
+}
+
+// Demux processes transport stream packet and returns PES packets completed by it
+func (d *Demuxer) Demux(buf []byte) ([]*PES, error) {
+	h, payload, err := ParsePacket(buf)
+	if err != nil {
+		return nil, err
+	}
+	if h.TransportError || h.PID == PIDNull {
+		return nil, nil
+	}
+
+	duplicate, err := d.checkContinuity(h)
+	if duplicate {
+		return nil, nil
+	}
+	if err != nil {
+		delete(d.sections, h.PID)
+		if pes := d.pes[h.PID]; pes != nil {
+			pes.active = false
+		}
+		if !h.PayloadUnitStart {
+			return nil, err
+		}
+	}
+	if len(payload) == 0 {
+		return nil, err
+	}
+
+	switch {
+	case h.PID == PIDPAT || d.pmts[h.PID]:
+		if perr := d.processSection(h, payload); perr != nil {
+			return nil, perr
+		}
+		return nil, err
+	case d.streams[h.PID] != 0:
+		result, perr := d.processPES(h, payload)
+		if perr != nil {
+			return result, perr
+		}
+		return result, err
+	}
+
+	return nil, err
+}
+
+// Flush returns PES packets of unknown length which are collected so far
+func (d *Demuxer) Flush() []*PES {
+	var result []*PES
+	for pid, pes := range d.pes {
+		if p := d.completePES(pid, pes); p != nil {
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PID < result[j].PID })
+	return result
+}
+
+// checkContinuity returns true if the packet duplicates the previous one, its payload must be dropped
+func (d *Demuxer) checkContinuity(h PacketHeader) (bool, error) {
+	last, ok := d.counters[h.PID]
+	if !h.HasPayload {
+		// counter isn't incremented without payload
+		return false, nil
+	}
+	d.counters[h.PID] = h.ContinuityCounter
+
+	if !ok || h.Discontinuity {
+		return false, nil
+	}
+	// duplicate packet is sent with the same counter (ISO/IEC 13818-1 section 2.4.3.3)
+	if h.ContinuityCounter == last {
+		return true, nil
+	}
+	if expected := (last + 1) & 0x0F; h.ContinuityCounter != expected {
+		return false, ErrContinuity{PID: h.PID, Expected: expected, Actual: h.ContinuityCounter}
+	}
+	return false, nil
+}
+
+func (d *Demuxer) processSection(h PacketHeader, payload []byte) error {
+	section := d.sections[h.PID]
+	if h.PayloadUnitStart {
+		pointer := int(payload[0])
+		if 1+pointer > len(payload) {
+			return ErrMalformedPacket{Reason: "pointer field exceeds payload"}
+		}
+		section = append(section[:0], payload[1+pointer:]...)
+	} else if section != nil {
+		section = append(section, payload...)
+	} else {
+		return nil
+	}
+	d.sections[h.PID] = section
+
+	if len(section) < 3 {
+		return nil
+	}
+	length := int(section[1]&0x0F)<<8 | int(section[2])
+	if len(section) < 3+length {
+		return nil
+	}
+	delete(d.sections, h.PID)
+
+	// table_id_extension, version, section numbers and CRC
+	if length < 9 {
+		return ErrMalformedPacket{Reason: "section is too short"}
+	}
+	data := section[8 : 3+length-4]
+
+	switch section[0] {
+	case tableIDPAT:
+		for ; len(data) >= 4; data = data[4:] {
+			program := uint16(data[0])<<8 | uint16(data[1])
+			pid := uint16(data[2])<<8&pidMask | uint16(data[3])
+			// program 0 refers to network information table
+			if program != 0 {
+				d.pmts[pid] = true
+			}
+		}
+
+	case tableIDPMT:
+		if len(data) < 4 {
+			return ErrMalformedPacket{Reason: "PMT is too short"}
+		}
+		infoLength := int(data[2]&0x0F)<<8 | int(data[3])
+		if 4+infoLength > len(data) {
+			return ErrMalformedPacket{Reason: "program info is truncated"}
+		}
+		data = data[4+infoLength:]
+
+		for len(data) >= 5 {
+			t := StreamType(data[0])
+			pid := uint16(data[1])<<8&pidMask | uint16(data[2])
+			esInfoLength := int(data[3]&0x0F)<<8 | int(data[4])
+			if 5+esInfoLength > len(data) {
+				return ErrMalformedPacket{Reason: "ES info is truncated"}
+			}
+			d.streams[pid] = t
+			data = data[5+esInfoLength:]
+		}
+	}
+
+	return nil
+}
+
+func (d *Demuxer) processPES(h PacketHeader, payload []byte) ([]*PES, error) {
+	pes := d.pes[h.PID]
+	if pes == nil {
+		pes = &pesBuffer{}
+		d.pes[h.PID] = pes
+	}
+
+	var result []*PES
+	if h.PayloadUnitStart {
+		if p := d.completePES(h.PID, pes); p != nil {
+			result = append(result, p)
+		}
+
+		if len(payload) < 6 || payload[0] != 0 || payload[1] != 0 || payload[2] != 1 {
+			return result, ErrMalformedPacket{Reason: "PES start code is absent"}
+		}
+		pes.data = append(pes.data[:0], payload...)
+		pes.length = int(payload[4])<<8 | int(payload[5])
+		pes.randomAccess = h.RandomAccess
+		pes.active = true
+	} else if pes.active {
+		pes.data = append(pes.data, payload...)
+	} else {
+		return nil, nil
+	}
+
+	if len(pes.data) > MaxPESSize {
+		pes.active = false
+		return result, ErrMalformedPacket{Reason: "PES packet is too large"}
+	}
+
+	// PES of known length is completed without waiting for the next one
+	if pes.length != 0 && len(pes.data) >= 6+pes.length {
+		pes.data = pes.data[:6+pes.length]
+		if p := d.completePES(h.PID, pes); p != nil {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+func (d *Demuxer) completePES(pid uint16, pes *pesBuffer) *PES {
+	if !pes.active {
+		return nil
+	}
+	pes.active = false
+
+	buf := pes.data
+	if pes.length != 0 && len(buf) < 6+pes.length {
+		return nil
+	}
+
+	p := &PES{
+		Stream:       Stream{PID: pid, Type: d.streams[pid]},
+		RandomAccess: pes.randomAccess,
+	}
+
+	// PES header is absent for some stream IDs, e.g. padding and private stream 2
+	streamID := buf[3]
+	if streamID == 0xBC || streamID == 0xBE || streamID == 0xBF || streamID == 0xF0 || streamID == 0xF1 ||
+		streamID == 0xFF || streamID == 0xF2 || streamID == 0xF8 {
+		p.Data = append([]byte(nil), buf[6:]...)
+		return p
+	}
+
+	if len(buf) < 9 {
+		return nil
+	}
+	flags := buf[7]
+	headerLength := int(buf[8])
+	if 9+headerLength > len(buf) {
+		return nil
+	}
+	header := buf[9 : 9+headerLength]
+
+	if flags&0x80 != 0 && len(header) >= 5 {
+		p.HasPTS = true
+		p.PTS = parseTimestamp(header)
+		p.DTS = p.PTS
+		if flags&0x40 != 0 && len(header) >= 10 {
+			p.DTS = parseTimestamp(header[5:])
+		}
+	}
+
+	p.Data = append([]byte(nil), buf[9+headerLength:]...)
+	return p
+}
+
+// parseTimestamp decodes 33-bit PTS or DTS field
+func parseTimestamp(buf []byte) int64 {
+	return (int64(buf[0]>>1&0x07)<<30 | int64(buf[1])<<22 | int64(buf[2]>>1)<<15 | int64(buf[3])<<7 |
+		int64(buf[4]>>1)) & ptsMask
+}
@@ -0,0 +1,32 @@
+package mpegts
+
+import "fmt"
+
+// ErrMalformedPacket happens when transport stream packet or table cannot be parsed
+type ErrMalformedPacket struct {
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed MPEG-TS data: %s", e.Reason)
+}
+
+// ErrContinuity happens when continuity counter of PID is unexpected, it means that packets are lost
+type ErrContinuity struct {
+	PID      uint16
+	Expected uint8
+	Actual   uint8
+}
+
+func (e ErrContinuity) Error() string {
+	return fmt.Sprintf("continuity counter of PID %d mismatch: %d != %d", e.PID, e.Actual, e.Expected)
+}
+
+// ErrMisalignedPayload happens when RTP payload size isn't multiple of 188
+type ErrMisalignedPayload struct {
+	Size int
+}
+
+func (e ErrMisalignedPayload) Error() string {
+	return fmt.Sprintf("payload size %d is not multiple of %d", e.Size, PacketSize)
+}
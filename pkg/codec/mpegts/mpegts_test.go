@@ -0,0 +1,187 @@
+package mpegts
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// testPacket makes TS packet, payload is padded by adaptation field
+func testPacket(pid uint16, start bool, cc uint8, randomAccess bool, payload []byte) []byte {
+	buf := []byte{SyncByte, byte(pid >> 8), byte(pid), 0x10 | cc&0x0F}
+	if start {
+		buf[1] |= 0x40
+	}
+
+	stuffing := PacketSize - 4 - len(payload)
+	if stuffing > 0 || randomAccess {
+		buf[3] |= 0x20
+		length := stuffing - 1
+		if length < 1 {
+			length = 1
+		}
+		buf = append(buf, byte(length))
+		var flags byte
+		if randomAccess {
+			flags = 0x40
+		}
+		buf = append(buf, flags)
+		for i := 1; i < length; i++ {
+			buf = append(buf, 0xFF)
+		}
+	}
+	return append(buf, payload...)[:PacketSize]
+}
+
+func testSection(tableID byte, data []byte) []byte {
+	length := 5 + len(data) + 4
+	section := []byte{0x00, tableID, 0xB0 | byte(length>>8), byte(length), 0x00, 0x01, 0xC1, 0x00, 0x00}
+	section = append(section, data...)
+	return append(section, 0, 0, 0, 0)
+}
+
+func testPES(streamID byte, pts, dts int64, data []byte) []byte {
+	header := []byte{0x21 | byte(pts>>29)&0x0E, byte(pts >> 22), 0x01 | byte(pts>>14), byte(pts >> 7), 0x01 | byte(pts<<1)}
+	flags := byte(0x80)
+	if dts >= 0 {
+		header[0] |= 0x10
+		header = append(header, 0x11|byte(dts>>29)&0x0E, byte(dts>>22), 0x01|byte(dts>>14), byte(dts>>7), 0x01|byte(dts<<1))
+		flags |= 0x40
+	}
+
+	length := 3 + len(header) + len(data)
+	if streamID >= 0xE0 {
+		length = 0
+	}
+	pes := []byte{0x00, 0x00, 0x01, streamID, byte(length >> 8), byte(length), 0x80, flags, byte(len(header))}
+	pes = append(pes, header...)
+	return append(pes, data...)
+}
+
+func TestDemuxer_Demux(t *testing.T) {
+	video := make([]byte, 300)
+	for i := range video {
+		video[i] = byte(i)
+	}
+	videoPES := testPES(0xE0, 0x1FFFFFFFF, 0x1FFFFFF00, video)
+	audioPES := testPES(0xC0, 9000, -1, []byte{0xFF, 0xF1, 0x01})
+
+	packets := [][]byte{
+		testPacket(PIDPAT, true, 0, false, testSection(tableIDPAT, []byte{0x00, 0x00, 0xE0, 0x10, 0x00, 0x01, 0xF0, 0x00})),
+		testPacket(0x1000, true, 0, false, testSection(tableIDPMT, []byte{
+			0xE1, 0x00, 0xF0, 0x00,
+			byte(StreamTypeH264), 0xE1, 0x00, 0xF0, 0x00,
+			byte(StreamTypeAACADTS), 0xE1, 0x01, 0xF0, 0x00,
+		})),
+		testPacket(0x100, true, 0, true, videoPES[:182]),
+		testPacket(0x101, true, 5, false, audioPES),
+		testPacket(0x100, false, 1, false, videoPES[182:]),
+		testPacket(0x100, true, 2, false, testPES(0xE0, 0, -1, []byte{0x01})),
+	}
+
+	d := NewDemuxer()
+	var result []*PES
+	for i, packet := range packets {
+		pes, err := d.Demux(packet)
+		assert.NoError(t, err, "packet : %d", i+1)
+		result = append(result, pes...)
+	}
+
+	assert.Equal(t, []Stream{{PID: 0x100, Type: StreamTypeH264}, {PID: 0x101, Type: StreamTypeAACADTS}}, d.Streams())
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, &PES{
+			Stream: Stream{PID: 0x101, Type: StreamTypeAACADTS},
+			HasPTS: true,
+			PTS:    9000,
+			DTS:    9000,
+			Data:   []byte{0xFF, 0xF1, 0x01},
+		}, result[0])
+
+		assert.Equal(t, Stream{PID: 0x100, Type: StreamTypeH264}, result[1].Stream)
+		assert.Equal(t, int64(0x1FFFFFFFF), result[1].PTS)
+		assert.Equal(t, int64(0x1FFFFFF00), result[1].DTS)
+		assert.True(t, result[1].RandomAccess)
+		assert.Equal(t, video, result[1].Data)
+	}
+
+	flushed := d.Flush()
+	if assert.Len(t, flushed, 1) {
+		assert.Equal(t, []byte{0x01}, flushed[0].Data)
+	}
+}
+
+func TestDemuxer_Continuity(t *testing.T) {
+	d := NewDemuxer()
+	d.streams[0x100] = StreamTypeH264
+
+	pes := testPES(0xE0, 0, -1, make([]byte, 400))
+	_, err := d.Demux(testPacket(0x100, true, 0, false, pes[:184]))
+	assert.NoError(t, err)
+
+	_, err = d.Demux(testPacket(0x100, false, 2, false, pes[184:368]))
+	assert.ErrorIs(t, err, ErrContinuity{PID: 0x100, Expected: 1, Actual: 2})
+
+	result, err := d.Demux(testPacket(0x100, true, 3, false, testPES(0xE0, 0, -1, []byte{0x01})))
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	// duplicate packet is ignored by counter check
+	_, err = d.Demux(testPacket(0x100, false, 3, false, []byte{0x02}))
+	assert.NoError(t, err)
+}
+
+func TestDemuxer_Duplicate(t *testing.T) {
+	d := NewDemuxer()
+	d.streams[0x100] = StreamTypeH264
+
+	pes := testPES(0xE0, 0, -1, []byte{0x01, 0x02})
+	packets := [][]byte{
+		testPacket(0x100, true, 0, false, pes),
+		testPacket(0x100, false, 1, false, []byte{0x03, 0x04}),
+		testPacket(0x100, false, 1, false, []byte{0x03, 0x04}),
+		testPacket(0x100, false, 2, false, []byte{0x05}),
+	}
+	for i, packet := range packets {
+		result, err := d.Demux(packet)
+		assert.NoError(t, err, "packet : %d", i+1)
+		assert.Empty(t, result, "packet : %d", i+1)
+	}
+
+	flushed := d.Flush()
+	if assert.Len(t, flushed, 1) {
+		assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05}, flushed[0].Data)
+	}
+}
+
+func TestRTP(t *testing.T) {
+	data := make([]byte, 0, 10*PacketSize)
+	for i := 0; i < 10; i++ {
+		data = append(data, testPacket(0x100, false, uint8(i), false, []byte{byte(i)})...)
+	}
+
+	p := NewPacketizer()
+	packets, err := p.Packetize(codec.Frame{Timestamp: 3000, Data: data})
+	assert.NoError(t, err)
+	if assert.Len(t, packets, 2) {
+		assert.Len(t, packets[0].Payload, 7*PacketSize)
+		assert.Equal(t, rtp.PayloadTypeMP2T, packets[0].Header.PayloadType)
+	}
+
+	d := &Depacketizer{}
+	var frames []codec.Frame
+	for i := range packets {
+		result, err := d.Depacketize(&packets[i])
+		assert.NoError(t, err)
+		frames = append(frames, result...)
+	}
+	if assert.Len(t, frames, 10) {
+		assert.Equal(t, data[9*PacketSize:], frames[9].Data)
+	}
+
+	_, err = d.Depacketize(&rtp.Packet{Payload: make([]byte, 100)})
+	assert.IsType(t, ErrMisalignedPayload{}, err)
+
+	_, err = p.Packetize(codec.Frame{Data: make([]byte, 100)})
+	assert.IsType(t, ErrMisalignedPayload{}, err)
+}
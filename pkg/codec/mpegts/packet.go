@@ -0,0 +1,81 @@
+package mpegts
+
+const (
+	// PacketSize is a size of transport stream packet
+	PacketSize = 188
+
+	// SyncByte starts every transport stream packet
+	SyncByte = 0x47
+
+	// PIDPAT is a PID of program association table
+	PIDPAT = 0x0000
+	// PIDNull is a PID of null packets
+	PIDNull = 0x1FFF
+
+	pidMask = 0x1FFF
+)
+
+// StreamType is a type of elementary stream in PMT (ISO/IEC 13818-1 table 2-34)
+type StreamType uint8
+
+const (
+	StreamTypeMPEG1Video StreamType = 0x01
+	StreamTypeMPEG2Video StreamType = 0x02
+	StreamTypeMPEG1Audio StreamType = 0x03
+	StreamTypeMPEG2Audio StreamType = 0x04
+	StreamTypePrivate    StreamType = 0x06
+	StreamTypeAACADTS    StreamType = 0x0F
+	StreamTypeAACLATM    StreamType = 0x11
+	StreamTypeH264       StreamType = 0x1B
+	StreamTypeH265       StreamType = 0x24
+)
+
+// PacketHeader represents transport stream packet header and adaptation field flags
+type PacketHeader struct {
+	TransportError    bool
+	PayloadUnitStart  bool
+	PID               uint16
+	Scrambling        uint8
+	HasAdaptation     bool
+	HasPayload        bool
+	ContinuityCounter uint8
+	Discontinuity     bool
+	RandomAccess      bool
+}
+
+// ParsePacket parses transport stream packet and returns header and payload
+func ParsePacket(buf []byte) (PacketHeader, []byte, error) {
+	var h PacketHeader
+	if len(buf) != PacketSize {
+		return h, nil, ErrMalformedPacket{Reason: "packet size is not 188 bytes"}
+	}
+	if buf[0] != SyncByte {
+		return h, nil, ErrMalformedPacket{Reason: "sync byte is absent"}
+	}
+
+	h.TransportError = buf[1]&0x80 != 0
+	h.PayloadUnitStart = buf[1]&0x40 != 0
+	h.PID = uint16(buf[1])<<8&pidMask | uint16(buf[2])
+	h.Scrambling = buf[3] >> 6
+	h.HasAdaptation = buf[3]&0x20 != 0
+	h.HasPayload = buf[3]&0x10 != 0
+	h.ContinuityCounter = buf[3] & 0x0F
+
+	payload := buf[4:]
+	if h.HasAdaptation {
+		length := int(payload[0])
+		if length+1 > len(payload) {
+			return h, nil, ErrMalformedPacket{Reason: "adaptation field is truncated"}
+		}
+		if length > 0 {
+			h.Discontinuity = payload[1]&0x80 != 0
+			h.RandomAccess = payload[1]&0x40 != 0
+		}
+		payload = payload[1+length:]
+	}
+	if !h.HasPayload {
+		payload = nil
+	}
+
+	return h, payload, nil
+}
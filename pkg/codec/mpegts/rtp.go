@@ -0,0 +1,93 @@
+package mpegts
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// ClockRate is an RTP clock rate of MP2T streams
+const ClockRate = 90000
+
+// SplitPackets splits buffer to transport stream packets
+func SplitPackets(buf []byte) ([][]byte, error) {
+	if len(buf)%PacketSize != 0 {
+		return nil, ErrMisalignedPayload{Size: len(buf)}
+	}
+
+	packets := make([][]byte, 0, len(buf)/PacketSize)
+	for ; len(buf) != 0; buf = buf[PacketSize:] {
+		if buf[0] != SyncByte {
+			return nil, ErrMalformedPacket{Reason: "sync byte is absent"}
+		}
+		packets = append(packets, buf[:PacketSize:PacketSize])
+	}
+	return packets, nil
+}
+
+// Depacketizer splits MP2T RTP payloads (RFC 2250 section 2) to transport stream packets, every frame is one
+// 188-byte packet
+type Depacketizer struct{}
+
+// Depacketize returns transport stream packets of RTP packet
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	packets, err := SplitPackets(p.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]codec.Frame, 0, len(packets))
+	for _, packet := range packets {
+		data := make([]byte, PacketSize)
+		copy(data, packet)
+		frames = append(frames, codec.Frame{Timestamp: p.Header.Timestamp, Data: data})
+	}
+	return frames, nil
+}
+
+// Packetizer groups transport stream packets to MP2T RTP packets, every RTP packet carries as many TS packets
+// as MTU allows
+type Packetizer struct {
+	codec.Sequencer
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer() *Packetizer {
+	return &Packetizer{Sequencer: codec.NewSequencer(rtp.PayloadTypeMP2T)}
+}
+
+// Packetize splits transport stream packets to RTP packets. Frame data must be multiple of 188 bytes and frame
+// timestamp is counted in 90 kHz units from the stream start. Returned packets refer to frame data and are valid
+// until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+	count := (mtu - rtp.HeaderLength) / PacketSize
+	if count == 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+	if len(f.Data)%PacketSize != 0 {
+		return nil, ErrMisalignedPayload{Size: len(f.Data)}
+	}
+
+	p.packets = p.packets[:0]
+	for data := f.Data; len(data) != 0; {
+		n := count * PacketSize
+		if n > len(data) {
+			n = len(data)
+		}
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(f.Timestamp, false),
+			Payload: data[:n:n],
+		})
+		data = data[n:]
+	}
+
+	return p.packets, nil
+}
@@ -0,0 +1,22 @@
+package opus
+
+import "fmt"
+
+// ErrMalformedPacket happens when Opus packet cannot be parsed
+type ErrMalformedPacket struct {
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed Opus packet: %s", e.Reason)
+}
+
+// ErrPacketTooLarge happens when Opus packet doesn't fit into MTU. Opus packets cannot be fragmented
+type ErrPacketTooLarge struct {
+	Size int
+	MTU  int
+}
+
+func (e ErrPacketTooLarge) Error() string {
+	return fmt.Sprintf("Opus packet doesn't fit into MTU %d: %d", e.MTU, e.Size)
+}
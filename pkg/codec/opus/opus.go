@@ -0,0 +1,77 @@
+package opus
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+// Depacketizer extracts Opus packets from RTP packets (RFC 7587), every RTP packet carries one Opus packet
+type Depacketizer struct {
+	Params
+}
+
+// NewDepacketizer creates depacketizer with parameters from SDP fmtp
+func NewDepacketizer(fmtp sdp.FMTP) *Depacketizer {
+	return &Depacketizer{Params: ParseParams(fmtp)}
+}
+
+// Depacketize returns Opus packet of RTP packet
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	// empty payload is allowed for DTX
+	if len(p.Payload) == 0 {
+		return nil, nil
+	}
+
+	if _, err := PacketDuration(p.Payload); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, len(p.Payload))
+	copy(data, p.Payload)
+	return []codec.Frame{{Timestamp: p.Header.Timestamp, Data: data, Key: true}}, nil
+}
+
+// Packetizer makes RTP packets from Opus packets
+type Packetizer struct {
+	codec.Sequencer
+	Params
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	started bool
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer(payloadType uint8, params Params) *Packetizer {
+	return &Packetizer{
+		Sequencer: codec.NewSequencer(payloadType),
+		Params:    params,
+	}
+}
+
+// Packetize makes RTP packet from Opus packet. Frame timestamp is counted in 48 kHz units from the stream start.
+// Marker bit is set on the first packet of the stream. Returned packets refer to frame data
+// and are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+	if rtp.HeaderLength+len(f.Data) > mtu {
+		return nil, ErrPacketTooLarge{Size: len(f.Data), MTU: mtu}
+	}
+	if _, err := PacketDuration(f.Data); err != nil {
+		return nil, err
+	}
+
+	p.packets = append(p.packets[:0], rtp.Packet{
+		Header:  p.Next(f.Timestamp, !p.started),
+		Payload: f.Data,
+	})
+	p.started = true
+
+	return p.packets, nil
+}
@@ -0,0 +1,81 @@
+package opus
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPacketDuration(t *testing.T) {
+	type testCase struct {
+		packet   []byte
+		duration int
+		err      bool
+	}
+
+	testCases := []testCase{
+		// SILK 20 ms, code 0
+		{packet: []byte{0x08, 0x00}, duration: 960},
+		// SILK 60 ms, code 1
+		{packet: []byte{0x19, 0x00}, duration: 5760},
+		// Hybrid 10 ms
+		{packet: []byte{0x60}, duration: 480},
+		// CELT 2.5 ms, code 3 with 4 frames
+		{packet: []byte{0x83, 0x04}, duration: 480},
+		// CELT 20 ms stereo, code 2
+		{packet: []byte{0xfe, 0x01, 0x02}, duration: 1920},
+		// SILK 60 ms x 3 exceeds 120 ms
+		{packet: []byte{0x1b, 0x03}, err: true},
+		{packet: []byte{0x03}, err: true},
+		{packet: []byte{0x03, 0x00}, err: true},
+		{packet: []byte{}, err: true},
+	}
+
+	for i, c := range testCases {
+		duration, err := PacketDuration(c.packet)
+		if c.err {
+			assert.Error(t, err, "testCase : %d", i+1)
+			continue
+		}
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.duration, duration, "testCase : %d", i+1)
+	}
+
+	toc := ParseTOC(0xfe)
+	assert.Equal(t, TOC{Config: 31, Stereo: true, Code: 2}, toc)
+	assert.Equal(t, ModeCELT, toc.Mode())
+	assert.Equal(t, byte(0xfe), toc.Byte())
+}
+
+func TestParseParams(t *testing.T) {
+	fmtp := sdp.ParseFMTP("minptime=10;useinbandfec=1;sprop-stereo=1;maxplaybackrate=16000")
+	params := ParseParams(fmtp)
+	assert.Equal(t, Params{SpropStereo: true, MaxPlaybackRate: 16000, UseInbandFEC: true}, params)
+	assert.Equal(t, 2, params.Channels())
+	assert.Equal(t, "maxplaybackrate=16000;sprop-stereo=1;useinbandfec=1", params.FMTP().String())
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	p := NewPacketizer(111, Params{})
+	d := NewDepacketizer(sdp.FMTP{})
+
+	for i := 0; i < 3; i++ {
+		packet := []byte{0x08, byte(i), 0x01}
+		packets, err := p.Packetize(codec.Frame{Timestamp: uint32(i * 960), Data: packet})
+		assert.NoError(t, err)
+		if !assert.Len(t, packets, 1) {
+			continue
+		}
+		assert.Equal(t, i == 0, packets[0].Header.Marker)
+
+		frames, err := d.Depacketize(&packets[0])
+		assert.NoError(t, err)
+		assert.Equal(t, []codec.Frame{{Timestamp: p.InitialTimestamp + uint32(i*960), Data: packet, Key: true}}, frames)
+	}
+
+	p.MTU = rtp.HeaderLength + 2
+	_, err := p.Packetize(codec.Frame{Data: []byte{0x08, 0x00, 0x00}})
+	assert.IsType(t, ErrPacketTooLarge{}, err)
+}
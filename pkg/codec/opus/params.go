@@ -0,0 +1,86 @@
+package opus
+
+import (
+	"strconv"
+
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+const (
+	// EncodingName is an encoding name of rtpmap attribute
+	EncodingName = "opus"
+
+	// RTPMapChannels is a channels count of rtpmap attribute, Opus is always signaled as two-channel
+	// (RFC 7587 section 7)
+	RTPMapChannels = 2
+)
+
+// Params represents Opus fmtp parameters (RFC 7587 section 6.1)
+type Params struct {
+	// Stereo means that receiver prefers stereo signal
+	Stereo bool
+	// SpropStereo means that sender is likely to produce stereo signal
+	SpropStereo bool
+
+	// MaxPlaybackRate is a maximum sample rate which receiver is able to render, 0 if not specified
+	MaxPlaybackRate int
+	// SpropMaxCaptureRate is a maximum sample rate which sender is able to produce, 0 if not specified
+	SpropMaxCaptureRate int
+
+	// MaxAverageBitrate is a maximum average bitrate in bits per second, 0 if not specified
+	MaxAverageBitrate int
+
+	UseInbandFEC bool
+	UseDTX       bool
+}
+
+// ParseParams reads parameters from SDP fmtp
+func ParseParams(fmtp sdp.FMTP) Params {
+	var p Params
+	p.Stereo = flag(fmtp, "stereo")
+	p.SpropStereo = flag(fmtp, "sprop-stereo")
+	p.MaxPlaybackRate, _ = fmtp.Int("maxplaybackrate")
+	p.SpropMaxCaptureRate, _ = fmtp.Int("sprop-maxcapturerate")
+	p.MaxAverageBitrate, _ = fmtp.Int("maxaveragebitrate")
+	p.UseInbandFEC = flag(fmtp, "useinbandfec")
+	p.UseDTX = flag(fmtp, "usedtx")
+	return p
+}
+
+func flag(fmtp sdp.FMTP, key string) bool {
+	v, ok := fmtp.Int(key)
+	return ok && v == 1
+}
+
+// FMTP composes SDP fmtp parameters, default values are omitted
+func (p Params) FMTP() sdp.FMTP {
+	fmtp := sdp.FMTP{}
+	setFlag(fmtp, "stereo", p.Stereo)
+	setFlag(fmtp, "sprop-stereo", p.SpropStereo)
+	setInt(fmtp, "maxplaybackrate", p.MaxPlaybackRate)
+	setInt(fmtp, "sprop-maxcapturerate", p.SpropMaxCaptureRate)
+	setInt(fmtp, "maxaveragebitrate", p.MaxAverageBitrate)
+	setFlag(fmtp, "useinbandfec", p.UseInbandFEC)
+	setFlag(fmtp, "usedtx", p.UseDTX)
+	return fmtp
+}
+
+func setFlag(fmtp sdp.FMTP, key string, value bool) {
+	if value {
+		fmtp[key] = "1"
+	}
+}
+
+func setInt(fmtp sdp.FMTP, key string, value int) {
+	if value != 0 {
+		fmtp[key] = strconv.Itoa(value)
+	}
+}
+
+// Channels returns count of channels which the stream is likely to have
+func (p Params) Channels() int {
+	if p.SpropStereo {
+		return 2
+	}
+	return 1
+}
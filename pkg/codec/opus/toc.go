@@ -0,0 +1,105 @@
+package opus
+
+// ClockRate is an RTP clock rate of Opus streams regardless of actual sample rate (RFC 7587 section 4.1)
+const ClockRate = 48000
+
+// MaxPacketDuration is a maximum duration of Opus packet in clock rate units (120 ms)
+const MaxPacketDuration = 120 * ClockRate / 1000
+
+// Mode is an Opus codec mode
+type Mode uint8
+
+const (
+	ModeSILK Mode = iota
+	ModeHybrid
+	ModeCELT
+)
+
+// TOC represents table-of-contents byte of Opus packet (RFC 6716 section 3.1)
+type TOC struct {
+	Config uint8
+	Stereo bool
+	Code   uint8
+}
+
+// ParseTOC parses table-of-contents byte
+func ParseTOC(b byte) TOC {
+	return TOC{
+		Config: b >> 3,
+		Stereo: b&0x04 != 0,
+		Code:   b & 0x03,
+	}
+}
+
+// Byte composes table-of-contents byte
+func (t TOC) Byte() byte {
+	b := t.Config<<3 | t.Code&0x03
+	if t.Stereo {
+		b |= 0x04
+	}
+	return b
+}
+
+// Mode returns codec mode of the packet
+func (t TOC) Mode() Mode {
+	switch {
+	case t.Config < 12:
+		return ModeSILK
+	case t.Config < 16:
+		return ModeHybrid
+	default:
+		return ModeCELT
+	}
+}
+
+// FrameDuration returns duration of single frame in clock rate units
+func (t TOC) FrameDuration() int {
+	// durations in 2.5 ms units
+	var units int
+	switch t.Mode() {
+	case ModeSILK:
+		units = []int{4, 8, 16, 24}[t.Config%4]
+	case ModeHybrid:
+		units = []int{4, 8}[t.Config%2]
+	default:
+		units = []int{1, 2, 4, 8}[t.Config%4]
+	}
+	return units * ClockRate / 400
+}
+
+// FrameCount returns count of frames in Opus packet
+func FrameCount(packet []byte) (int, error) {
+	if len(packet) == 0 {
+		return 0, ErrMalformedPacket{Reason: "empty packet"}
+	}
+
+	switch ParseTOC(packet[0]).Code {
+	case 0:
+		return 1, nil
+	case 1, 2:
+		return 2, nil
+	default:
+		if len(packet) < 2 {
+			return 0, ErrMalformedPacket{Reason: "frame count byte is absent"}
+		}
+		count := int(packet[1] & 0x3F)
+		if count == 0 {
+			return 0, ErrMalformedPacket{Reason: "zero frame count"}
+		}
+		return count, nil
+	}
+}
+
+// PacketDuration returns duration of Opus packet in clock rate units
+func PacketDuration(packet []byte) (int, error) {
+	count, err := FrameCount(packet)
+	if err != nil {
+		return 0, err
+	}
+
+	duration := count * ParseTOC(packet[0]).FrameDuration()
+	if duration > MaxPacketDuration {
+		return 0, ErrMalformedPacket{Reason: "packet duration exceeds 120 ms"}
+	}
+	return duration, nil
+}
@@ -0,0 +1,13 @@
+package pcm
+
+import "fmt"
+
+// ErrMisalignedPayload happens when payload size isn't multiple of samples size
+type ErrMisalignedPayload struct {
+	Size         int
+	BytesPerTick int
+}
+
+func (e ErrMisalignedPayload) Error() string {
+	return fmt.Sprintf("payload size %d is not multiple of %d", e.Size, e.BytesPerTick)
+}
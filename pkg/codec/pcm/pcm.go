@@ -0,0 +1,123 @@
+// Package pcm implements RTP payload formats of sample-based audio encodings (RFC 3551 section 4.5):
+// G.711 (PCMU, PCMA), G.722 and L16
+package pcm
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// Encoding describes layout of samples in payload
+type Encoding struct {
+	// BytesPerTick is a size of samples of all channels per one RTP clock tick
+	BytesPerTick int
+}
+
+var (
+	// G711 is a layout of PCMU and PCMA: one byte per sample, 8000 Hz clock rate
+	G711 = Encoding{BytesPerTick: 1}
+
+	// G722 is a layout of G.722: 64 kbit/s with 8000 Hz clock rate (RFC 3551 section 4.5.2)
+	G722 = Encoding{BytesPerTick: 1}
+)
+
+// L16 returns layout of 16-bit big-endian linear PCM with specified channels count
+func L16(channels int) Encoding {
+	if channels < 1 {
+		channels = 1
+	}
+	return Encoding{BytesPerTick: 2 * channels}
+}
+
+// Depacketizer extracts samples from RTP packets, every packet produces one frame
+type Depacketizer struct {
+	Encoding
+}
+
+// NewDepacketizer creates depacketizer of specified encoding
+func NewDepacketizer(encoding Encoding) *Depacketizer {
+	return &Depacketizer{Encoding: encoding}
+}
+
+// Depacketize returns samples of the packet
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	if d.BytesPerTick > 1 && len(p.Payload)%d.BytesPerTick != 0 {
+		return nil, ErrMisalignedPayload{Size: len(p.Payload), BytesPerTick: d.BytesPerTick}
+	}
+	if len(p.Payload) == 0 {
+		return nil, nil
+	}
+
+	data := make([]byte, len(p.Payload))
+	copy(data, p.Payload)
+	return []codec.Frame{{Timestamp: p.Header.Timestamp, Data: data, Key: true}}, nil
+}
+
+// Packetizer splits samples to RTP packets
+type Packetizer struct {
+	codec.Sequencer
+	Encoding
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// MaxTicks limits duration of packet in clock rate units, e.g. 160 is 20 ms for 8000 Hz. Unlimited if zero
+	MaxTicks int
+
+	started bool
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC and initial sequence number and timestamp
+func NewPacketizer(payloadType uint8, encoding Encoding) *Packetizer {
+	return &Packetizer{
+		Sequencer: codec.NewSequencer(payloadType),
+		Encoding:  encoding,
+	}
+}
+
+// Packetize splits samples to RTP packets. Frame timestamp is counted in clock rate units from the stream start.
+// Marker bit is set on the first packet of the stream. Returned packets refer to frame data and are valid until
+// the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+
+	bytesPerTick := p.BytesPerTick
+	if bytesPerTick < 1 {
+		bytesPerTick = 1
+	}
+	if len(f.Data)%bytesPerTick != 0 {
+		return nil, ErrMisalignedPayload{Size: len(f.Data), BytesPerTick: bytesPerTick}
+	}
+
+	maxTicks := (mtu - rtp.HeaderLength) / bytesPerTick
+	if maxTicks <= 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+	if p.MaxTicks > 0 && p.MaxTicks < maxTicks {
+		maxTicks = p.MaxTicks
+	}
+
+	p.packets = p.packets[:0]
+	timestamp := f.Timestamp
+	for data := f.Data; len(data) != 0; {
+		n := maxTicks * bytesPerTick
+		if n > len(data) {
+			n = len(data)
+		}
+
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(timestamp, !p.started),
+			Payload: data[:n:n],
+		})
+		p.started = true
+
+		data = data[n:]
+		timestamp += uint32(n / bytesPerTick)
+	}
+
+	return p.packets, nil
+}
@@ -0,0 +1,77 @@
+package pcm
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPacketizer_Packetize(t *testing.T) {
+	type testCase struct {
+		encoding  Encoding
+		mtu       int
+		maxTicks  int
+		size      int
+		sizes     []int
+		timestamp []uint32
+	}
+
+	testCases := []testCase{
+		{
+			encoding:  G711,
+			maxTicks:  160,
+			size:      400,
+			sizes:     []int{160, 160, 80},
+			timestamp: []uint32{0, 160, 320},
+		},
+		{
+			encoding:  L16(2),
+			mtu:       rtp.HeaderLength + 10,
+			size:      20,
+			sizes:     []int{8, 8, 4},
+			timestamp: []uint32{0, 2, 4},
+		},
+		{
+			encoding:  G722,
+			size:      100,
+			sizes:     []int{100},
+			timestamp: []uint32{0},
+		},
+	}
+
+	for i, c := range testCases {
+		p := &Packetizer{
+			Sequencer: codec.Sequencer{PayloadType: 0, InitialTimestamp: 1000},
+			Encoding:  c.encoding,
+			MTU:       c.mtu,
+			MaxTicks:  c.maxTicks,
+		}
+		d := NewDepacketizer(c.encoding)
+
+		packets, err := p.Packetize(codec.Frame{Data: make([]byte, c.size)})
+		assert.NoError(t, err, "testCase : %d", i+1)
+		if !assert.Len(t, packets, len(c.sizes), "testCase : %d", i+1) {
+			continue
+		}
+
+		for j := range packets {
+			assert.Len(t, packets[j].Payload, c.sizes[j], "testCase : %d", i+1)
+			assert.Equal(t, 1000+c.timestamp[j], packets[j].Header.Timestamp, "testCase : %d", i+1)
+			assert.Equal(t, j == 0, packets[j].Header.Marker, "testCase : %d", i+1)
+
+			frames, err := d.Depacketize(&packets[j])
+			assert.NoError(t, err, "testCase : %d", i+1)
+			assert.Equal(t, []codec.Frame{{Timestamp: 1000 + c.timestamp[j], Data: packets[j].Payload, Key: true}}, frames,
+				"testCase : %d", i+1)
+		}
+	}
+}
+
+func TestMisalignedPayload(t *testing.T) {
+	_, err := NewDepacketizer(L16(1)).Depacketize(&rtp.Packet{Payload: []byte{0x01, 0x02, 0x03}})
+	assert.ErrorIs(t, err, ErrMisalignedPayload{Size: 3, BytesPerTick: 2})
+
+	_, err = NewPacketizer(rtp.PayloadTypeL16S, L16(2)).Packetize(codec.Frame{Data: make([]byte, 6)})
+	assert.ErrorIs(t, err, ErrMisalignedPayload{Size: 6, BytesPerTick: 4})
+}
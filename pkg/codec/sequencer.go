@@ -0,0 +1,49 @@
+package codec
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// DefaultMTU is a default maximum size of outgoing RTP packet
+const DefaultMTU = 1400
+
+// Sequencer generates headers of outgoing RTP stream
+type Sequencer struct {
+	PayloadType uint8
+	SSRC        uint32
+
+	// SequenceNumber is a sequence number of the next packet
+	SequenceNumber uint16
+
+	// InitialTimestamp is an RTP timestamp of the stream start
+	InitialTimestamp uint32
+}
+
+// NewSequencer creates sequencer with random SSRC, initial sequence number and timestamp (RFC 3550 section 5.1)
+func NewSequencer(payloadType uint8) Sequencer {
+	buf := make([]byte, 10)
+	_, _ = rand.Read(buf)
+
+	return Sequencer{
+		PayloadType:      payloadType,
+		SSRC:             binary.BigEndian.Uint32(buf),
+		SequenceNumber:   binary.BigEndian.Uint16(buf[4:]),
+		InitialTimestamp: binary.BigEndian.Uint32(buf[6:]),
+	}
+}
+
+// Next returns header of the next packet. Timestamp is counted from the stream start
+func (s *Sequencer) Next(timestamp uint32, marker bool) rtp.Header {
+	h := rtp.Header{
+		Marker:         marker,
+		PayloadType:    s.PayloadType,
+		SequenceNumber: s.SequenceNumber,
+		Timestamp:      s.InitialTimestamp + timestamp,
+		SSRC:           s.SSRC,
+	}
+	s.SequenceNumber++
+	return h
+}
@@ -0,0 +1,191 @@
+package vp8
+
+const (
+	xBit        = 0x80
+	nBit        = 0x20
+	sBit        = 0x10
+	pidMask     = 0x07
+	iBit        = 0x80
+	lBit        = 0x40
+	tBit        = 0x20
+	kBit        = 0x10
+	mBit        = 0x80
+	tidShift    = 6
+	yBit        = 0x20
+	keyIdxMask  = 0x1F
+	pictureID7  = 0x7F
+	pictureID15 = 0x7FFF
+)
+
+// Descriptor represents VP8 payload descriptor (RFC 7741 section 4.2)
+type Descriptor struct {
+	// NonReference means that frame can be discarded without affecting other frames
+	NonReference bool
+	// Start is set on the first packet of partition
+	Start       bool
+	PartitionID uint8
+
+	HasPictureID bool
+	// PictureID is 7 or 15 bit picture ID, 15 bit form is used if value exceeds 7 bits or LongPictureID is set
+	PictureID     uint16
+	LongPictureID bool
+
+	HasTL0PICIDX bool
+	TL0PICIDX    uint8
+
+	HasTID    bool
+	TID       uint8
+	LayerSync bool
+
+	HasKEYIDX bool
+	KEYIDX    uint8
+}
+
+// Parse parses payload descriptor and returns its size
+func (d *Descriptor) Parse(buf []byte) (int, error) {
+	*d = Descriptor{}
+	if len(buf) == 0 {
+		return 0, ErrMalformedPacket{Reason: "descriptor is empty"}
+	}
+
+	d.NonReference = buf[0]&nBit != 0
+	d.Start = buf[0]&sBit != 0
+	d.PartitionID = buf[0] & pidMask
+	n := 1
+	if buf[0]&xBit == 0 {
+		return n, nil
+	}
+
+	if len(buf) <= n {
+		return 0, ErrMalformedPacket{Reason: "extension byte is truncated"}
+	}
+	ext := buf[n]
+	n++
+
+	if ext&iBit != 0 {
+		d.HasPictureID = true
+		if len(buf) <= n {
+			return 0, ErrMalformedPacket{Reason: "PictureID is truncated"}
+		}
+		if buf[n]&mBit != 0 {
+			if len(buf) <= n+1 {
+				return 0, ErrMalformedPacket{Reason: "PictureID is truncated"}
+			}
+			d.LongPictureID = true
+			d.PictureID = uint16(buf[n]&pictureID7)<<8 | uint16(buf[n+1])
+			n += 2
+		} else {
+			d.PictureID = uint16(buf[n] & pictureID7)
+			n++
+		}
+	}
+
+	if ext&lBit != 0 {
+		if len(buf) <= n {
+			return 0, ErrMalformedPacket{Reason: "TL0PICIDX is truncated"}
+		}
+		d.HasTL0PICIDX = true
+		d.TL0PICIDX = buf[n]
+		n++
+	}
+
+	if ext&(tBit|kBit) != 0 {
+		if len(buf) <= n {
+			return 0, ErrMalformedPacket{Reason: "TID/KEYIDX is truncated"}
+		}
+		if ext&tBit != 0 {
+			d.HasTID = true
+			d.TID = buf[n] >> tidShift
+			d.LayerSync = buf[n]&yBit != 0
+		}
+		if ext&kBit != 0 {
+			d.HasKEYIDX = true
+			d.KEYIDX = buf[n] & keyIdxMask
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// Size returns size of payload descriptor
+func (d Descriptor) Size() int {
+	if !d.HasPictureID && !d.HasTL0PICIDX && !d.HasTID && !d.HasKEYIDX {
+		return 1
+	}
+
+	n := 2
+	if d.HasPictureID {
+		n++
+		if d.long() {
+			n++
+		}
+	}
+	if d.HasTL0PICIDX {
+		n++
+	}
+	if d.HasTID || d.HasKEYIDX {
+		n++
+	}
+	return n
+}
+
+func (d Descriptor) long() bool {
+	return d.LongPictureID || d.PictureID > pictureID7
+}
+
+// Append composes payload descriptor to the end of buffer
+func (d Descriptor) Append(buf []byte) []byte {
+	b := d.PartitionID & pidMask
+	if d.NonReference {
+		b |= nBit
+	}
+	if d.Start {
+		b |= sBit
+	}
+
+	if d.Size() == 1 {
+		return append(buf, b)
+	}
+
+	var ext byte
+	if d.HasPictureID {
+		ext |= iBit
+	}
+	if d.HasTL0PICIDX {
+		ext |= lBit
+	}
+	if d.HasTID {
+		ext |= tBit
+	}
+	if d.HasKEYIDX {
+		ext |= kBit
+	}
+	buf = append(buf, b|xBit, ext)
+
+	if d.HasPictureID {
+		if d.long() {
+			id := d.PictureID & pictureID15
+			buf = append(buf, mBit|byte(id>>8), byte(id))
+		} else {
+			buf = append(buf, byte(d.PictureID))
+		}
+	}
+	if d.HasTL0PICIDX {
+		buf = append(buf, d.TL0PICIDX)
+	}
+	if d.HasTID || d.HasKEYIDX {
+		b = d.TID<<tidShift | d.KEYIDX&keyIdxMask
+		if d.LayerSync {
+			b |= yBit
+		}
+		buf = append(buf, b)
+	}
+
+	return buf
+}
+
+// IsKeyFrame returns true if VP8 frame (or the first partition of it) is a key frame (RFC 6386 section 9.1)
+func IsKeyFrame(frame []byte) bool {
+	return len(frame) != 0 && frame[0]&0x01 == 0
+}
@@ -0,0 +1,157 @@
+package vp8
+
+import (
+	"math/rand"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// ClockRate is an RTP clock rate of VP8 streams
+	ClockRate = 90000
+
+	// MaxFrameSize limits memory consumed by single frame
+	MaxFrameSize = 8 * 1024 * 1024
+)
+
+// Depacketizer reassembles VP8 frames from RTP packets (RFC 7741). Frames with lost packets are dropped
+type Depacketizer struct {
+	// Descriptor is a payload descriptor of the last packet
+	Descriptor Descriptor
+
+	frame     []byte
+	timestamp uint32
+	active    bool
+	broken    bool
+
+	seq     uint16
+	started bool
+}
+
+// Depacketize processes RTP packet and returns frame completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	lost := d.started && p.Header.SequenceNumber != d.seq+1
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+
+	n, err := d.Descriptor.Parse(p.Payload)
+	if err != nil {
+		d.broken = true
+		return nil, err
+	}
+	payload := p.Payload[n:]
+
+	switch {
+	case d.Descriptor.Start && d.Descriptor.PartitionID == 0:
+		// the previous frame is dropped if it hasn't been completed by marker bit
+		d.frame = d.frame[:0]
+		d.timestamp = p.Header.Timestamp
+		d.active = true
+		d.broken = false
+	case !d.active:
+		return nil, nil
+	case lost || d.timestamp != p.Header.Timestamp:
+		d.broken = true
+	}
+
+	if !d.broken {
+		d.frame = append(d.frame, payload...)
+		if len(d.frame) > MaxFrameSize {
+			size := len(d.frame)
+			d.reset()
+			return nil, ErrFrameTooLarge{Size: size}
+		}
+	}
+
+	if !p.Header.Marker {
+		return nil, nil
+	}
+
+	defer d.reset()
+	if d.broken || len(d.frame) == 0 {
+		return nil, nil
+	}
+
+	frame := make([]byte, len(d.frame))
+	copy(frame, d.frame)
+	return []codec.Frame{{Timestamp: d.timestamp, Data: frame, Key: IsKeyFrame(frame)}}, nil
+}
+
+func (d *Depacketizer) reset() {
+	d.frame = d.frame[:0]
+	d.active = false
+	d.broken = false
+}
+
+// Packetizer splits VP8 frames to RTP packets. Each packet carries 15-bit PictureID
+type Packetizer struct {
+	codec.Sequencer
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// PictureID is a picture ID of the next frame
+	PictureID uint16
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC, initial sequence number, timestamp and PictureID
+func NewPacketizer(payloadType uint8) *Packetizer {
+	return &Packetizer{
+		Sequencer: codec.NewSequencer(payloadType),
+		PictureID: uint16(rand.Uint32()) & pictureID15,
+	}
+}
+
+// Packetize splits frame to RTP packets. Frame timestamp is counted in 90 kHz units from the stream start.
+// Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+
+	descriptor := Descriptor{
+		Start:         true,
+		HasPictureID:  true,
+		PictureID:     p.PictureID,
+		LongPictureID: true,
+	}
+	p.PictureID = (p.PictureID + 1) & pictureID15
+
+	maxPayload := mtu - rtp.HeaderLength - descriptor.Size()
+	if maxPayload <= 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	count := (len(f.Data) + maxPayload - 1) / maxPayload
+	if size := len(f.Data) + count*descriptor.Size(); cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	for data := f.Data; len(data) != 0; {
+		n := maxPayload
+		if n > len(data) {
+			n = len(data)
+		}
+
+		start := len(p.buf)
+		p.buf = descriptor.Append(p.buf)
+		p.buf = append(p.buf, data[:n]...)
+		data = data[n:]
+
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(f.Timestamp, len(data) == 0),
+			Payload: p.buf[start:len(p.buf):len(p.buf)],
+		})
+		descriptor.Start = false
+	}
+
+	return p.packets, nil
+}
@@ -0,0 +1,136 @@
+package vp8
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDescriptor_Parse(t *testing.T) {
+	type testCase struct {
+		buf        []byte
+		descriptor Descriptor
+		size       int
+		err        bool
+	}
+
+	testCases := []testCase{
+		{
+			buf:        []byte{0x10, 0xaa},
+			descriptor: Descriptor{Start: true},
+			size:       1,
+		},
+		{
+			buf:        []byte{0xb2, 0x80, 0x7f},
+			descriptor: Descriptor{NonReference: true, Start: true, PartitionID: 2, HasPictureID: true, PictureID: 0x7f},
+			size:       3,
+		},
+		{
+			buf: []byte{0x90, 0xf0, 0x81, 0x23, 0x05, 0x65},
+			descriptor: Descriptor{
+				Start:         true,
+				HasPictureID:  true,
+				PictureID:     0x0123,
+				LongPictureID: true,
+				HasTL0PICIDX:  true,
+				TL0PICIDX:     5,
+				HasTID:        true,
+				TID:           1,
+				LayerSync:     true,
+				HasKEYIDX:     true,
+				KEYIDX:        5,
+			},
+			size: 6,
+		},
+		{buf: []byte{0x90, 0x80, 0x81}, err: true},
+		{buf: []byte{0x80}, err: true},
+		{buf: []byte{}, err: true},
+	}
+
+	for i, c := range testCases {
+		var d Descriptor
+		size, err := d.Parse(c.buf)
+		if c.err {
+			assert.Error(t, err, "testCase : %d", i+1)
+			continue
+		}
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.size, size, "testCase : %d", i+1)
+		assert.Equal(t, c.descriptor, d, "testCase : %d", i+1)
+		assert.Equal(t, c.size, d.Size(), "testCase : %d", i+1)
+		assert.Equal(t, c.buf[:size], d.Append(nil), "testCase : %d", i+1)
+	}
+}
+
+func testPacket(seq uint16, ts uint32, marker bool, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Marker:         marker,
+			PayloadType:    96,
+			SequenceNumber: seq,
+			Timestamp:      ts,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDepacketizer_Depacketize(t *testing.T) {
+	packets := []*rtp.Packet{
+		// key frame
+		testPacket(1, 0, false, 0x10, 0x00, 0x01),
+		testPacket(2, 0, true, 0x00, 0x02),
+		// inter frame with lost packet
+		testPacket(3, 3000, false, 0x10, 0x01, 0x01),
+		testPacket(5, 3000, true, 0x00, 0x03),
+		// inter frame with partitions
+		testPacket(6, 6000, false, 0x10, 0x01),
+		testPacket(7, 6000, true, 0x11, 0x02),
+		// the first packet is lost
+		testPacket(9, 9000, true, 0x00, 0x02),
+	}
+	expected := []codec.Frame{
+		{Timestamp: 0, Data: []byte{0x00, 0x01, 0x02}, Key: true},
+		{Timestamp: 6000, Data: []byte{0x01, 0x02}},
+	}
+
+	d := &Depacketizer{}
+	var frames []codec.Frame
+	for _, p := range packets {
+		result, err := d.Depacketize(p)
+		assert.NoError(t, err)
+		frames = append(frames, result...)
+	}
+	assert.Equal(t, expected, frames)
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	p := NewPacketizer(96)
+	p.MTU = 100
+	p.PictureID = pictureID15
+	d := &Depacketizer{}
+
+	for i, size := range []int{50, 1000} {
+		frame := make([]byte, size)
+		for j := range frame {
+			frame[j] = byte(i + j + 1)
+		}
+
+		packets, err := p.Packetize(codec.Frame{Timestamp: uint32(i * 3000), Data: frame})
+		assert.NoError(t, err)
+
+		var frames []codec.Frame
+		for j := range packets {
+			assert.True(t, packets[j].Size() <= p.MTU)
+			result, err := d.Depacketize(&packets[j])
+			assert.NoError(t, err)
+			frames = append(frames, result...)
+		}
+		assert.Equal(t, uint16(i+pictureID15)&pictureID15, d.Descriptor.PictureID)
+
+		if assert.Len(t, frames, 1) {
+			assert.Equal(t, frame, frames[0].Data)
+			assert.Equal(t, i%2 == 1, frames[0].Key)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package vp9
+
+const (
+	iBit        = 0x80
+	pBit        = 0x40
+	lBit        = 0x20
+	fBit        = 0x10
+	bBit        = 0x08
+	eBit        = 0x04
+	vBit        = 0x02
+	zBit        = 0x01
+	mBit        = 0x80
+	uBit        = 0x10
+	dBit        = 0x01
+	nBit        = 0x01
+	yBit        = 0x10
+	gBit        = 0x08
+	pictureID7  = 0x7F
+	pictureID15 = 0x7FFF
+
+	// MaxReferences is a maximum count of reference indices in flexible mode
+	MaxReferences = 3
+	// MaxSpatialLayers is a maximum count of spatial layers in scalability structure
+	MaxSpatialLayers = 8
+)
+
+// Descriptor represents VP9 payload descriptor (RFC 9628 section 4.2)
+type Descriptor struct {
+	// InterPicturePredicted means that frame depends on previous frames
+	InterPicturePredicted bool
+	// Flexible means flexible mode: references are signaled explicitly by P_DIFF
+	Flexible bool
+	// Begin is set on the first packet of layer frame
+	Begin bool
+	// End is set on the last packet of layer frame
+	End bool
+	// NotReference means that frame isn't used as reference by frames of the same spatial layer
+	NotReference bool
+
+	HasPictureID bool
+	// PictureID is 7 or 15 bit picture ID, 15 bit form is used if value exceeds 7 bits or LongPictureID is set
+	PictureID     uint16
+	LongPictureID bool
+
+	// HasLayers means that layer indices are presented
+	HasLayers bool
+	TID       uint8
+	// SwitchingUp is U bit: switching up point to higher temporal layer
+	SwitchingUp bool
+	SID         uint8
+	// InterLayerDependency is D bit: frame depends on lower spatial layer of the same picture
+	InterLayerDependency bool
+	// TL0PICIDX is presented in non-flexible mode with layer indices
+	TL0PICIDX uint8
+
+	// References are P_DIFF values in flexible mode
+	References []uint8
+
+	// Scalability is presented if V bit is set
+	Scalability *ScalabilityStructure
+}
+
+// Resolution is a resolution of spatial layer
+type Resolution struct {
+	Width  uint16
+	Height uint16
+}
+
+// PictureGroup describes picture of group of pictures in scalability structure
+type PictureGroup struct {
+	TID         uint8
+	SwitchingUp bool
+	References  []uint8
+}
+
+// ScalabilityStructure represents SS data (RFC 9628 section 4.2.1)
+type ScalabilityStructure struct {
+	// SpatialLayers is a count of spatial layers
+	SpatialLayers int
+	// Resolutions are presented for each spatial layer or absent
+	Resolutions []Resolution
+	// Groups describe group of pictures, nil if not presented
+	Groups []PictureGroup
+}
+
+// Parse parses payload descriptor and returns its size
+func (d *Descriptor) Parse(buf []byte) (int, error) {
+	*d = Descriptor{}
+	r := reader{buf: buf}
+
+	b, err := r.byte("descriptor")
+	if err != nil {
+		return 0, err
+	}
+	d.InterPicturePredicted = b&pBit != 0
+	d.Flexible = b&fBit != 0
+	d.Begin = b&bBit != 0
+	d.End = b&eBit != 0
+	d.NotReference = b&zBit != 0
+
+	if b&iBit != 0 {
+		d.HasPictureID = true
+		id, err := r.byte("PictureID")
+		if err != nil {
+			return 0, err
+		}
+		if id&mBit != 0 {
+			low, err := r.byte("PictureID")
+			if err != nil {
+				return 0, err
+			}
+			d.LongPictureID = true
+			d.PictureID = uint16(id&pictureID7)<<8 | uint16(low)
+		} else {
+			d.PictureID = uint16(id)
+		}
+	}
+
+	if b&lBit != 0 {
+		d.HasLayers = true
+		layers, err := r.byte("layer indices")
+		if err != nil {
+			return 0, err
+		}
+		d.TID = layers >> 5
+		d.SwitchingUp = layers&uBit != 0
+		d.SID = layers >> 1 & 0x07
+		d.InterLayerDependency = layers&dBit != 0
+
+		if !d.Flexible {
+			if d.TL0PICIDX, err = r.byte("TL0PICIDX"); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if d.Flexible && d.InterPicturePredicted {
+		for {
+			if len(d.References) == MaxReferences {
+				return 0, ErrMalformedPacket{Reason: "too many references"}
+			}
+			ref, err := r.byte("P_DIFF")
+			if err != nil {
+				return 0, err
+			}
+			d.References = append(d.References, ref>>1)
+			if ref&nBit == 0 {
+				break
+			}
+		}
+	}
+
+	if b&vBit != 0 {
+		if d.Scalability, err = parseScalability(&r); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.pos, nil
+}
+
+func parseScalability(r *reader) (*ScalabilityStructure, error) {
+	b, err := r.byte("SS")
+	if err != nil {
+		return nil, err
+	}
+
+	ss := &ScalabilityStructure{SpatialLayers: int(b>>5) + 1}
+	if b&yBit != 0 {
+		for i := 0; i < ss.SpatialLayers; i++ {
+			width, err := r.uint16("SS resolution")
+			if err != nil {
+				return nil, err
+			}
+			height, err := r.uint16("SS resolution")
+			if err != nil {
+				return nil, err
+			}
+			ss.Resolutions = append(ss.Resolutions, Resolution{Width: width, Height: height})
+		}
+	}
+
+	if b&gBit != 0 {
+		count, err := r.byte("N_G")
+		if err != nil {
+			return nil, err
+		}
+		ss.Groups = make([]PictureGroup, 0, count)
+		for i := 0; i < int(count); i++ {
+			g, err := r.byte("SS picture group")
+			if err != nil {
+				return nil, err
+			}
+			group := PictureGroup{TID: g >> 5, SwitchingUp: g&uBit != 0}
+			for j := 0; j < int(g>>2&0x03); j++ {
+				ref, err := r.byte("SS P_DIFF")
+				if err != nil {
+					return nil, err
+				}
+				group.References = append(group.References, ref)
+			}
+			ss.Groups = append(ss.Groups, group)
+		}
+	}
+
+	return ss, nil
+}
+
+// Size returns size of payload descriptor
+func (d Descriptor) Size() int {
+	return len(d.Append(nil))
+}
+
+func (d Descriptor) long() bool {
+	return d.LongPictureID || d.PictureID > pictureID7
+}
+
+// Append composes payload descriptor to the end of buffer
+func (d Descriptor) Append(buf []byte) []byte {
+	var b byte
+	if d.HasPictureID {
+		b |= iBit
+	}
+	if d.InterPicturePredicted {
+		b |= pBit
+	}
+	if d.HasLayers {
+		b |= lBit
+	}
+	if d.Flexible {
+		b |= fBit
+	}
+	if d.Begin {
+		b |= bBit
+	}
+	if d.End {
+		b |= eBit
+	}
+	if d.Scalability != nil {
+		b |= vBit
+	}
+	if d.NotReference {
+		b |= zBit
+	}
+	buf = append(buf, b)
+
+	if d.HasPictureID {
+		if d.long() {
+			id := d.PictureID & pictureID15
+			buf = append(buf, mBit|byte(id>>8), byte(id))
+		} else {
+			buf = append(buf, byte(d.PictureID))
+		}
+	}
+
+	if d.HasLayers {
+		b = d.TID<<5 | d.SID&0x07<<1
+		if d.SwitchingUp {
+			b |= uBit
+		}
+		if d.InterLayerDependency {
+			b |= dBit
+		}
+		buf = append(buf, b)
+		if !d.Flexible {
+			buf = append(buf, d.TL0PICIDX)
+		}
+	}
+
+	if d.Flexible && d.InterPicturePredicted {
+		for i, ref := range d.References {
+			b = ref << 1
+			if i != len(d.References)-1 {
+				b |= nBit
+			}
+			buf = append(buf, b)
+		}
+	}
+
+	if ss := d.Scalability; ss != nil {
+		b = byte(ss.SpatialLayers-1) << 5
+		if len(ss.Resolutions) != 0 {
+			b |= yBit
+		}
+		if ss.Groups != nil {
+			b |= gBit
+		}
+		buf = append(buf, b)
+
+		for _, r := range ss.Resolutions {
+			buf = append(buf, byte(r.Width>>8), byte(r.Width), byte(r.Height>>8), byte(r.Height))
+		}
+
+		if ss.Groups != nil {
+			buf = append(buf, byte(len(ss.Groups)))
+			for _, g := range ss.Groups {
+				b = g.TID<<5 | byte(len(g.References))<<2
+				if g.SwitchingUp {
+					b |= uBit
+				}
+				buf = append(buf, b)
+				buf = append(buf, g.References...)
+			}
+		}
+	}
+
+	return buf
+}
+
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) byte(field string) (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, ErrMalformedPacket{Reason: field + " is truncated"}
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) uint16(field string) (uint16, error) {
+	if r.pos+2 > len(r.buf) {
+		return 0, ErrMalformedPacket{Reason: field + " is truncated"}
+	}
+	v := uint16(r.buf[r.pos])<<8 | uint16(r.buf[r.pos+1])
+	r.pos += 2
+	return v, nil
+}
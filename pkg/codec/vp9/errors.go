@@ -0,0 +1,21 @@
+package vp9
+
+import "fmt"
+
+// ErrMalformedPacket happens when payload cannot be parsed
+type ErrMalformedPacket struct {
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed VP9 payload: %s", e.Reason)
+}
+
+// ErrFrameTooLarge happens when frame exceeds MaxFrameSize
+type ErrFrameTooLarge struct {
+	Size int
+}
+
+func (e ErrFrameTooLarge) Error() string {
+	return fmt.Sprintf("frame too large: %d > %d", e.Size, MaxFrameSize)
+}
@@ -0,0 +1,203 @@
+package vp9
+
+import (
+	"math/rand"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// ClockRate is an RTP clock rate of VP9 streams
+	ClockRate = 90000
+
+	// MaxFrameSize limits memory consumed by single frame
+	MaxFrameSize = 8 * 1024 * 1024
+
+	frameMarker = 0x02
+)
+
+// IsKeyFrame returns true if uncompressed header of VP9 frame describes key frame (VP9 bitstream specification
+// section 6.2)
+func IsKeyFrame(frame []byte) bool {
+	if len(frame) == 0 || frame[0]>>6 != frameMarker {
+		return false
+	}
+
+	// profile bits are followed by reserved bit in profile 3
+	pos := 4
+	if frame[0]>>4&0x03 == 0x03 {
+		pos++
+	}
+
+	showExistingFrame := frame[0] >> (7 - pos) & 1
+	frameType := frame[0] >> (6 - pos) & 1
+	return showExistingFrame == 0 && frameType == 0
+}
+
+// Depacketizer reassembles VP9 frames from RTP packets (RFC 9628). Spatial layers of the same picture are
+// returned as separate frames with the same timestamp. Frames with lost packets are dropped
+type Depacketizer struct {
+	// Descriptor is a payload descriptor of the last packet
+	Descriptor Descriptor
+
+	// Scalability is the last received scalability structure
+	Scalability *ScalabilityStructure
+
+	frame     []byte
+	timestamp uint32
+	key       bool
+	active    bool
+	broken    bool
+
+	seq     uint16
+	started bool
+}
+
+// Depacketize processes RTP packet and returns frame completed by it
+func (d *Depacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	lost := d.started && p.Header.SequenceNumber != d.seq+1
+	d.started = true
+	d.seq = p.Header.SequenceNumber
+
+	n, err := d.Descriptor.Parse(p.Payload)
+	if err != nil {
+		d.broken = true
+		return nil, err
+	}
+	payload := p.Payload[n:]
+	if d.Descriptor.Scalability != nil {
+		d.Scalability = d.Descriptor.Scalability
+	}
+
+	switch {
+	case d.Descriptor.Begin:
+		// the previous frame is dropped if it hasn't been completed by E bit
+		d.frame = d.frame[:0]
+		d.timestamp = p.Header.Timestamp
+		d.key = !d.Descriptor.InterPicturePredicted && d.Descriptor.SID == 0 && !d.Descriptor.InterLayerDependency
+		d.active = true
+		d.broken = false
+	case !d.active:
+		return nil, nil
+	case lost || d.timestamp != p.Header.Timestamp:
+		d.broken = true
+	}
+
+	if !d.broken {
+		d.frame = append(d.frame, payload...)
+		if len(d.frame) > MaxFrameSize {
+			size := len(d.frame)
+			d.reset()
+			return nil, ErrFrameTooLarge{Size: size}
+		}
+	}
+
+	if !d.Descriptor.End {
+		return nil, nil
+	}
+
+	defer d.reset()
+	if d.broken || len(d.frame) == 0 {
+		return nil, nil
+	}
+
+	frame := make([]byte, len(d.frame))
+	copy(frame, d.frame)
+	return []codec.Frame{{Timestamp: d.timestamp, Data: frame, Key: d.key}}, nil
+}
+
+func (d *Depacketizer) reset() {
+	d.frame = d.frame[:0]
+	d.active = false
+	d.broken = false
+}
+
+// Packetizer splits VP9 frames to RTP packets in non-flexible mode without layers. Each packet carries 15-bit
+// PictureID, key frames carry scalability structure
+type Packetizer struct {
+	codec.Sequencer
+
+	// MTU is a maximum size of RTP packet, codec.DefaultMTU if zero
+	MTU int
+
+	// PictureID is a picture ID of the next frame
+	PictureID uint16
+
+	// Resolution is signaled in scalability structure if it is not zero
+	Resolution Resolution
+
+	// buffers are reused, so packets are valid until the next Packetize call
+	buf     []byte
+	packets []rtp.Packet
+}
+
+// NewPacketizer creates packetizer with random SSRC, initial sequence number, timestamp and PictureID
+func NewPacketizer(payloadType uint8) *Packetizer {
+	return &Packetizer{
+		Sequencer: codec.NewSequencer(payloadType),
+		PictureID: uint16(rand.Uint32()) & pictureID15,
+	}
+}
+
+// Packetize splits frame to RTP packets. Frame timestamp is counted in 90 kHz units from the stream start.
+// Returned packets are valid until the next call
+func (p *Packetizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	mtu := p.MTU
+	if mtu == 0 {
+		mtu = codec.DefaultMTU
+	}
+
+	key := IsKeyFrame(f.Data)
+	descriptor := Descriptor{
+		InterPicturePredicted: !key,
+		Begin:                 true,
+		HasPictureID:          true,
+		PictureID:             p.PictureID,
+		LongPictureID:         true,
+	}
+	p.PictureID = (p.PictureID + 1) & pictureID15
+
+	if key {
+		descriptor.Scalability = &ScalabilityStructure{SpatialLayers: 1}
+		if p.Resolution != (Resolution{}) {
+			descriptor.Scalability.Resolutions = []Resolution{p.Resolution}
+		}
+	}
+
+	// scalability structure is sent in the first packet only
+	firstSize := descriptor.Size()
+	maxPayload := mtu - rtp.HeaderLength - firstSize
+	if maxPayload <= 0 {
+		return nil, codec.ErrMTUTooSmall{MTU: mtu}
+	}
+
+	count := (len(f.Data) + maxPayload - 1) / maxPayload
+	if size := len(f.Data) + count*firstSize; cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = p.buf[:0]
+	p.packets = p.packets[:0]
+
+	for data := f.Data; len(data) != 0; {
+		n := mtu - rtp.HeaderLength - descriptor.Size()
+		if n >= len(data) {
+			n = len(data)
+			descriptor.End = true
+		}
+
+		start := len(p.buf)
+		p.buf = descriptor.Append(p.buf)
+		p.buf = append(p.buf, data[:n]...)
+		data = data[n:]
+
+		p.packets = append(p.packets, rtp.Packet{
+			Header:  p.Next(f.Timestamp, len(data) == 0),
+			Payload: p.buf[start:len(p.buf):len(p.buf)],
+		})
+		descriptor.Begin = false
+		descriptor.Scalability = nil
+	}
+
+	return p.packets, nil
+}
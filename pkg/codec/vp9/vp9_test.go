@@ -0,0 +1,163 @@
+package vp9
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDescriptor_Parse(t *testing.T) {
+	type testCase struct {
+		buf        []byte
+		descriptor Descriptor
+		err        bool
+	}
+
+	testCases := []testCase{
+		// non-flexible mode with layers
+		{
+			buf: []byte{0xac, 0x81, 0x23, 0x53, 0x07},
+			descriptor: Descriptor{
+				Begin:                true,
+				End:                  true,
+				HasPictureID:         true,
+				PictureID:            0x0123,
+				LongPictureID:        true,
+				HasLayers:            true,
+				TID:                  2,
+				SwitchingUp:          true,
+				SID:                  1,
+				InterLayerDependency: true,
+				TL0PICIDX:            7,
+			},
+		},
+		// flexible mode with references
+		{
+			buf: []byte{0xd8, 0x05, 0x03, 0x04},
+			descriptor: Descriptor{
+				InterPicturePredicted: true,
+				Flexible:              true,
+				Begin:                 true,
+				HasPictureID:          true,
+				PictureID:             5,
+				References:            []uint8{1, 2},
+			},
+		},
+		// scalability structure
+		{
+			buf: []byte{0x0a, 0x38, 0x01, 0x40, 0x00, 0xb4, 0x02, 0x80, 0x01, 0x68, 0x02, 0x04, 0x01, 0x30},
+			descriptor: Descriptor{
+				Begin: true,
+				Scalability: &ScalabilityStructure{
+					SpatialLayers: 2,
+					Resolutions:   []Resolution{{Width: 320, Height: 180}, {Width: 640, Height: 360}},
+					Groups: []PictureGroup{
+						{TID: 0, References: []uint8{1}},
+						{TID: 1, SwitchingUp: true},
+					},
+				},
+			},
+		},
+		{buf: []byte{0xd8, 0x05, 0x03, 0x03, 0x03}, err: true},
+		{buf: []byte{0xa0, 0x81}, err: true},
+		{buf: []byte{0x02, 0x18, 0x01}, err: true},
+	}
+
+	for i, c := range testCases {
+		var d Descriptor
+		size, err := d.Parse(c.buf)
+		if c.err {
+			assert.Error(t, err, "testCase : %d", i+1)
+			continue
+		}
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, len(c.buf), size, "testCase : %d", i+1)
+		assert.Equal(t, c.descriptor, d, "testCase : %d", i+1)
+		assert.Equal(t, c.buf, d.Append(nil), "testCase : %d", i+1)
+	}
+}
+
+func TestIsKeyFrame(t *testing.T) {
+	assert.True(t, IsKeyFrame([]byte{0x82}))
+	assert.False(t, IsKeyFrame([]byte{0x86}))
+	assert.False(t, IsKeyFrame([]byte{0x88}))
+	assert.True(t, IsKeyFrame([]byte{0xb0}))
+	assert.False(t, IsKeyFrame([]byte{0x02}))
+	assert.False(t, IsKeyFrame(nil))
+}
+
+func testPacket(seq uint16, ts uint32, marker bool, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Marker:         marker,
+			PayloadType:    98,
+			SequenceNumber: seq,
+			Timestamp:      ts,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDepacketizer_Depacketize(t *testing.T) {
+	packets := []*rtp.Packet{
+		// key frame with two spatial layers
+		testPacket(1, 0, false, 0x2c, 0x00, 0x00, 0x82),
+		testPacket(2, 0, false, 0x28, 0x03, 0x00, 0x01),
+		testPacket(3, 0, true, 0x24, 0x03, 0x00, 0x02),
+		// inter frame with lost packet
+		testPacket(4, 3000, false, 0x08, 0x86),
+		testPacket(6, 3000, true, 0x04, 0x03),
+		// inter frame
+		testPacket(7, 6000, true, 0x4c, 0x86),
+	}
+	expected := []codec.Frame{
+		{Timestamp: 0, Data: []byte{0x82}, Key: true},
+		{Timestamp: 0, Data: []byte{0x01, 0x02}},
+		{Timestamp: 6000, Data: []byte{0x86}},
+	}
+
+	d := &Depacketizer{}
+	var frames []codec.Frame
+	for _, p := range packets {
+		result, err := d.Depacketize(p)
+		assert.NoError(t, err)
+		frames = append(frames, result...)
+	}
+	assert.Equal(t, expected, frames)
+}
+
+func TestPacketizer_RoundTrip(t *testing.T) {
+	p := NewPacketizer(98)
+	p.MTU = 100
+	p.Resolution = Resolution{Width: 640, Height: 480}
+	d := &Depacketizer{}
+
+	for i, first := range []byte{0x82, 0x86} {
+		frame := make([]byte, 1000)
+		frame[0] = first
+		for j := 1; j < len(frame); j++ {
+			frame[j] = byte(j)
+		}
+
+		packets, err := p.Packetize(codec.Frame{Timestamp: uint32(i * 3000), Data: frame})
+		assert.NoError(t, err)
+
+		var frames []codec.Frame
+		for j := range packets {
+			assert.True(t, packets[j].Size() <= p.MTU)
+			result, err := d.Depacketize(&packets[j])
+			assert.NoError(t, err)
+			frames = append(frames, result...)
+		}
+
+		if assert.Len(t, frames, 1) {
+			assert.Equal(t, frame, frames[0].Data)
+			assert.Equal(t, i == 0, frames[0].Key)
+		}
+	}
+
+	if assert.NotNil(t, d.Scalability) {
+		assert.Equal(t, []Resolution{{Width: 640, Height: 480}}, d.Scalability.Resolutions)
+	}
+}
@@ -0,0 +1,94 @@
+package format
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec/aac"
+	"github.com/racoon-devel/gortsp/pkg/codec/opus"
+	"github.com/racoon-devel/gortsp/pkg/codec/pcm"
+)
+
+// MPEG4Audio is an AAC format of mpeg4-generic encoding (RFC 3640)
+type MPEG4Audio struct {
+	PT     uint8
+	Config aac.AudioSpecificConfig
+
+	// AU-header fields lengths in bits, they are used by decoder only
+	SizeLength              int
+	IndexLength             int
+	IndexDeltaLength        int
+	AuxiliaryDataSizeLength int
+}
+
+func (f *MPEG4Audio) Codec() string      { return "mpeg4-generic" }
+func (f *MPEG4Audio) ClockRate() int     { return f.Config.ClockRate() }
+func (f *MPEG4Audio) PayloadType() uint8 { return f.PT }
+
+func (f *MPEG4Audio) NewDecoder() (*Decoder, error) {
+	return newDecoder(f.ClockRate(), &aac.Depacketizer{
+		Config:                  f.Config,
+		SizeLength:              f.SizeLength,
+		IndexLength:             f.IndexLength,
+		IndexDeltaLength:        f.IndexDeltaLength,
+		AuxiliaryDataSizeLength: f.AuxiliaryDataSizeLength,
+	}), nil
+}
+
+func (f *MPEG4Audio) NewEncoder() (*Encoder, error) {
+	return newEncoder(f.ClockRate(), aac.NewPacketizer(f.PT, f.Config)), nil
+}
+
+// LATM is an AAC format of MP4A-LATM encoding (RFC 6416)
+type LATM struct {
+	PT uint8
+	aac.StreamMuxConfig
+}
+
+func (f *LATM) Codec() string      { return "MP4A-LATM" }
+func (f *LATM) ClockRate() int     { return f.Config.ClockRate() }
+func (f *LATM) PayloadType() uint8 { return f.PT }
+
+func (f *LATM) NewDecoder() (*Decoder, error) {
+	return newDecoder(f.ClockRate(), &aac.LATMDepacketizer{StreamMuxConfig: f.StreamMuxConfig}), nil
+}
+
+func (f *LATM) NewEncoder() (*Encoder, error) {
+	return newEncoder(f.ClockRate(), aac.NewLATMPacketizer(f.PT, f.Config)), nil
+}
+
+// Opus is an Opus format (RFC 7587)
+type Opus struct {
+	PT uint8
+	opus.Params
+}
+
+func (f *Opus) Codec() string      { return opus.EncodingName }
+func (f *Opus) ClockRate() int     { return opus.ClockRate }
+func (f *Opus) PayloadType() uint8 { return f.PT }
+
+func (f *Opus) NewDecoder() (*Decoder, error) {
+	return newDecoder(opus.ClockRate, &opus.Depacketizer{Params: f.Params}), nil
+}
+
+func (f *Opus) NewEncoder() (*Encoder, error) {
+	return newEncoder(opus.ClockRate, opus.NewPacketizer(f.PT, f.Params)), nil
+}
+
+// PCM is a sample-based audio format: PCMU, PCMA, G722 or L16 (RFC 3551)
+type PCM struct {
+	PT           uint8
+	EncodingName string
+	Rate         int
+	Channels     int
+	pcm.Encoding
+}
+
+func (f *PCM) Codec() string      { return f.EncodingName }
+func (f *PCM) ClockRate() int     { return f.Rate }
+func (f *PCM) PayloadType() uint8 { return f.PT }
+
+func (f *PCM) NewDecoder() (*Decoder, error) {
+	return newDecoder(f.Rate, pcm.NewDepacketizer(f.Encoding)), nil
+}
+
+func (f *PCM) NewEncoder() (*Encoder, error) {
+	return newEncoder(f.Rate, pcm.NewPacketizer(f.PT, f.Encoding)), nil
+}
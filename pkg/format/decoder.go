@@ -0,0 +1,87 @@
+package format
+
+import (
+	"time"
+
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// Frame is a decoded media unit with presentation time
+type Frame struct {
+	codec.Frame
+
+	// PTS is a presentation time relative to the first decoded frame
+	PTS time.Duration
+}
+
+// Decoder reassembles frames with presentation time from RTP packets of single stream
+type Decoder struct {
+	// Depacketizer is a codec specific depacketizer, it may be configured before the first packet
+	Depacketizer codec.Depacketizer
+
+	clockRate int
+
+	// unwrapped timestamp of the last frame relative to the first one
+	last    uint32
+	elapsed int64
+	started bool
+}
+
+func newDecoder(clockRate int, depacketizer codec.Depacketizer) *Decoder {
+	return &Decoder{Depacketizer: depacketizer, clockRate: clockRate}
+}
+
+// Decode processes RTP packet and returns frames completed by it
+func (d *Decoder) Decode(p *rtp.Packet) ([]Frame, error) {
+	frames, err := d.Depacketizer.Depacketize(p)
+	if len(frames) == 0 {
+		return nil, err
+	}
+
+	result := make([]Frame, len(frames))
+	for i, f := range frames {
+		if d.started {
+			// signed difference handles both timestamp wraparound and slightly reordered frames
+			d.elapsed += int64(int32(f.Timestamp - d.last))
+		}
+		d.started = true
+		d.last = f.Timestamp
+
+		result[i] = Frame{Frame: f, PTS: ticksToDuration(d.elapsed, d.clockRate)}
+	}
+	return result, err
+}
+
+// Encoder splits frames with presentation time to RTP packets of single stream
+type Encoder struct {
+	// Packetizer is a codec specific packetizer, it may be configured before the first frame
+	Packetizer codec.Packetizer
+
+	clockRate int
+}
+
+func newEncoder(clockRate int, packetizer codec.Packetizer) *Encoder {
+	return &Encoder{Packetizer: packetizer, clockRate: clockRate}
+}
+
+// Encode splits frame to RTP packets. Frame PTS is counted from the stream start, Timestamp is ignored.
+// Returned packets are valid until the next call
+func (e *Encoder) Encode(f Frame) ([]rtp.Packet, error) {
+	f.Timestamp = uint32(durationToTicks(f.PTS, e.clockRate))
+	return e.Packetizer.Packetize(f.Frame)
+}
+
+func ticksToDuration(ticks int64, clockRate int) time.Duration {
+	if clockRate <= 0 {
+		return 0
+	}
+	rate := int64(clockRate)
+	// split to avoid overflow on long streams
+	return time.Duration(ticks/rate)*time.Second + time.Duration(ticks%rate)*time.Second/time.Duration(rate)
+}
+
+func durationToTicks(d time.Duration, clockRate int) int64 {
+	rate := int64(clockRate)
+	return int64(d/time.Second)*rate + int64(d%time.Second)*rate/int64(time.Second)
+}
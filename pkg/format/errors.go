@@ -0,0 +1,31 @@
+package format
+
+import "fmt"
+
+// ErrInvalidPayloadType happens when media format of RTP media is not a payload type
+type ErrInvalidPayloadType struct {
+	Format string
+}
+
+func (e ErrInvalidPayloadType) Error() string {
+	return fmt.Sprintf("invalid payload type: %q", e.Format)
+}
+
+// ErrUnknownPayloadType happens when dynamic payload type has no rtpmap attribute
+type ErrUnknownPayloadType struct {
+	PayloadType uint8
+}
+
+func (e ErrUnknownPayloadType) Error() string {
+	return fmt.Sprintf("unknown payload type: %d", e.PayloadType)
+}
+
+// ErrFrameTooLarge happens when frame of generic format doesn't fit into single RTP packet
+type ErrFrameTooLarge struct {
+	Size    int
+	MaxSize int
+}
+
+func (e ErrFrameTooLarge) Error() string {
+	return fmt.Sprintf("frame too large: %d > %d", e.Size, e.MaxSize)
+}
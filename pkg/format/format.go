@@ -0,0 +1,162 @@
+// Package format binds SDP media descriptions to RTP payload formats of pkg/codec
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/racoon-devel/gortsp/pkg/codec/aac"
+	"github.com/racoon-devel/gortsp/pkg/codec/h264"
+	"github.com/racoon-devel/gortsp/pkg/codec/h265"
+	"github.com/racoon-devel/gortsp/pkg/codec/opus"
+	"github.com/racoon-devel/gortsp/pkg/codec/pcm"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+// Format is an RTP payload format of media stream
+type Format interface {
+	// Codec returns encoding name of rtpmap attribute
+	Codec() string
+
+	// ClockRate returns RTP timestamp clock rate
+	ClockRate() int
+
+	// PayloadType returns RTP payload type
+	PayloadType() uint8
+
+	// NewDecoder creates decoder of received RTP packets
+	NewDecoder() (*Decoder, error)
+
+	// NewEncoder creates encoder of outgoing RTP packets
+	NewEncoder() (*Encoder, error)
+}
+
+// ParseMedia creates formats of all payload types of the media
+func ParseMedia(m *sdp.Media) ([]Format, error) {
+	formats := make([]Format, 0, len(m.Formats))
+	for _, value := range m.Formats {
+		pt, err := strconv.ParseUint(value, 10, 7)
+		if err != nil {
+			return nil, ErrInvalidPayloadType{Format: value}
+		}
+
+		f, err := New(m, uint8(pt))
+		if err != nil {
+			return nil, err
+		}
+		formats = append(formats, f)
+	}
+	return formats, nil
+}
+
+// New creates format of the payload type by rtpmap and fmtp attributes of the media. Static payload types
+// may have no rtpmap. Unknown encodings are handled by Generic format
+func New(m *sdp.Media, payloadType uint8) (Format, error) {
+	rtpmap, ok := m.RTPMap(payloadType)
+	if !ok {
+		static, ok := rtp.StaticPayloadFormat(payloadType)
+		if !ok {
+			return nil, ErrUnknownPayloadType{PayloadType: payloadType}
+		}
+		rtpmap = sdp.RTPMap{
+			PayloadType:  payloadType,
+			EncodingName: static.EncodingName,
+			ClockRate:    static.ClockRate,
+			Channels:     static.Channels,
+		}
+	}
+
+	fmtp, ok := m.FMTP(payloadType)
+	if !ok {
+		fmtp = sdp.FMTP{}
+	}
+
+	switch strings.ToUpper(rtpmap.EncodingName) {
+	case "H264":
+		f := &H264{PT: payloadType}
+		if sprop, ok := fmtp.Get("sprop-parameter-sets"); ok {
+			var err error
+			if f.SPS, f.PPS, err = h264.ParseSpropParameterSets(sprop); err != nil {
+				return nil, err
+			}
+		}
+		return f, nil
+
+	case "H265":
+		ps, err := h265.ParseParameterSets(fmtp)
+		if err != nil {
+			return nil, err
+		}
+		return &H265{PT: payloadType, MaxDONDiff: h265.MaxDONDiff(fmtp), ParameterSets: ps}, nil
+
+	case "MPEG4-GENERIC":
+		if !strings.EqualFold(m.Type, "audio") {
+			break
+		}
+		d, err := aac.NewDepacketizer(fmtp)
+		if err != nil {
+			return nil, err
+		}
+		return &MPEG4Audio{
+			PT:                      payloadType,
+			Config:                  d.Config,
+			SizeLength:              d.SizeLength,
+			IndexLength:             d.IndexLength,
+			IndexDeltaLength:        d.IndexDeltaLength,
+			AuxiliaryDataSizeLength: d.AuxiliaryDataSizeLength,
+		}, nil
+
+	case "MP4A-LATM":
+		d, err := aac.NewLATMDepacketizer(fmtp)
+		if err != nil {
+			return nil, err
+		}
+		return &LATM{PT: payloadType, StreamMuxConfig: d.StreamMuxConfig}, nil
+
+	case "OPUS":
+		return &Opus{PT: payloadType, Params: opus.ParseParams(fmtp)}, nil
+
+	case "PCMU", "PCMA":
+		return newPCM(rtpmap, pcm.G711), nil
+
+	case "G722":
+		return newPCM(rtpmap, pcm.G722), nil
+
+	case "L16":
+		return newPCM(rtpmap, pcm.L16(rtpmap.Channels)), nil
+
+	case "VP8":
+		return &VP8{PT: payloadType}, nil
+
+	case "VP9":
+		return &VP9{PT: payloadType}, nil
+
+	case "AV1":
+		return &AV1{PT: payloadType}, nil
+
+	case "JPEG":
+		return &MJPEG{PT: payloadType}, nil
+
+	case "MP2T":
+		return &MP2T{PT: payloadType}, nil
+	}
+
+	return &Generic{
+		PT:           payloadType,
+		EncodingName: rtpmap.EncodingName,
+		Rate:         rtpmap.ClockRate,
+		Channels:     rtpmap.Channels,
+		FMTP:         fmtp,
+	}, nil
+}
+
+func newPCM(rtpmap sdp.RTPMap, encoding pcm.Encoding) *PCM {
+	return &PCM{
+		PT:           rtpmap.PayloadType,
+		EncodingName: rtpmap.EncodingName,
+		Rate:         rtpmap.ClockRate,
+		Channels:     rtpmap.Channels,
+		Encoding:     encoding,
+	}
+}
@@ -0,0 +1,184 @@
+package format
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/codec/pcm"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseMedia(t *testing.T) {
+	type testCase struct {
+		sdp       string
+		codecs    []string
+		clockRate []int
+		types     []Format
+		err       bool
+	}
+
+	testCases := []testCase{
+		{
+			sdp: "m=video 0 RTP/AVP 96\r\na=rtpmap:96 H264/90000\r\n" +
+				"a=fmtp:96 packetization-mode=1;sprop-parameter-sets=Z0IAH5WoFAFuQA==,aM48gA==\r\n",
+			codecs:    []string{"H264"},
+			clockRate: []int{90000},
+			types:     []Format{&H264{}},
+		},
+		{
+			sdp:       "m=audio 0 RTP/AVP 0 8 101\r\na=rtpmap:101 telephone-event/8000\r\n",
+			codecs:    []string{"PCMU", "PCMA", "telephone-event"},
+			clockRate: []int{8000, 8000, 8000},
+			types:     []Format{&PCM{}, &PCM{}, &Generic{}},
+		},
+		{
+			sdp: "m=audio 0 RTP/AVP 97\r\na=rtpmap:97 mpeg4-generic/44100/2\r\n" +
+				"a=fmtp:97 streamtype=5;mode=AAC-hbr;config=1210;sizelength=13;indexlength=3;indexdeltalength=3\r\n",
+			codecs:    []string{"mpeg4-generic"},
+			clockRate: []int{44100},
+			types:     []Format{&MPEG4Audio{}},
+		},
+		{
+			sdp:       "m=audio 0 RTP/AVP 111\r\na=rtpmap:111 opus/48000/2\r\na=fmtp:111 sprop-stereo=1\r\n",
+			codecs:    []string{"opus"},
+			clockRate: []int{48000},
+			types:     []Format{&Opus{}},
+		},
+		{
+			sdp:       "m=video 0 RTP/AVP 26 33\r\n",
+			codecs:    []string{"JPEG", "MP2T"},
+			clockRate: []int{90000, 90000},
+			types:     []Format{&MJPEG{}, &MP2T{}},
+		},
+		{
+			sdp:    "m=video 0 RTP/AVP 96\r\n",
+			err:    true,
+			codecs: []string{},
+		},
+		{
+			sdp: "m=video 0 RTP/AVP 96\r\na=rtpmap:96 H264/90000\r\na=fmtp:96 sprop-parameter-sets=!!!\r\n",
+			err: true,
+		},
+		{
+			sdp: "m=application 0 TCP/WSF *\r\n",
+			err: true,
+		},
+	}
+
+	for i, c := range testCases {
+		desc, err := sdp.Parse([]byte("v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n" + c.sdp))
+		if !assert.NoError(t, err, "testCase : %d", i+1) {
+			continue
+		}
+
+		formats, err := ParseMedia(desc.Medias[0])
+		if c.err {
+			assert.Error(t, err, "testCase : %d", i+1)
+			continue
+		}
+		assert.NoError(t, err, "testCase : %d", i+1)
+		if !assert.Len(t, formats, len(c.codecs), "testCase : %d", i+1) {
+			continue
+		}
+
+		for j, f := range formats {
+			assert.Equal(t, c.codecs[j], f.Codec(), "testCase : %d", i+1)
+			assert.Equal(t, c.clockRate[j], f.ClockRate(), "testCase : %d", i+1)
+			assert.IsType(t, c.types[j], f, "testCase : %d", i+1)
+		}
+	}
+}
+
+func TestNew_Parameters(t *testing.T) {
+	desc, err := sdp.Parse([]byte("v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n" +
+		"m=video 0 RTP/AVP 96\r\na=rtpmap:96 H264/90000\r\n" +
+		"a=fmtp:96 packetization-mode=1;sprop-parameter-sets=Z0IAH5WoFAFuQA==,aM48gA==\r\n" +
+		"m=audio 0 RTP/AVP 97\r\na=rtpmap:97 L16/16000/2\r\n"))
+	assert.NoError(t, err)
+
+	f, err := New(desc.Medias[0], 96)
+	assert.NoError(t, err)
+	assert.Equal(t, &H264{
+		PT:  96,
+		SPS: []byte{0x67, 0x42, 0x00, 0x1f, 0x95, 0xa8, 0x14, 0x01, 0x6e, 0x40},
+		PPS: []byte{0x68, 0xce, 0x3c, 0x80},
+	}, f)
+
+	f, err = New(desc.Medias[1], 97)
+	assert.NoError(t, err)
+	assert.Equal(t, &PCM{PT: 97, EncodingName: "L16", Rate: 16000, Channels: 2, Encoding: pcm.L16(2)}, f)
+
+	_, err = New(desc.Medias[1], 98)
+	assert.ErrorIs(t, err, ErrUnknownPayloadType{PayloadType: 98})
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	f := &Generic{PT: 100, EncodingName: "X-TEST", Rate: 1000}
+	d, err := f.NewDecoder()
+	assert.NoError(t, err)
+
+	timestamps := []uint32{0xFFFFFC18, 0xFFFFFFFF, 0x000003E8, 0x000001F4, 0x000009C4}
+	pts := []time.Duration{0, 999 * time.Millisecond, 2 * time.Second, 1500 * time.Millisecond, 3500 * time.Millisecond}
+
+	for i := range timestamps {
+		frames, err := d.Decode(&rtp.Packet{
+			Header:  rtp.Header{PayloadType: 100, SequenceNumber: uint16(i), Timestamp: timestamps[i]},
+			Payload: []byte{byte(i)},
+		})
+		assert.NoError(t, err, "packet : %d", i+1)
+		if assert.Len(t, frames, 1, "packet : %d", i+1) {
+			assert.Equal(t, pts[i], frames[0].PTS, "packet : %d", i+1)
+			assert.Equal(t, []byte{byte(i)}, frames[0].Data, "packet : %d", i+1)
+		}
+	}
+
+	frames, err := d.Decode(&rtp.Packet{Header: rtp.Header{PayloadType: 100}})
+	assert.NoError(t, err)
+	assert.Empty(t, frames)
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	formats := []Format{
+		&H264{PT: 96},
+		&PCM{PT: 0, EncodingName: "PCMU", Rate: 8000, Encoding: pcm.G711},
+		&Generic{PT: 100, EncodingName: "X-TEST", Rate: 1000},
+	}
+	data := [][]byte{
+		{0x00, 0x00, 0x00, 0x01, 0x65, 0x88, 0x84},
+		make([]byte, 160),
+		{0x01, 0x02, 0x03},
+	}
+
+	for i, f := range formats {
+		e, err := f.NewEncoder()
+		assert.NoError(t, err, "testCase : %d", i+1)
+		d, err := f.NewDecoder()
+		assert.NoError(t, err, "testCase : %d", i+1)
+
+		var result []Frame
+		for _, pts := range []time.Duration{0, 40 * time.Millisecond} {
+			packets, err := e.Encode(Frame{Frame: codec.Frame{Data: data[i], Key: true}, PTS: pts})
+			assert.NoError(t, err, "testCase : %d", i+1)
+
+			for j := range packets {
+				assert.Equal(t, f.PayloadType(), packets[j].Header.PayloadType, "testCase : %d", i+1)
+				frames, err := d.Decode(&packets[j])
+				assert.NoError(t, err, "testCase : %d", i+1)
+				result = append(result, frames...)
+			}
+		}
+
+		// the last access unit of H.264 is completed by marker bit
+		if assert.Len(t, result, 2, "testCase : %d", i+1) {
+			assert.Equal(t, 40*time.Millisecond, result[1].PTS, "testCase : %d", i+1)
+			assert.Equal(t, data[i], result[1].Data, "testCase : %d", i+1)
+		}
+	}
+
+	e, err := (&Generic{PT: 100, Rate: 1000}).NewEncoder()
+	assert.NoError(t, err)
+	_, err = e.Encode(Frame{Frame: codec.Frame{Data: make([]byte, codec.DefaultMTU)}})
+	assert.IsType(t, ErrFrameTooLarge{}, err)
+}
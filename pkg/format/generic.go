@@ -0,0 +1,56 @@
+package format
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+)
+
+// Generic is a format of unsupported encoding. Payload of every RTP packet is passed through as a frame
+type Generic struct {
+	PT           uint8
+	EncodingName string
+	Rate         int
+	Channels     int
+	FMTP         sdp.FMTP
+}
+
+func (f *Generic) Codec() string      { return f.EncodingName }
+func (f *Generic) ClockRate() int     { return f.Rate }
+func (f *Generic) PayloadType() uint8 { return f.PT }
+
+func (f *Generic) NewDecoder() (*Decoder, error) {
+	return newDecoder(f.Rate, &passthroughDepacketizer{}), nil
+}
+
+func (f *Generic) NewEncoder() (*Encoder, error) {
+	return newEncoder(f.Rate, &passthroughPacketizer{Sequencer: codec.NewSequencer(f.PT)}), nil
+}
+
+type passthroughDepacketizer struct{}
+
+func (d *passthroughDepacketizer) Depacketize(p *rtp.Packet) ([]codec.Frame, error) {
+	if len(p.Payload) == 0 {
+		return nil, nil
+	}
+
+	data := make([]byte, len(p.Payload))
+	copy(data, p.Payload)
+	return []codec.Frame{{Timestamp: p.Header.Timestamp, Data: data}}, nil
+}
+
+// passthroughPacketizer sends every frame in a single RTP packet with marker bit
+type passthroughPacketizer struct {
+	codec.Sequencer
+
+	packets [1]rtp.Packet
+}
+
+func (p *passthroughPacketizer) Packetize(f codec.Frame) ([]rtp.Packet, error) {
+	if max := codec.DefaultMTU - rtp.HeaderLength; len(f.Data) > max {
+		return nil, ErrFrameTooLarge{Size: len(f.Data), MaxSize: max}
+	}
+
+	p.packets[0] = rtp.Packet{Header: p.Next(f.Timestamp, true), Payload: f.Data}
+	return p.packets[:], nil
+}
@@ -0,0 +1,146 @@
+package format
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/codec/av1"
+	"github.com/racoon-devel/gortsp/pkg/codec/h264"
+	"github.com/racoon-devel/gortsp/pkg/codec/h265"
+	"github.com/racoon-devel/gortsp/pkg/codec/mjpeg"
+	"github.com/racoon-devel/gortsp/pkg/codec/mpegts"
+	"github.com/racoon-devel/gortsp/pkg/codec/vp8"
+	"github.com/racoon-devel/gortsp/pkg/codec/vp9"
+)
+
+// H264 is an H.264 format (RFC 6184)
+type H264 struct {
+	PT uint8
+
+	// SPS and PPS are parameter sets of sprop-parameter-sets
+	SPS []byte
+	PPS []byte
+}
+
+func (f *H264) Codec() string      { return "H264" }
+func (f *H264) ClockRate() int     { return h264.ClockRate }
+func (f *H264) PayloadType() uint8 { return f.PT }
+
+func (f *H264) NewDecoder() (*Decoder, error) {
+	return newDecoder(h264.ClockRate, &h264.Depacketizer{SPS: f.SPS, PPS: f.PPS}), nil
+}
+
+func (f *H264) NewEncoder() (*Encoder, error) {
+	return newEncoder(h264.ClockRate, h264.NewPacketizer(f.PT)), nil
+}
+
+// H265 is an H.265 format (RFC 7798)
+type H265 struct {
+	PT uint8
+
+	// MaxDONDiff is sprop-max-don-diff value
+	MaxDONDiff int
+
+	// ParameterSets are parameter sets of sprop-vps/sps/pps
+	h265.ParameterSets
+}
+
+func (f *H265) Codec() string      { return "H265" }
+func (f *H265) ClockRate() int     { return h265.ClockRate }
+func (f *H265) PayloadType() uint8 { return f.PT }
+
+func (f *H265) NewDecoder() (*Decoder, error) {
+	return newDecoder(h265.ClockRate, &h265.Depacketizer{MaxDONDiff: f.MaxDONDiff, ParameterSets: f.ParameterSets}), nil
+}
+
+func (f *H265) NewEncoder() (*Encoder, error) {
+	p := h265.NewPacketizer(f.PT)
+	p.MaxDONDiff = f.MaxDONDiff
+	return newEncoder(h265.ClockRate, p), nil
+}
+
+// VP8 is a VP8 format (RFC 7741)
+type VP8 struct {
+	PT uint8
+}
+
+func (f *VP8) Codec() string      { return "VP8" }
+func (f *VP8) ClockRate() int     { return vp8.ClockRate }
+func (f *VP8) PayloadType() uint8 { return f.PT }
+
+func (f *VP8) NewDecoder() (*Decoder, error) {
+	return newDecoder(vp8.ClockRate, &vp8.Depacketizer{}), nil
+}
+
+func (f *VP8) NewEncoder() (*Encoder, error) {
+	return newEncoder(vp8.ClockRate, vp8.NewPacketizer(f.PT)), nil
+}
+
+// VP9 is a VP9 format (RFC 9628)
+type VP9 struct {
+	PT uint8
+}
+
+func (f *VP9) Codec() string      { return "VP9" }
+func (f *VP9) ClockRate() int     { return vp9.ClockRate }
+func (f *VP9) PayloadType() uint8 { return f.PT }
+
+func (f *VP9) NewDecoder() (*Decoder, error) {
+	return newDecoder(vp9.ClockRate, &vp9.Depacketizer{}), nil
+}
+
+func (f *VP9) NewEncoder() (*Encoder, error) {
+	return newEncoder(vp9.ClockRate, vp9.NewPacketizer(f.PT)), nil
+}
+
+// AV1 is an AV1 format (AV1 RTP payload specification)
+type AV1 struct {
+	PT uint8
+}
+
+func (f *AV1) Codec() string      { return "AV1" }
+func (f *AV1) ClockRate() int     { return av1.ClockRate }
+func (f *AV1) PayloadType() uint8 { return f.PT }
+
+func (f *AV1) NewDecoder() (*Decoder, error) {
+	return newDecoder(av1.ClockRate, &av1.Depacketizer{}), nil
+}
+
+func (f *AV1) NewEncoder() (*Encoder, error) {
+	return newEncoder(av1.ClockRate, av1.NewPacketizer(f.PT)), nil
+}
+
+// MJPEG is a JPEG format (RFC 2435)
+type MJPEG struct {
+	PT uint8
+}
+
+func (f *MJPEG) Codec() string      { return "JPEG" }
+func (f *MJPEG) ClockRate() int     { return mjpeg.ClockRate }
+func (f *MJPEG) PayloadType() uint8 { return f.PT }
+
+func (f *MJPEG) NewDecoder() (*Decoder, error) {
+	return newDecoder(mjpeg.ClockRate, &mjpeg.Depacketizer{}), nil
+}
+
+func (f *MJPEG) NewEncoder() (*Encoder, error) {
+	p := mjpeg.NewPacketizer()
+	p.PayloadType = f.PT
+	return newEncoder(mjpeg.ClockRate, p), nil
+}
+
+// MP2T is an MPEG transport stream format (RFC 2250), frames are 188-byte TS packets
+type MP2T struct {
+	PT uint8
+}
+
+func (f *MP2T) Codec() string      { return "MP2T" }
+func (f *MP2T) ClockRate() int     { return mpegts.ClockRate }
+func (f *MP2T) PayloadType() uint8 { return f.PT }
+
+func (f *MP2T) NewDecoder() (*Decoder, error) {
+	return newDecoder(mpegts.ClockRate, &mpegts.Depacketizer{}), nil
+}
+
+func (f *MP2T) NewEncoder() (*Encoder, error) {
+	p := mpegts.NewPacketizer()
+	p.PayloadType = f.PT
+	return newEncoder(mpegts.ClockRate, p), nil
+}
@@ -0,0 +1,55 @@
+package rtcp
+
+// ApplicationDefined represents APP packet
+type ApplicationDefined struct {
+	SubType uint8
+	SSRC    uint32
+	// Name is four ASCII characters
+	Name [4]byte
+	// Data is an application-dependent data, its length must be multiple of 4
+	Data []byte
+}
+
+func (p ApplicationDefined) Type() PacketType {
+	return TypeApplicationDefined
+}
+
+func (p ApplicationDefined) Size() int {
+	return HeaderLength + 8 + align(len(p.Data))
+}
+
+func (p ApplicationDefined) ComposeTo(buf []byte) (int, error) {
+	size := p.Size()
+	raw, err := composeHeader(buf, TypeApplicationDefined, p.SubType, size)
+	if err != nil {
+		return 0, err
+	}
+
+	raw.SetSSRC(p.SSRC)
+	copy(raw[8:12], p.Name[:])
+	copy(raw[12:], p.Data)
+
+	return size, nil
+}
+
+func (p *ApplicationDefined) Parse(data []byte) error {
+	raw, payload, err := parseTypedHeader(data, TypeApplicationDefined)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) < 8 {
+		return ErrMalformedPacket{Type: TypeApplicationDefined, Reason: "name is missing"}
+	}
+
+	p.SubType = raw.Count()
+	p.SSRC = raw.SSRC()
+	copy(p.Name[:], payload[4:8])
+	p.Data = nil
+	if len(payload) > 8 {
+		p.Data = make([]byte, len(payload)-8)
+		copy(p.Data, payload[8:])
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package rtcp
+
+import "encoding/binary"
+
+// Goodbye represents BYE packet
+type Goodbye struct {
+	Sources []uint32
+	// Reason is an optional reason for leaving
+	Reason string
+}
+
+func (p Goodbye) Type() PacketType {
+	return TypeGoodbye
+}
+
+func (p Goodbye) Size() int {
+	size := HeaderLength + len(p.Sources)*4
+	if p.Reason != "" {
+		size += align(1 + len(p.Reason))
+	}
+	return size
+}
+
+func (p Goodbye) ComposeTo(buf []byte) (int, error) {
+	if len(p.Sources) > MaxCount {
+		return 0, newErrCountLimitExceeded(len(p.Sources))
+	}
+	if len(p.Reason) > 255 {
+		return 0, ErrMalformedPacket{Type: TypeGoodbye, Reason: "reason is too long"}
+	}
+
+	size := p.Size()
+	raw, err := composeHeader(buf, TypeGoodbye, uint8(len(p.Sources)), size)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := HeaderLength
+	for _, ssrc := range p.Sources {
+		binary.BigEndian.PutUint32(raw[offset:], ssrc)
+		offset += 4
+	}
+
+	if p.Reason != "" {
+		raw[offset] = uint8(len(p.Reason))
+		copy(raw[offset+1:], p.Reason)
+	}
+
+	return size, nil
+}
+
+func (p *Goodbye) Parse(data []byte) error {
+	raw, payload, err := parseTypedHeader(data, TypeGoodbye)
+	if err != nil {
+		return err
+	}
+
+	count := int(raw.Count())
+	if len(payload) < count*4 {
+		return ErrMalformedPacket{Type: TypeGoodbye, Reason: "sources are truncated"}
+	}
+
+	p.Sources = nil
+	if count != 0 {
+		p.Sources = make([]uint32, count)
+		for i := range p.Sources {
+			p.Sources[i] = binary.BigEndian.Uint32(payload[i*4:])
+		}
+	}
+
+	p.Reason = ""
+	if rest := payload[count*4:]; len(rest) != 0 {
+		length := int(rest[0])
+		if len(rest) < 1+length {
+			return ErrMalformedPacket{Type: TypeGoodbye, Reason: "reason is truncated"}
+		}
+		p.Reason = string(rest[1 : 1+length])
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package rtcp
+
+const (
+
+	// Version is an RTCP specification version
+	Version = 2
+
+	// HeaderLength is a common RTCP packet header length
+	//    0                   1                   2                   3
+	//    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	//   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	//   |V=2|P|    RC   |      PT       |             length            |
+	//   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	HeaderLength = 4
+
+	// MaxCount is a higher value of report count, source count or subtype
+	MaxCount = 31
+
+	// MaxPacketSize is a maximum size of single packet, it is limited by 16-bit length field in 32-bit words
+	MaxPacketSize = 4 * (1 << 16)
+
+	// ReceptionReportLength is a length of single report block
+	ReceptionReportLength = 24
+
+	// SenderInfoLength is a length of sender info section of SR packet
+	SenderInfoLength = 20
+)
+
+// PacketType identifies RTCP packet type
+type PacketType uint8
+
+const (
+	TypeSenderReport       PacketType = 200
+	TypeReceiverReport     PacketType = 201
+	TypeSourceDescription  PacketType = 202
+	TypeGoodbye            PacketType = 203
+	TypeApplicationDefined PacketType = 204
+
+	// TypeTransportFeedback is a transport layer feedback message (RFC 4585 section 6.2)
+	TypeTransportFeedback PacketType = 205
+	// TypePayloadFeedback is a payload-specific feedback message (RFC 4585 section 6.3)
+	TypePayloadFeedback PacketType = 206
+)
+
+// Feedback message types which are carried in count field of feedback packets
+const (
+	FormatNACK uint8 = 1
+	FormatPLI  uint8 = 1
+	FormatFIR  uint8 = 4
+	// FormatAFB is an application layer feedback, e.g. REMB
+	FormatAFB uint8 = 15
+)
+
+// SDESType identifies SDES item type
+type SDESType uint8
+
+const (
+	SDESEnd   SDESType = 0
+	SDESCNAME SDESType = 1
+	SDESName  SDESType = 2
+	SDESEmail SDESType = 3
+	SDESPhone SDESType = 4
+	SDESLoc   SDESType = 5
+	SDESTool  SDESType = 6
+	SDESNote  SDESType = 7
+	SDESPriv  SDESType = 8
+)
@@ -0,0 +1,90 @@
+package rtcp
+
+import "fmt"
+
+// ErrIncompletePacket describes error when packet length is less than required
+type ErrIncompletePacket struct {
+	Expected int
+	Actual   int
+}
+
+func (e ErrIncompletePacket) Error() string {
+	return fmt.Sprintf("incoming buffer too short: %d < %d", e.Actual, e.Expected)
+}
+
+func newErrIncompletePacket(expected int, actual int) error {
+	return ErrIncompletePacket{
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+// ErrVersionMismatch describes error when packet has an unknown version
+type ErrVersionMismatch struct {
+	Version uint8
+}
+
+func (e ErrVersionMismatch) Error() string {
+	return fmt.Sprintf("RTCP version mismatch: %d != %d", e.Version, Version)
+}
+
+// ErrNotEnoughBufferSpace describes error when buffer length is not enough for building packet
+type ErrNotEnoughBufferSpace struct {
+	Expected int
+	Actual   int
+}
+
+func (e ErrNotEnoughBufferSpace) Error() string {
+	return fmt.Sprintf("not enough buffer space: %d < %d", e.Actual, e.Expected)
+}
+
+func newErrNotEnoughBufferSpace(expected int, actual int) error {
+	return ErrNotEnoughBufferSpace{
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+// ErrUnexpectedPacketType happens when packet is parsed by wrong type
+type ErrUnexpectedPacketType struct {
+	Expected PacketType
+	Actual   PacketType
+}
+
+func (e ErrUnexpectedPacketType) Error() string {
+	return fmt.Sprintf("unexpected packet type: %d != %d", e.Actual, e.Expected)
+}
+
+// ErrCountLimitExceeded happens when count of reports, sources or chunks > MaxCount
+type ErrCountLimitExceeded struct {
+	Count int
+}
+
+func (e ErrCountLimitExceeded) Error() string {
+	return fmt.Sprintf("count limit exceeded: %d / %d", e.Count, MaxCount)
+}
+
+func newErrCountLimitExceeded(count int) error {
+	return ErrCountLimitExceeded{
+		count,
+	}
+}
+
+// ErrPacketTooLarge happens when packet size exceeds MaxPacketSize
+type ErrPacketTooLarge struct {
+	Size int
+}
+
+func (e ErrPacketTooLarge) Error() string {
+	return fmt.Sprintf("packet too large: %d > %d", e.Size, MaxPacketSize)
+}
+
+// ErrMalformedPacket happens when packet content doesn't match its header
+type ErrMalformedPacket struct {
+	Type   PacketType
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("malformed packet %d: %s", e.Type, e.Reason)
+}
@@ -0,0 +1,326 @@
+package rtcp
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// feedbackHeaderLength is a length of common header and sender and media SSRCs of feedback packet
+	feedbackHeaderLength = HeaderLength + 8
+
+	nackPairLength = 4
+	firEntryLength = 8
+
+	rembMantissaBits = 18
+	rembMaxExponent  = 63
+)
+
+var rembIdentifier = [4]byte{'R', 'E', 'M', 'B'}
+
+// newFeedback returns empty packet of supported feedback message type or UnknownPacket
+func newFeedback(r RawPacket) Packet {
+	switch {
+	case r.PT() == TypeTransportFeedback && r.Count() == FormatNACK:
+		return &NACK{}
+	case r.PT() == TypePayloadFeedback && r.Count() == FormatPLI:
+		return &PictureLossIndication{}
+	case r.PT() == TypePayloadFeedback && r.Count() == FormatFIR:
+		return &FullIntraRequest{}
+	case r.PT() == TypePayloadFeedback && r.Count() == FormatAFB &&
+		len(r) >= feedbackHeaderLength+4 && string(r[feedbackHeaderLength:feedbackHeaderLength+4]) == string(rembIdentifier[:]):
+		return &ReceiverEstimatedMaximumBitrate{}
+	}
+	return &UnknownPacket{}
+}
+
+// composeFeedbackHeader writes common header of feedback packet (RFC 4585 section 6.1)
+func composeFeedbackHeader(buf []byte, pt PacketType, format uint8, size int, sender, media uint32) (RawPacket, error) {
+	raw, err := composeHeader(buf, pt, format, size)
+	if err != nil {
+		return nil, err
+	}
+
+	raw.SetSSRC(sender)
+	binary.BigEndian.PutUint32(raw[8:12], media)
+	return raw, nil
+}
+
+// parseFeedbackHeader validates feedback packet and returns sender and media SSRCs and feedback control information
+func parseFeedbackHeader(data []byte, pt PacketType, format uint8) (uint32, uint32, []byte, error) {
+	raw, payload, err := parseTypedHeader(data, pt)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	if raw.Count() != format {
+		return 0, 0, nil, ErrMalformedPacket{Type: pt, Reason: "unexpected feedback message type"}
+	}
+	if len(payload) < 8 {
+		return 0, 0, nil, ErrMalformedPacket{Type: pt, Reason: "SSRC is missing"}
+	}
+
+	return raw.SSRC(), binary.BigEndian.Uint32(payload[4:8]), payload[8:], nil
+}
+
+// NACKPair is a packet identifier with bitmask of following lost packets
+type NACKPair struct {
+	// PacketID is a sequence number of lost packet
+	PacketID uint16
+	// LostPackets is a bitmask of lost packets following PacketID, the least significant bit is PacketID+1
+	LostPackets uint16
+}
+
+// NACK represents transport layer generic NACK packet (RFC 4585 section 6.2.1)
+type NACK struct {
+	SenderSSRC uint32
+	MediaSSRC  uint32
+	Pairs      []NACKPair
+}
+
+// NewNACK makes NACK of lost packets, sequence numbers are expected in ascending order
+func NewNACK(sender, media uint32, seqs []uint16) *NACK {
+	p := &NACK{SenderSSRC: sender, MediaSSRC: media}
+	for _, seq := range seqs {
+		if n := len(p.Pairs); n != 0 {
+			last := &p.Pairs[n-1]
+			if diff := seq - last.PacketID; diff >= 1 && diff <= 16 {
+				last.LostPackets |= 1 << (diff - 1)
+				continue
+			}
+		}
+		p.Pairs = append(p.Pairs, NACKPair{PacketID: seq})
+	}
+	return p
+}
+
+// NewNACKFromGaps makes NACK of packets which are awaited by jitter buffer
+func NewNACKFromGaps(sender, media uint32, gaps []rtp.Gap) *NACK {
+	var seqs []uint16
+	for _, g := range gaps {
+		seqs = append(seqs, g.SequenceNumbers()...)
+	}
+	return NewNACK(sender, media, seqs)
+}
+
+// SequenceNumbers returns sequence numbers of all lost packets
+func (p NACK) SequenceNumbers() []uint16 {
+	var seqs []uint16
+	for _, pair := range p.Pairs {
+		seqs = append(seqs, pair.PacketID)
+		for i := uint16(0); i < 16; i++ {
+			if pair.LostPackets&(1<<i) != 0 {
+				seqs = append(seqs, pair.PacketID+i+1)
+			}
+		}
+	}
+	return seqs
+}
+
+func (p NACK) Type() PacketType {
+	return TypeTransportFeedback
+}
+
+func (p NACK) Size() int {
+	return feedbackHeaderLength + len(p.Pairs)*nackPairLength
+}
+
+func (p NACK) ComposeTo(buf []byte) (int, error) {
+	size := p.Size()
+	raw, err := composeFeedbackHeader(buf, TypeTransportFeedback, FormatNACK, size, p.SenderSSRC, p.MediaSSRC)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := feedbackHeaderLength
+	for _, pair := range p.Pairs {
+		binary.BigEndian.PutUint16(raw[offset:], pair.PacketID)
+		binary.BigEndian.PutUint16(raw[offset+2:], pair.LostPackets)
+		offset += nackPairLength
+	}
+
+	return size, nil
+}
+
+func (p *NACK) Parse(data []byte) error {
+	var fci []byte
+	var err error
+	p.SenderSSRC, p.MediaSSRC, fci, err = parseFeedbackHeader(data, TypeTransportFeedback, FormatNACK)
+	if err != nil {
+		return err
+	}
+
+	p.Pairs = make([]NACKPair, len(fci)/nackPairLength)
+	for i := range p.Pairs {
+		p.Pairs[i].PacketID = binary.BigEndian.Uint16(fci[i*nackPairLength:])
+		p.Pairs[i].LostPackets = binary.BigEndian.Uint16(fci[i*nackPairLength+2:])
+	}
+
+	return nil
+}
+
+// PictureLossIndication represents PLI packet (RFC 4585 section 6.3.1)
+type PictureLossIndication struct {
+	SenderSSRC uint32
+	MediaSSRC  uint32
+}
+
+func (p PictureLossIndication) Type() PacketType {
+	return TypePayloadFeedback
+}
+
+func (p PictureLossIndication) Size() int {
+	return feedbackHeaderLength
+}
+
+func (p PictureLossIndication) ComposeTo(buf []byte) (int, error) {
+	size := p.Size()
+	if _, err := composeFeedbackHeader(buf, TypePayloadFeedback, FormatPLI, size, p.SenderSSRC, p.MediaSSRC); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func (p *PictureLossIndication) Parse(data []byte) error {
+	var err error
+	p.SenderSSRC, p.MediaSSRC, _, err = parseFeedbackHeader(data, TypePayloadFeedback, FormatPLI)
+	return err
+}
+
+// FIREntry is a request of decoder refresh point from single media sender
+type FIREntry struct {
+	SSRC uint32
+	// SequenceNumber is incremented by one for every new request to the sender
+	SequenceNumber uint8
+}
+
+// FullIntraRequest represents FIR packet (RFC 5104 section 4.3.1). Media SSRC field is unused and set to zero
+type FullIntraRequest struct {
+	SenderSSRC uint32
+	Entries    []FIREntry
+}
+
+func (p FullIntraRequest) Type() PacketType {
+	return TypePayloadFeedback
+}
+
+func (p FullIntraRequest) Size() int {
+	return feedbackHeaderLength + len(p.Entries)*firEntryLength
+}
+
+func (p FullIntraRequest) ComposeTo(buf []byte) (int, error) {
+	size := p.Size()
+	raw, err := composeFeedbackHeader(buf, TypePayloadFeedback, FormatFIR, size, p.SenderSSRC, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := feedbackHeaderLength
+	for _, e := range p.Entries {
+		binary.BigEndian.PutUint32(raw[offset:], e.SSRC)
+		raw[offset+4] = e.SequenceNumber
+		offset += firEntryLength
+	}
+
+	return size, nil
+}
+
+func (p *FullIntraRequest) Parse(data []byte) error {
+	var fci []byte
+	var err error
+	p.SenderSSRC, _, fci, err = parseFeedbackHeader(data, TypePayloadFeedback, FormatFIR)
+	if err != nil {
+		return err
+	}
+
+	p.Entries = make([]FIREntry, len(fci)/firEntryLength)
+	for i := range p.Entries {
+		p.Entries[i].SSRC = binary.BigEndian.Uint32(fci[i*firEntryLength:])
+		p.Entries[i].SequenceNumber = fci[i*firEntryLength+4]
+	}
+
+	return nil
+}
+
+// ReceiverEstimatedMaximumBitrate represents REMB packet (draft-alvestrand-rmcat-remb). Media SSRC field
+// is unused and set to zero
+type ReceiverEstimatedMaximumBitrate struct {
+	SenderSSRC uint32
+	// Bitrate is an estimated maximum total bitrate of the streams in bits per second. It is sent with
+	// 18-bit precision, so less significant bits may be lost
+	Bitrate uint64
+	// SSRCs are identifiers of the streams the estimation pertains to
+	SSRCs []uint32
+}
+
+func (p ReceiverEstimatedMaximumBitrate) Type() PacketType {
+	return TypePayloadFeedback
+}
+
+func (p ReceiverEstimatedMaximumBitrate) Size() int {
+	return feedbackHeaderLength + 8 + len(p.SSRCs)*4
+}
+
+func (p ReceiverEstimatedMaximumBitrate) ComposeTo(buf []byte) (int, error) {
+	if len(p.SSRCs) > math.MaxUint8 {
+		return 0, newErrCountLimitExceeded(len(p.SSRCs))
+	}
+
+	size := p.Size()
+	raw, err := composeFeedbackHeader(buf, TypePayloadFeedback, FormatAFB, size, p.SenderSSRC, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	exponent := uint32(0)
+	mantissa := p.Bitrate
+	for mantissa >= 1<<rembMantissaBits {
+		mantissa >>= 1
+		exponent++
+	}
+
+	fci := raw[feedbackHeaderLength:]
+	copy(fci[0:4], rembIdentifier[:])
+	binary.BigEndian.PutUint32(fci[4:8], uint32(len(p.SSRCs))<<24|exponent<<rembMantissaBits|uint32(mantissa))
+	for i, ssrc := range p.SSRCs {
+		binary.BigEndian.PutUint32(fci[8+i*4:], ssrc)
+	}
+
+	return size, nil
+}
+
+func (p *ReceiverEstimatedMaximumBitrate) Parse(data []byte) error {
+	var fci []byte
+	var err error
+	p.SenderSSRC, _, fci, err = parseFeedbackHeader(data, TypePayloadFeedback, FormatAFB)
+	if err != nil {
+		return err
+	}
+
+	if len(fci) < 8 || string(fci[0:4]) != string(rembIdentifier[:]) {
+		return ErrMalformedPacket{Type: TypePayloadFeedback, Reason: "REMB identifier is missing"}
+	}
+
+	value := binary.BigEndian.Uint32(fci[4:8])
+	count := int(value >> 24)
+	exponent := (value >> rembMantissaBits) & rembMaxExponent
+	mantissa := uint64(value & (1<<rembMantissaBits - 1))
+
+	if mantissa != 0 && exponent > 64-rembMantissaBits && mantissa > math.MaxUint64>>exponent {
+		p.Bitrate = math.MaxUint64
+	} else {
+		p.Bitrate = mantissa << exponent
+	}
+
+	if len(fci) < 8+count*4 {
+		return ErrMalformedPacket{Type: TypePayloadFeedback, Reason: "SSRCs are truncated"}
+	}
+	p.SSRCs = make([]uint32, count)
+	for i := range p.SSRCs {
+		p.SSRCs[i] = binary.BigEndian.Uint32(fci[8+i*4:])
+	}
+
+	return nil
+}
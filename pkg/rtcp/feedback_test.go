@@ -0,0 +1,122 @@
+package rtcp
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFeedback(t *testing.T) {
+	type testCase struct {
+		raw []byte
+		p   Packet
+	}
+
+	testCases := []testCase{
+		{
+			raw: []byte{
+				0x81, 0xcd, 0x00, 0x04, // header
+				0x90, 0x2f, 0x9e, 0x2e, // sender SSRC
+				0xbc, 0x5e, 0x9a, 0x40, // media SSRC
+				0x00, 0x64, 0x80, 0x05, // PID, BLP
+				0xff, 0xff, 0x00, 0x00, // PID, BLP
+			},
+			p: &NACK{
+				SenderSSRC: 0x902f9e2e,
+				MediaSSRC:  0xbc5e9a40,
+				Pairs:      []NACKPair{{PacketID: 100, LostPackets: 0x8005}, {PacketID: 65535}},
+			},
+		},
+		{
+			raw: []byte{
+				0x81, 0xce, 0x00, 0x02, // header
+				0x90, 0x2f, 0x9e, 0x2e, // sender SSRC
+				0xbc, 0x5e, 0x9a, 0x40, // media SSRC
+			},
+			p: &PictureLossIndication{SenderSSRC: 0x902f9e2e, MediaSSRC: 0xbc5e9a40},
+		},
+		{
+			raw: []byte{
+				0x84, 0xce, 0x00, 0x04, // header
+				0x90, 0x2f, 0x9e, 0x2e, // sender SSRC
+				0x00, 0x00, 0x00, 0x00, // media SSRC
+				0xbc, 0x5e, 0x9a, 0x40, // SSRC
+				0x07, 0x00, 0x00, 0x00, // sequence number, reserved
+			},
+			p: &FullIntraRequest{SenderSSRC: 0x902f9e2e, Entries: []FIREntry{{SSRC: 0xbc5e9a40, SequenceNumber: 7}}},
+		},
+		{
+			raw: []byte{
+				0x8f, 0xce, 0x00, 0x05, // header
+				0x90, 0x2f, 0x9e, 0x2e, // sender SSRC
+				0x00, 0x00, 0x00, 0x00, // media SSRC
+				0x52, 0x45, 0x4d, 0x42, // REMB
+				0x01, 0x0b, 0xd0, 0x90, // SSRC count, exponent, mantissa
+				0xbc, 0x5e, 0x9a, 0x40, // SSRC
+			},
+			p: &ReceiverEstimatedMaximumBitrate{SenderSSRC: 0x902f9e2e, Bitrate: 1000000, SSRCs: []uint32{0xbc5e9a40}},
+		},
+		// application layer feedback of unknown kind
+		{
+			raw: []byte{
+				0x8f, 0xce, 0x00, 0x03, // header
+				0x90, 0x2f, 0x9e, 0x2e, // sender SSRC
+				0x00, 0x00, 0x00, 0x00, // media SSRC
+				0x47, 0x4f, 0x4f, 0x47, // GOOG
+			},
+			p: &UnknownPacket{
+				PacketType: TypePayloadFeedback,
+				Count:      FormatAFB,
+				Payload:    []byte{0x90, 0x2f, 0x9e, 0x2e, 0x00, 0x00, 0x00, 0x00, 0x47, 0x4f, 0x4f, 0x47},
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		packets, err := Parse(c.raw)
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, []Packet{c.p}, packets, "testCase : %d", i+1)
+
+		raw, err := Compose(c.p)
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.raw, raw, "testCase : %d", i+1)
+	}
+}
+
+func TestFeedback_Errors(t *testing.T) {
+	var nack NACK
+	assert.Error(t, nack.Parse([]byte{0x81, 0xcd, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e}))
+
+	var pli PictureLossIndication
+	assert.Error(t, pli.Parse([]byte{0x84, 0xce, 0x00, 0x02, 0x90, 0x2f, 0x9e, 0x2e, 0x00, 0x00, 0x00, 0x00}))
+
+	var remb ReceiverEstimatedMaximumBitrate
+	assert.Error(t, remb.Parse([]byte{
+		0x8f, 0xce, 0x00, 0x04,
+		0x90, 0x2f, 0x9e, 0x2e,
+		0x00, 0x00, 0x00, 0x00,
+		0x52, 0x45, 0x4d, 0x42,
+		0x02, 0x0b, 0xd0, 0x90,
+	}))
+}
+
+func TestREMB_Bitrate(t *testing.T) {
+	for i, bitrate := range []uint64{0, 1, 1<<18 - 1, 1 << 40, 0xFFFFC0000000000} {
+		raw, err := Compose(&ReceiverEstimatedMaximumBitrate{Bitrate: bitrate})
+		assert.NoError(t, err, "testCase : %d", i+1)
+
+		var remb ReceiverEstimatedMaximumBitrate
+		assert.NoError(t, remb.Parse(raw), "testCase : %d", i+1)
+		assert.Equal(t, bitrate, remb.Bitrate, "testCase : %d", i+1)
+	}
+}
+
+func TestNewNACK(t *testing.T) {
+	nack := NewNACK(1, 2, []uint16{65534, 65535, 0, 14, 15, 16, 17})
+	assert.Equal(t, []NACKPair{{PacketID: 65534, LostPackets: 0x8003}, {PacketID: 15, LostPackets: 0x0003}}, nack.Pairs)
+	assert.Equal(t, []uint16{65534, 65535, 0, 14, 15, 16, 17}, nack.SequenceNumbers())
+
+	nack = NewNACKFromGaps(1, 2, []rtp.Gap{{From: 65535, Count: 2}, {From: 65540, Count: 1}})
+	assert.Equal(t, []uint16{65535, 0, 4}, nack.SequenceNumbers())
+	assert.Equal(t, uint32(2), nack.MediaSSRC)
+}
@@ -0,0 +1,52 @@
+package rtcp
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// MinInterval is a minimal interval between RTCP packets (RFC 3550 section 6.2)
+	MinInterval = 5 * time.Second
+
+	// BandwidthFraction is a fraction of session bandwidth which is used by RTCP
+	BandwidthFraction = 0.05
+
+	senderBandwidthFraction   = 0.25
+	receiverBandwidthFraction = 1 - senderBandwidthFraction
+
+	// compensation of "timer reconsideration" converging to a value below the intended average
+	intervalCompensation = 2.71828 - 1.5
+)
+
+// Interval calculates randomized interval between RTCP packets of the participant (RFC 3550 appendix A.7).
+// Bandwidth is an RTCP bandwidth in octets per second, only minimal interval is applied if it is zero.
+// AvgSize is an average size of compound RTCP packets including lower layer headers
+func Interval(members, senders int, bandwidth float64, weSent bool, avgSize float64, initial bool) time.Duration {
+	min := MinInterval.Seconds()
+	if initial {
+		min /= 2
+	}
+
+	// senders share quarter of the bandwidth if they are few
+	n := members
+	if senders > 0 && float64(senders) <= float64(members)*senderBandwidthFraction {
+		if weSent {
+			bandwidth *= senderBandwidthFraction
+			n = senders
+		} else {
+			bandwidth *= receiverBandwidthFraction
+			n -= senders
+		}
+	}
+
+	t := min
+	if bandwidth > 0 {
+		if deterministic := avgSize * float64(n) / bandwidth; deterministic > t {
+			t = deterministic
+		}
+	}
+
+	t = t * (rand.Float64() + 0.5) / intervalCompensation
+	return time.Duration(t * float64(time.Second))
+}
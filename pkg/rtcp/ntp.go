@@ -0,0 +1,29 @@
+package rtcp
+
+import "time"
+
+// ntpEpochOffset is a count of seconds between 1900 and 1970
+const ntpEpochOffset = 2208988800
+
+// NTPTime is a 64-bit fixed point NTP timestamp: seconds since 1900 and fraction
+type NTPTime uint64
+
+// NewNTPTime converts wall clock time to NTP timestamp
+func NewNTPTime(t time.Time) NTPTime {
+	nanos := uint64(t.UnixNano()) + ntpEpochOffset*uint64(time.Second)
+	seconds := nanos / uint64(time.Second)
+	fraction := (nanos % uint64(time.Second)) << 32 / uint64(time.Second)
+	return NTPTime(seconds<<32 | fraction)
+}
+
+// Time converts NTP timestamp to wall clock time
+func (t NTPTime) Time() time.Time {
+	seconds := int64(t>>32) - ntpEpochOffset
+	nanos := int64((uint64(t) & 0xFFFFFFFF) * uint64(time.Second) >> 32)
+	return time.Unix(seconds, nanos)
+}
+
+// Middle returns middle 32 bits of the timestamp which are used in LSR field
+func (t NTPTime) Middle() uint32 {
+	return uint32(t >> 16)
+}
@@ -0,0 +1,180 @@
+package rtcp
+
+// Packet represents any RTCP packet of compound packet
+type Packet interface {
+	// Type returns RTCP packet type
+	Type() PacketType
+
+	// Size returns full serialized packet size
+	Size() int
+
+	// ComposeTo builds packet to a specified buffer
+	ComposeTo(buf []byte) (int, error)
+
+	// Parse parses single raw packet and fills fields
+	Parse(data []byte) error
+}
+
+// Parse parses compound packet and returns structured packets. Unknown packet types are
+// returned as *UnknownPacket
+func Parse(buf []byte) ([]Packet, error) {
+	raw, err := RawCompound(buf).Packets()
+	if err != nil {
+		return nil, err
+	}
+
+	packets := make([]Packet, 0, len(raw))
+	for _, r := range raw {
+		var p Packet
+		switch r.PT() {
+		case TypeSenderReport:
+			p = &SenderReport{}
+		case TypeReceiverReport:
+			p = &ReceiverReport{}
+		case TypeSourceDescription:
+			p = &SourceDescription{}
+		case TypeGoodbye:
+			p = &Goodbye{}
+		case TypeApplicationDefined:
+			p = &ApplicationDefined{}
+		case TypeTransportFeedback, TypePayloadFeedback:
+			p = newFeedback(r)
+		default:
+			p = &UnknownPacket{}
+		}
+
+		if err = p.Parse(r); err != nil {
+			return nil, err
+		}
+		packets = append(packets, p)
+	}
+
+	return packets, nil
+}
+
+// Compose builds compound packet
+func Compose(packets ...Packet) ([]byte, error) {
+	size := 0
+	for _, p := range packets {
+		size += p.Size()
+	}
+
+	buf := make([]byte, size)
+	_, err := ComposeTo(buf, packets...)
+	return buf, err
+}
+
+// ComposeTo builds compound packet to a specified buffer
+func ComposeTo(buf []byte, packets ...Packet) (int, error) {
+	n := 0
+	for _, p := range packets {
+		written, err := p.ComposeTo(buf[n:])
+		if err != nil {
+			return 0, err
+		}
+		n += written
+	}
+	return n, nil
+}
+
+// UnknownPacket keeps packet of unsupported type as is
+type UnknownPacket struct {
+	PacketType PacketType
+	Count      uint8
+	Payload    []byte
+}
+
+func (p UnknownPacket) Type() PacketType {
+	return p.PacketType
+}
+
+func (p UnknownPacket) Size() int {
+	return HeaderLength + align(len(p.Payload))
+}
+
+func (p UnknownPacket) ComposeTo(buf []byte) (int, error) {
+	size := p.Size()
+	raw, err := composeHeader(buf, p.PacketType, p.Count, size)
+	if err != nil {
+		return 0, err
+	}
+
+	copy(raw[HeaderLength:], p.Payload)
+	return size, nil
+}
+
+func (p *UnknownPacket) Parse(data []byte) error {
+	raw, payload, err := parseHeader(data)
+	if err != nil {
+		return err
+	}
+
+	p.PacketType = raw.PT()
+	p.Count = raw.Count()
+	p.Payload = make([]byte, len(payload))
+	copy(p.Payload, payload)
+	return nil
+}
+
+// composeHeader checks buffer space and writes common header
+func composeHeader(buf []byte, pt PacketType, count uint8, size int) (RawPacket, error) {
+	if size > MaxPacketSize {
+		return nil, ErrPacketTooLarge{Size: size}
+	}
+	if len(buf) < size {
+		return nil, newErrNotEnoughBufferSpace(size, len(buf))
+	}
+	if count > MaxCount {
+		return nil, newErrCountLimitExceeded(int(count))
+	}
+
+	raw := RawPacket(buf[:size])
+	for i := range raw {
+		raw[i] = 0
+	}
+	raw.SetVersion(Version)
+	raw.SetCount(count)
+	raw.SetPT(pt)
+	raw.SetLength(uint16(size/4 - 1))
+
+	return raw, nil
+}
+
+// parseHeader validates packet and returns its content without common header and padding
+func parseHeader(data []byte) (RawPacket, []byte, error) {
+	size, err := RawPacket(data).ValidateHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw := RawPacket(data[:size])
+	payload := raw[HeaderLength:]
+	if raw.P() {
+		padding := int(raw.Padding())
+		if padding == 0 || padding > len(payload) {
+			return nil, nil, ErrMalformedPacket{Type: raw.PT(), Reason: "invalid padding"}
+		}
+		payload = payload[:len(payload)-padding]
+	}
+
+	return raw, payload, nil
+}
+
+// parseTypedHeader validates packet and its type
+func parseTypedHeader(data []byte, expected PacketType) (RawPacket, []byte, error) {
+	raw, payload, err := parseHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if raw.PT() != expected {
+		return nil, nil, ErrUnexpectedPacketType{Expected: expected, Actual: raw.PT()}
+	}
+
+	return raw, payload, nil
+}
+
+// align rounds size up to 32-bit boundary
+func align(size int) int {
+	return (size + 3) &^ 3
+}
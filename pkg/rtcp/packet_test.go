@@ -0,0 +1,223 @@
+package rtcp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var rawSenderReport = []byte{
+	0x81, 0xc8, 0x00, 0x0c, // header
+	0x90, 0x2f, 0x9e, 0x2e, // SSRC
+	0xda, 0x8b, 0xd1, 0xfc, 0xdd, 0xdd, 0xa0, 0x5a, // NTP timestamp
+	0xaa, 0xf4, 0xed, 0xd5, // RTP timestamp
+	0x00, 0x00, 0x00, 0x01, // packet count
+	0x00, 0x00, 0x00, 0x02, // octet count
+	0xbc, 0x5e, 0x9a, 0x40, // report SSRC
+	0x00, 0x00, 0x00, 0x00, // fraction lost, total lost
+	0x00, 0x00, 0x46, 0xe1, // last sequence
+	0x00, 0x00, 0x01, 0x11, // jitter
+	0x09, 0xf3, 0x64, 0x32, // LSR
+	0x00, 0x02, 0x4a, 0x79, // DLSR
+}
+
+var senderReport = &SenderReport{
+	SSRC:         0x902f9e2e,
+	NTPTimestamp: 0xda8bd1fcdddda05a,
+	RTPTimestamp: 0xaaf4edd5,
+	PacketCount:  1,
+	OctetCount:   2,
+	Reports: []ReceptionReport{
+		{
+			SSRC:             0xbc5e9a40,
+			LastSequence:     0x46e1,
+			Jitter:           273,
+			LastSenderReport: 0x9f36432,
+			Delay:            150137,
+		},
+	},
+}
+
+var rawReceiverReport = []byte{
+	0x81, 0xc9, 0x00, 0x07, // header
+	0x90, 0x2f, 0x9e, 0x2e, // SSRC
+	0xbc, 0x5e, 0x9a, 0x40, // report SSRC
+	0x0a, 0xff, 0xff, 0xfe, // fraction lost, total lost
+	0x00, 0x01, 0x46, 0xe1, // last sequence
+	0x00, 0x00, 0x01, 0x11, // jitter
+	0x09, 0xf3, 0x64, 0x32, // LSR
+	0x00, 0x02, 0x4a, 0x79, // DLSR
+}
+
+var receiverReport = &ReceiverReport{
+	SSRC: 0x902f9e2e,
+	Reports: []ReceptionReport{
+		{
+			SSRC:             0xbc5e9a40,
+			FractionLost:     10,
+			TotalLost:        -2,
+			LastSequence:     0x146e1,
+			Jitter:           273,
+			LastSenderReport: 0x9f36432,
+			Delay:            150137,
+		},
+	},
+}
+
+var rawSourceDescription = []byte{
+	0x81, 0xca, 0x00, 0x06, // header
+	0x90, 0x2f, 0x9e, 0x2e, // SSRC
+	0x01, 0x10, 0x7b, 0x39, 0x63, 0x30, 0x30, 0x65, 0x62, 0x39, 0x32, 0x2d, 0x31, 0x61, 0x66, 0x62, 0x2d, 0x39, // CNAME
+	0x00, 0x00, // END and padding
+}
+
+var sourceDescription = NewCNAME(0x902f9e2e, "{9c00eb92-1afb-9")
+
+var rawGoodbye = []byte{
+	0x82, 0xcb, 0x00, 0x03, // header
+	0x90, 0x2f, 0x9e, 0x2e, // SSRC
+	0xbc, 0x5e, 0x9a, 0x40, // SSRC
+	0x03, 0x46, 0x4f, 0x4f, // reason
+}
+
+var goodbye = &Goodbye{
+	Sources: []uint32{0x902f9e2e, 0xbc5e9a40},
+	Reason:  "FOO",
+}
+
+var rawApplicationDefined = []byte{
+	0x85, 0xcc, 0x00, 0x03, // header
+	0x90, 0x2f, 0x9e, 0x2e, // SSRC
+	0x51, 0x54, 0x53, 0x31, // name
+	0x01, 0x02, 0x03, 0x04, // data
+}
+
+var applicationDefined = &ApplicationDefined{
+	SubType: 5,
+	SSRC:    0x902f9e2e,
+	Name:    [4]byte{'Q', 'T', 'S', '1'},
+	Data:    []byte{0x01, 0x02, 0x03, 0x04},
+}
+
+func TestPackets(t *testing.T) {
+	type testCase struct {
+		raw []byte
+		p   Packet
+	}
+
+	testCases := []testCase{
+		{raw: rawSenderReport, p: senderReport},
+		{raw: rawReceiverReport, p: receiverReport},
+		{raw: rawSourceDescription, p: sourceDescription},
+		{raw: rawGoodbye, p: goodbye},
+		{raw: rawApplicationDefined, p: applicationDefined},
+		{raw: []byte{0x81, 0xcf, 0x00, 0x01, 0x01, 0x02, 0x03, 0x04}, p: &UnknownPacket{PacketType: 207, Count: 1, Payload: []byte{0x01, 0x02, 0x03, 0x04}}},
+	}
+
+	for i, c := range testCases {
+		packets, err := Parse(c.raw)
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, []Packet{c.p}, packets, "testCase : %d", i+1)
+
+		assert.Equal(t, len(c.raw), c.p.Size(), "testCase : %d", i+1)
+		buf, err := Compose(c.p)
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.raw, buf, "testCase : %d", i+1)
+	}
+}
+
+func TestParse_Compound(t *testing.T) {
+	var compound []byte
+	compound = append(compound, rawSenderReport...)
+	compound = append(compound, rawSourceDescription...)
+	compound = append(compound, rawGoodbye...)
+
+	packets, err := Parse(compound)
+	assert.NoError(t, err)
+	assert.Equal(t, []Packet{senderReport, sourceDescription, goodbye}, packets)
+
+	buf, err := Compose(packets...)
+	assert.NoError(t, err)
+	assert.Equal(t, compound, buf)
+
+	_, err = ComposeTo(make([]byte, len(compound)-1), packets...)
+	assert.Equal(t, ErrNotEnoughBufferSpace{Expected: len(rawGoodbye), Actual: len(rawGoodbye) - 1}, err)
+}
+
+func TestParse_Padding(t *testing.T) {
+	raw := []byte{
+		0xa0, 0xc9, 0x00, 0x02, // header with padding
+		0x90, 0x2f, 0x9e, 0x2e, // SSRC
+		0x00, 0x00, 0x00, 0x04, // padding
+	}
+
+	packets, err := Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, []Packet{&ReceiverReport{SSRC: 0x902f9e2e}}, packets)
+
+	raw[11] = 0x09
+	_, err = Parse(raw)
+	assert.Equal(t, ErrMalformedPacket{Type: TypeReceiverReport, Reason: "invalid padding"}, err)
+}
+
+func TestParse_Errors(t *testing.T) {
+	type testCase struct {
+		raw []byte
+		p   Packet
+		err error
+	}
+
+	testCases := []testCase{
+		{
+			raw: rawReceiverReport,
+			p:   &SenderReport{},
+			err: ErrUnexpectedPacketType{Expected: TypeSenderReport, Actual: TypeReceiverReport},
+		},
+		{
+			raw: []byte{0x81, 0xc8, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			p:   &SenderReport{},
+			err: ErrMalformedPacket{Type: TypeSenderReport, Reason: "sender info is truncated"},
+		},
+		{
+			raw: []byte{0x82, 0xc9, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			p:   &ReceiverReport{},
+			err: ErrMalformedPacket{Type: TypeReceiverReport, Reason: "report blocks are truncated"},
+		},
+		{
+			raw: []byte{0x81, 0xca, 0x00, 0x02, 0x90, 0x2f, 0x9e, 0x2e, 0x01, 0x10, 0x7b, 0x39},
+			p:   &SourceDescription{},
+			err: ErrMalformedPacket{Type: TypeSourceDescription, Reason: "item is truncated"},
+		},
+		{
+			raw: []byte{0x82, 0xcb, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			p:   &Goodbye{},
+			err: ErrMalformedPacket{Type: TypeGoodbye, Reason: "sources are truncated"},
+		},
+		{
+			raw: []byte{0x80, 0xcc, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			p:   &ApplicationDefined{},
+			err: ErrMalformedPacket{Type: TypeApplicationDefined, Reason: "name is missing"},
+		},
+	}
+
+	for i, c := range testCases {
+		assert.Equal(t, c.err, c.p.Parse(c.raw), "testCase : %d", i+1)
+	}
+
+	_, err := Compose(&ReceiverReport{Reports: make([]ReceptionReport, MaxCount+1)})
+	assert.Equal(t, ErrCountLimitExceeded{MaxCount + 1}, err)
+
+	_, err = Compose(&UnknownPacket{PacketType: 207, Payload: make([]byte, MaxPacketSize)})
+	assert.Equal(t, ErrPacketTooLarge{Size: HeaderLength + MaxPacketSize}, err)
+
+	_, err = Compose(&UnknownPacket{PacketType: 207, Payload: make([]byte, MaxPacketSize-HeaderLength)})
+	assert.NoError(t, err)
+}
+
+func TestNTPTime(t *testing.T) {
+	ts := time.Date(2022, 4, 17, 1, 52, 57, 500000000, time.UTC)
+	ntp := NewNTPTime(ts)
+	assert.Equal(t, NTPTime(0xe605f179_80000000), ntp)
+	assert.True(t, ts.Equal(ntp.Time()))
+	assert.Equal(t, uint32(0xf1798000), ntp.Middle())
+}
@@ -0,0 +1,174 @@
+package rtcp
+
+import "encoding/binary"
+
+const (
+	versionMask  = 0xC0
+	versionShift = 6
+	paddingMask  = 0x20
+	paddingShift = 5
+	countMask    = 0x1F
+)
+
+// RawPacket is a buffer with single RTCP packet
+// All RawPacket methods are unsafe. It can be used only if you understand what
+// you do
+type RawPacket []byte
+
+/*
+	RFC3550:
+
+	0                   1                   2                   3
+    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |V=2|P|    RC   |      PT       |             length            |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |                 SSRC of packet sender                         |
+   +=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+=+
+   |                       type specific                           |
+   |                             ....                              |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+*/
+
+// ValidateHeader returns size of the first packet in the buffer and error if it's malformed
+func (p RawPacket) ValidateHeader() (size int, err error) {
+	if len(p) < HeaderLength {
+		err = newErrIncompletePacket(HeaderLength, len(p))
+		return
+	}
+
+	version := p.Version()
+	if version != Version {
+		err = ErrVersionMismatch{version}
+		return
+	}
+
+	expected := p.Size()
+	if len(p) < expected {
+		err = newErrIncompletePacket(expected, len(p))
+		return
+	}
+
+	size = expected
+	return
+}
+
+// Size returns entire packet size according to length field
+func (p RawPacket) Size() int {
+	return (int(p.Length()) + 1) * 4
+}
+
+func (p RawPacket) Version() uint8 {
+	return p[0] >> versionShift
+}
+
+func (p RawPacket) SetVersion(version uint8) {
+	p[0] &^= versionMask
+	p[0] |= version << versionShift & versionMask
+}
+
+func (p RawPacket) P() bool {
+	return (p[0] & paddingMask >> paddingShift) > 0
+}
+
+func (p RawPacket) SetP(bit bool) {
+	if bit {
+		p[0] |= paddingMask
+	} else {
+		p[0] &^= paddingMask
+	}
+}
+
+// Count returns report count, source count or subtype depending on packet type
+func (p RawPacket) Count() uint8 {
+	return p[0] & countMask
+}
+
+func (p RawPacket) SetCount(count uint8) {
+	p[0] &^= countMask
+	p[0] |= count & countMask
+}
+
+func (p RawPacket) PT() PacketType {
+	return PacketType(p[1])
+}
+
+func (p RawPacket) SetPT(pt PacketType) {
+	p[1] = uint8(pt)
+}
+
+// Length returns packet length in 32-bit words minus one
+func (p RawPacket) Length() uint16 {
+	return binary.BigEndian.Uint16(p[2:4])
+}
+
+func (p RawPacket) SetLength(length uint16) {
+	binary.BigEndian.PutUint16(p[2:4], length)
+}
+
+// SSRC returns SSRC of packet sender (the first source for BYE and SDES)
+func (p RawPacket) SSRC() uint32 {
+	return binary.BigEndian.Uint32(p[4:8])
+}
+
+func (p RawPacket) SetSSRC(ssrc uint32) {
+	binary.BigEndian.PutUint32(p[4:8], ssrc)
+}
+
+// Padding returns count of padding bytes including the last one
+func (p RawPacket) Padding() uint8 {
+	return p[len(p)-1]
+}
+
+// NTPTimestamp returns NTP timestamp of SR packet
+func (p RawPacket) NTPTimestamp() NTPTime {
+	return NTPTime(binary.BigEndian.Uint64(p[8:16]))
+}
+
+func (p RawPacket) SetNTPTimestamp(ts NTPTime) {
+	binary.BigEndian.PutUint64(p[8:16], uint64(ts))
+}
+
+// RTPTimestamp returns RTP timestamp of SR packet
+func (p RawPacket) RTPTimestamp() uint32 {
+	return binary.BigEndian.Uint32(p[16:20])
+}
+
+func (p RawPacket) SetRTPTimestamp(ts uint32) {
+	binary.BigEndian.PutUint32(p[16:20], ts)
+}
+
+// PacketCount returns sender's packet count of SR packet
+func (p RawPacket) PacketCount() uint32 {
+	return binary.BigEndian.Uint32(p[20:24])
+}
+
+func (p RawPacket) SetPacketCount(count uint32) {
+	binary.BigEndian.PutUint32(p[20:24], count)
+}
+
+// OctetCount returns sender's octet count of SR packet
+func (p RawPacket) OctetCount() uint32 {
+	return binary.BigEndian.Uint32(p[24:28])
+}
+
+func (p RawPacket) SetOctetCount(count uint32) {
+	binary.BigEndian.PutUint32(p[24:28], count)
+}
+
+// RawCompound is a buffer with compound RTCP packet
+type RawCompound []byte
+
+// Packets splits compound packet to raw packets without copying
+func (c RawCompound) Packets() ([]RawPacket, error) {
+	var packets []RawPacket
+	for buf := RawPacket(c); len(buf) != 0; {
+		size, err := buf.ValidateHeader()
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, buf[:size])
+		buf = buf[size:]
+	}
+	return packets, nil
+}
@@ -0,0 +1,92 @@
+package rtcp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRawPacket_ValidateHeader(t *testing.T) {
+	type testCase struct {
+		raw  RawPacket
+		size int
+		err  error
+	}
+
+	testCases := []testCase{
+		// packet length less than 4 bytes
+		{
+			raw: RawPacket{0x80, 0xc9, 0x00},
+			err: ErrIncompletePacket{Expected: HeaderLength, Actual: 3},
+		},
+		// invalid version
+		{
+			raw: RawPacket{0x40, 0xc9, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			err: ErrVersionMismatch{1},
+		},
+		// length field exceeds buffer
+		{
+			raw: RawPacket{0x80, 0xc9, 0x00, 0x02, 0x90, 0x2f, 0x9e, 0x2e},
+			err: ErrIncompletePacket{Expected: 12, Actual: 8},
+		},
+		// empty RR
+		{
+			raw:  RawPacket{0x80, 0xc9, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			size: 8,
+		},
+		// the first packet of compound one
+		{
+			raw:  RawPacket{0x80, 0xc9, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e, 0x81, 0xcb, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e},
+			size: 8,
+		},
+	}
+
+	for i, c := range testCases {
+		size, err := c.raw.ValidateHeader()
+		assert.Equal(t, c.err, err, "testCase : %d", i+1)
+		assert.Equal(t, c.size, size, "testCase : %d", i+1)
+	}
+}
+
+func TestRawPacket_Fields(t *testing.T) {
+	p := RawPacket(make([]byte, HeaderLength+4+SenderInfoLength))
+	p.SetVersion(Version)
+	p.SetP(true)
+	p.SetCount(31)
+	p.SetPT(TypeSenderReport)
+	p.SetLength(6)
+	p.SetSSRC(0x902f9e2e)
+	p.SetNTPTimestamp(0xda8bd1fcdddda05a)
+	p.SetRTPTimestamp(0xaaf4edd5)
+	p.SetPacketCount(1)
+	p.SetOctetCount(2)
+
+	assert.Equal(t, uint8(Version), p.Version())
+	assert.True(t, p.P())
+	assert.Equal(t, uint8(31), p.Count())
+	assert.Equal(t, TypeSenderReport, p.PT())
+	assert.Equal(t, uint16(6), p.Length())
+	assert.Equal(t, 28, p.Size())
+	assert.Equal(t, uint32(0x902f9e2e), p.SSRC())
+	assert.Equal(t, NTPTime(0xda8bd1fcdddda05a), p.NTPTimestamp())
+	assert.Equal(t, uint32(0xaaf4edd5), p.RTPTimestamp())
+	assert.Equal(t, uint32(1), p.PacketCount())
+	assert.Equal(t, uint32(2), p.OctetCount())
+
+	p.SetP(false)
+	assert.False(t, p.P())
+	assert.Equal(t, []byte{0x9f, 0xc8, 0x00, 0x06}, []byte(p[:HeaderLength]))
+}
+
+func TestRawCompound_Packets(t *testing.T) {
+	compound := RawCompound{
+		0x80, 0xc9, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e,
+		0x81, 0xcb, 0x00, 0x01, 0x90, 0x2f, 0x9e, 0x2e,
+	}
+
+	packets, err := compound.Packets()
+	assert.NoError(t, err)
+	assert.Equal(t, []RawPacket{RawPacket(compound[:8]), RawPacket(compound[8:])}, packets)
+
+	_, err = compound[:12].Packets()
+	assert.Equal(t, ErrIncompletePacket{Expected: 8, Actual: 4}, err)
+}
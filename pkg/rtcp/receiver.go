@@ -0,0 +1,222 @@
+package rtcp
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+const (
+	// maxTotalLost and minTotalLost are limits of 24-bit signed cumulative number of lost packets
+	maxTotalLost = 0x7FFFFF
+	minTotalLost = -0x800000
+)
+
+// ReceptionStats are reception statistics of single source (RFC 3550 section 6.4.1)
+type ReceptionStats struct {
+	SSRC uint32
+
+	// HighestSequence is an extended highest sequence number received
+	HighestSequence uint32
+
+	// Received is a count of received packets including duplicates
+	Received uint64
+
+	// Lost is a cumulative number of lost packets, it may be negative due to duplicates
+	Lost int64
+
+	// FractionLost is a fraction of packets lost during the last report interval, in 1/256 units
+	FractionLost uint8
+
+	// Jitter is an interarrival jitter in timestamp units
+	Jitter uint32
+
+	// LastSenderReport is an arrival time of the last SR, zero if SR hasn't been received
+	LastSenderReport time.Time
+}
+
+type receptionSource struct {
+	stats ReceptionStats
+
+	seq  rtp.SequenceUnwrapper
+	base int64
+
+	// counters at the moment of the previous report
+	expectedPrior int64
+	receivedPrior uint64
+
+	// relative transit time of the previous packet
+	transit    uint32
+	hasTransit bool
+	jitter     float64
+
+	// middle 32 bits of NTP timestamp of the last SR
+	lsr uint32
+}
+
+func (s *receptionSource) expected() int64 {
+	return s.seq.Highest() - s.base + 1
+}
+
+// Receiver collects reception statistics of sources of single RTP session and builds receiver reports
+type Receiver struct {
+	// SSRC is an identifier of the receiver itself
+	SSRC uint32
+
+	// ClockRate is an RTP clock rate of the session media, jitter isn't calculated if it is zero
+	ClockRate int
+
+	sources map[uint32]*receptionSource
+	// origin of arrival time in timestamp units
+	origin time.Time
+}
+
+// NewReceiver creates receiver with random SSRC
+func NewReceiver(clockRate int) *Receiver {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+
+	return &Receiver{
+		SSRC:      binary.BigEndian.Uint32(buf),
+		ClockRate: clockRate,
+		sources:   map[uint32]*receptionSource{},
+	}
+}
+
+func (r *Receiver) source(ssrc uint32) *receptionSource {
+	if r.sources == nil {
+		r.sources = map[uint32]*receptionSource{}
+	}
+	s, ok := r.sources[ssrc]
+	if !ok {
+		s = &receptionSource{stats: ReceptionStats{SSRC: ssrc}}
+		r.sources[ssrc] = s
+	}
+	return s
+}
+
+// ProcessRTP updates statistics of the packet source
+func (r *Receiver) ProcessRTP(h *rtp.Header, arrival time.Time) {
+	s := r.source(h.SSRC)
+
+	first := s.stats.Received == 0
+	ext := s.seq.Unwrap(h.SequenceNumber)
+	if first || ext < s.base {
+		s.base = ext
+	}
+	s.stats.Received++
+	s.stats.HighestSequence = uint32(s.seq.Highest())
+	s.stats.Lost = s.expected() - int64(s.stats.Received)
+
+	if r.ClockRate <= 0 {
+		return
+	}
+	if r.origin.IsZero() {
+		r.origin = arrival
+	}
+
+	// interarrival jitter (RFC 3550 appendix A.8)
+	elapsed := arrival.Sub(r.origin)
+	rate := int64(r.ClockRate)
+	ticks := int64(elapsed/time.Second)*rate + int64(elapsed%time.Second)*rate/int64(time.Second)
+	transit := uint32(ticks) - h.Timestamp
+	if s.hasTransit {
+		d := float64(int32(transit - s.transit))
+		if d < 0 {
+			d = -d
+		}
+		s.jitter += (d - s.jitter) / 16
+		s.stats.Jitter = uint32(s.jitter)
+	}
+	s.transit = transit
+	s.hasTransit = true
+}
+
+// ProcessRTCP takes the last sender reports of sources and forgets sources which have left the session
+func (r *Receiver) ProcessRTCP(packets []Packet, arrival time.Time) {
+	for _, p := range packets {
+		switch t := p.(type) {
+		case *SenderReport:
+			s := r.source(t.SSRC)
+			s.lsr = t.NTPTimestamp.Middle()
+			s.stats.LastSenderReport = arrival
+		case *Goodbye:
+			for _, ssrc := range t.Sources {
+				delete(r.sources, ssrc)
+			}
+		}
+	}
+}
+
+// Report builds receiver report with report blocks of sources which have sent packets since the previous report
+func (r *Receiver) Report(now time.Time) *ReceiverReport {
+	rr := &ReceiverReport{SSRC: r.SSRC}
+	for _, ssrc := range r.ssrcs() {
+		s := r.sources[ssrc]
+		if s.stats.Received == s.receivedPrior {
+			continue
+		}
+
+		expected := s.expected()
+		expectedInterval := expected - s.expectedPrior
+		lostInterval := expectedInterval - int64(s.stats.Received-s.receivedPrior)
+		s.expectedPrior = expected
+		s.receivedPrior = s.stats.Received
+
+		s.stats.FractionLost = 0
+		if expectedInterval > 0 && lostInterval > 0 {
+			fraction := lostInterval << 8 / expectedInterval
+			if fraction > 255 {
+				fraction = 255
+			}
+			s.stats.FractionLost = uint8(fraction)
+		}
+
+		if len(rr.Reports) == MaxCount {
+			continue
+		}
+
+		lost := s.stats.Lost
+		if lost > maxTotalLost {
+			lost = maxTotalLost
+		} else if lost < minTotalLost {
+			lost = minTotalLost
+		}
+
+		report := ReceptionReport{
+			SSRC:         ssrc,
+			FractionLost: s.stats.FractionLost,
+			TotalLost:    int32(lost),
+			LastSequence: s.stats.HighestSequence,
+			Jitter:       s.stats.Jitter,
+		}
+		if !s.stats.LastSenderReport.IsZero() {
+			report.LastSenderReport = s.lsr
+			report.Delay = uint32(now.Sub(s.stats.LastSenderReport).Seconds() * 65536)
+		}
+		rr.Reports = append(rr.Reports, report)
+	}
+	return rr
+}
+
+// Stats returns statistics of all sources ordered by SSRC
+func (r *Receiver) Stats() []ReceptionStats {
+	ssrcs := r.ssrcs()
+	stats := make([]ReceptionStats, len(ssrcs))
+	for i, ssrc := range ssrcs {
+		stats[i] = r.sources[ssrc].stats
+	}
+	return stats
+}
+
+func (r *Receiver) ssrcs() []uint32 {
+	ssrcs := make([]uint32, 0, len(r.sources))
+	for ssrc := range r.sources {
+		ssrcs = append(ssrcs, ssrc)
+	}
+	sort.Slice(ssrcs, func(i, j int) bool { return ssrcs[i] < ssrcs[j] })
+	return ssrcs
+}
@@ -0,0 +1,66 @@
+package rtcp
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReceiver_Report(t *testing.T) {
+	start := time.Now()
+	r := NewReceiver(8000)
+	r.SSRC = 0x1000
+
+	// 20 ms packets, the packet 65535 is lost and the packet 1 is delayed by 10 ms
+	seqs := []uint16{65533, 65534, 0, 1, 2}
+	for _, seq := range seqs {
+		ticks := uint32(seq+3) * 160
+		arrival := start.Add(time.Duration(ticks/8) * time.Millisecond)
+		if seq == 1 {
+			arrival = arrival.Add(10 * time.Millisecond)
+		}
+		r.ProcessRTP(&rtp.Header{SSRC: 0x2000, SequenceNumber: seq, Timestamp: 1000 + ticks}, arrival)
+	}
+
+	sr := &SenderReport{SSRC: 0x2000, NTPTimestamp: NewNTPTime(start)}
+	r.ProcessRTCP([]Packet{sr}, start.Add(50*time.Millisecond))
+
+	rr := r.Report(start.Add(100 * time.Millisecond))
+	assert.Equal(t, uint32(0x1000), rr.SSRC)
+	if assert.Len(t, rr.Reports, 1) {
+		assert.Equal(t, ReceptionReport{
+			SSRC:             0x2000,
+			FractionLost:     256 / 6,
+			TotalLost:        1,
+			LastSequence:     0x10002,
+			Jitter:           9,
+			LastSenderReport: sr.NTPTimestamp.Middle(),
+			Delay:            65536 / 20,
+		}, rr.Reports[0])
+	}
+
+	// nothing is received since the previous report
+	assert.Empty(t, r.Report(start.Add(time.Second)).Reports)
+
+	// duplicate packet makes loss negative
+	r.ProcessRTP(&rtp.Header{SSRC: 0x2000, SequenceNumber: 3, Timestamp: 1000 + 6*160}, start.Add(120*time.Millisecond))
+	r.ProcessRTP(&rtp.Header{SSRC: 0x2000, SequenceNumber: 3, Timestamp: 1000 + 6*160}, start.Add(120*time.Millisecond))
+	r.ProcessRTP(&rtp.Header{SSRC: 0x2000, SequenceNumber: 65535, Timestamp: 1000 + 2*160}, start.Add(120*time.Millisecond))
+
+	stats := r.Stats()
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, int64(-1), stats[0].Lost)
+		assert.Equal(t, uint64(8), stats[0].Received)
+		assert.Equal(t, start.Add(50*time.Millisecond), stats[0].LastSenderReport)
+	}
+
+	rr = r.Report(start.Add(2 * time.Second))
+	if assert.Len(t, rr.Reports, 1) {
+		assert.Equal(t, uint8(0), rr.Reports[0].FractionLost)
+		assert.Equal(t, int32(-1), rr.Reports[0].TotalLost)
+	}
+
+	r.ProcessRTCP([]Packet{&Goodbye{Sources: []uint32{0x2000}}}, start)
+	assert.Empty(t, r.Stats())
+}
@@ -0,0 +1,189 @@
+package rtcp
+
+import "encoding/binary"
+
+// ReceptionReport represents report block of SR and RR packets
+type ReceptionReport struct {
+	// SSRC is an identifier of the source to which the report pertains
+	SSRC uint32
+	// FractionLost is a fraction of packets lost since the previous report, in 1/256 units
+	FractionLost uint8
+	// TotalLost is a cumulative number of packets lost (24-bit signed)
+	TotalLost int32
+	// LastSequence is an extended highest sequence number received
+	LastSequence uint32
+	// Jitter is an interarrival jitter in timestamp units
+	Jitter uint32
+	// LastSenderReport is a middle 32 bits of NTP timestamp of the last SR
+	LastSenderReport uint32
+	// Delay is a delay since the last SR in 1/65536 seconds
+	Delay uint32
+}
+
+func (r ReceptionReport) composeTo(buf []byte) {
+	binary.BigEndian.PutUint32(buf[0:4], r.SSRC)
+	lost := uint32(r.TotalLost) & 0xFFFFFF
+	binary.BigEndian.PutUint32(buf[4:8], uint32(r.FractionLost)<<24|lost)
+	binary.BigEndian.PutUint32(buf[8:12], r.LastSequence)
+	binary.BigEndian.PutUint32(buf[12:16], r.Jitter)
+	binary.BigEndian.PutUint32(buf[16:20], r.LastSenderReport)
+	binary.BigEndian.PutUint32(buf[20:24], r.Delay)
+}
+
+func (r *ReceptionReport) parse(buf []byte) {
+	r.SSRC = binary.BigEndian.Uint32(buf[0:4])
+	r.FractionLost = buf[4]
+	// sign extension of 24-bit value
+	r.TotalLost = int32(binary.BigEndian.Uint32(buf[4:8])<<8) >> 8
+	r.LastSequence = binary.BigEndian.Uint32(buf[8:12])
+	r.Jitter = binary.BigEndian.Uint32(buf[12:16])
+	r.LastSenderReport = binary.BigEndian.Uint32(buf[16:20])
+	r.Delay = binary.BigEndian.Uint32(buf[20:24])
+}
+
+func parseReports(pt PacketType, count int, buf []byte) ([]ReceptionReport, []byte, error) {
+	if len(buf) < count*ReceptionReportLength {
+		return nil, nil, ErrMalformedPacket{Type: pt, Reason: "report blocks are truncated"}
+	}
+
+	var reports []ReceptionReport
+	if count != 0 {
+		reports = make([]ReceptionReport, count)
+		for i := range reports {
+			reports[i].parse(buf[i*ReceptionReportLength:])
+		}
+	}
+
+	rest := buf[count*ReceptionReportLength:]
+	if len(rest) == 0 {
+		return reports, nil, nil
+	}
+
+	extensions := make([]byte, len(rest))
+	copy(extensions, rest)
+	return reports, extensions, nil
+}
+
+// SenderReport represents SR packet
+type SenderReport struct {
+	SSRC         uint32
+	NTPTimestamp NTPTime
+	RTPTimestamp uint32
+	PacketCount  uint32
+	OctetCount   uint32
+	Reports      []ReceptionReport
+
+	// ProfileExtensions is a profile-specific content, its length must be multiple of 4
+	ProfileExtensions []byte
+}
+
+func (p SenderReport) Type() PacketType {
+	return TypeSenderReport
+}
+
+func (p SenderReport) Size() int {
+	return HeaderLength + 4 + SenderInfoLength + len(p.Reports)*ReceptionReportLength + align(len(p.ProfileExtensions))
+}
+
+func (p SenderReport) ComposeTo(buf []byte) (int, error) {
+	if len(p.Reports) > MaxCount {
+		return 0, newErrCountLimitExceeded(len(p.Reports))
+	}
+
+	size := p.Size()
+	raw, err := composeHeader(buf, TypeSenderReport, uint8(len(p.Reports)), size)
+	if err != nil {
+		return 0, err
+	}
+
+	raw.SetSSRC(p.SSRC)
+	raw.SetNTPTimestamp(p.NTPTimestamp)
+	raw.SetRTPTimestamp(p.RTPTimestamp)
+	raw.SetPacketCount(p.PacketCount)
+	raw.SetOctetCount(p.OctetCount)
+
+	offset := HeaderLength + 4 + SenderInfoLength
+	for _, r := range p.Reports {
+		r.composeTo(raw[offset:])
+		offset += ReceptionReportLength
+	}
+	copy(raw[offset:], p.ProfileExtensions)
+
+	return size, nil
+}
+
+func (p *SenderReport) Parse(data []byte) error {
+	raw, payload, err := parseTypedHeader(data, TypeSenderReport)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) < 4+SenderInfoLength {
+		return ErrMalformedPacket{Type: TypeSenderReport, Reason: "sender info is truncated"}
+	}
+
+	p.SSRC = raw.SSRC()
+	p.NTPTimestamp = raw.NTPTimestamp()
+	p.RTPTimestamp = raw.RTPTimestamp()
+	p.PacketCount = raw.PacketCount()
+	p.OctetCount = raw.OctetCount()
+	p.Reports, p.ProfileExtensions, err = parseReports(TypeSenderReport, int(raw.Count()), payload[4+SenderInfoLength:])
+
+	return err
+}
+
+// ReceiverReport represents RR packet
+type ReceiverReport struct {
+	SSRC    uint32
+	Reports []ReceptionReport
+
+	// ProfileExtensions is a profile-specific content, its length must be multiple of 4
+	ProfileExtensions []byte
+}
+
+func (p ReceiverReport) Type() PacketType {
+	return TypeReceiverReport
+}
+
+func (p ReceiverReport) Size() int {
+	return HeaderLength + 4 + len(p.Reports)*ReceptionReportLength + align(len(p.ProfileExtensions))
+}
+
+func (p ReceiverReport) ComposeTo(buf []byte) (int, error) {
+	if len(p.Reports) > MaxCount {
+		return 0, newErrCountLimitExceeded(len(p.Reports))
+	}
+
+	size := p.Size()
+	raw, err := composeHeader(buf, TypeReceiverReport, uint8(len(p.Reports)), size)
+	if err != nil {
+		return 0, err
+	}
+
+	raw.SetSSRC(p.SSRC)
+
+	offset := HeaderLength + 4
+	for _, r := range p.Reports {
+		r.composeTo(raw[offset:])
+		offset += ReceptionReportLength
+	}
+	copy(raw[offset:], p.ProfileExtensions)
+
+	return size, nil
+}
+
+func (p *ReceiverReport) Parse(data []byte) error {
+	raw, payload, err := parseTypedHeader(data, TypeReceiverReport)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) < 4 {
+		return ErrMalformedPacket{Type: TypeReceiverReport, Reason: "SSRC is missing"}
+	}
+
+	p.SSRC = raw.SSRC()
+	p.Reports, p.ProfileExtensions, err = parseReports(TypeReceiverReport, int(raw.Count()), payload[4:])
+
+	return err
+}
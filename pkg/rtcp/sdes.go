@@ -0,0 +1,137 @@
+package rtcp
+
+import "encoding/binary"
+
+// SDESItem represents single item of SDES chunk
+type SDESItem struct {
+	Type SDESType
+	Text string
+}
+
+// SDESChunk represents items which describe single source
+type SDESChunk struct {
+	Source uint32
+	Items  []SDESItem
+}
+
+func (c SDESChunk) size() int {
+	// source identifier + items + END item
+	size := 4 + 1
+	for _, item := range c.Items {
+		size += 2 + len(item.Text)
+	}
+	return align(size)
+}
+
+// SourceDescription represents SDES packet
+type SourceDescription struct {
+	Chunks []SDESChunk
+}
+
+// NewCNAME makes SDES packet with single CNAME item
+func NewCNAME(ssrc uint32, cname string) *SourceDescription {
+	return &SourceDescription{
+		Chunks: []SDESChunk{
+			{
+				Source: ssrc,
+				Items:  []SDESItem{{Type: SDESCNAME, Text: cname}},
+			},
+		},
+	}
+}
+
+func (p SourceDescription) Type() PacketType {
+	return TypeSourceDescription
+}
+
+func (p SourceDescription) Size() int {
+	size := HeaderLength
+	for _, c := range p.Chunks {
+		size += c.size()
+	}
+	return size
+}
+
+func (p SourceDescription) ComposeTo(buf []byte) (int, error) {
+	if len(p.Chunks) > MaxCount {
+		return 0, newErrCountLimitExceeded(len(p.Chunks))
+	}
+
+	for _, c := range p.Chunks {
+		for _, item := range c.Items {
+			if len(item.Text) > 255 {
+				return 0, ErrMalformedPacket{Type: TypeSourceDescription, Reason: "item text is too long"}
+			}
+		}
+	}
+
+	size := p.Size()
+	raw, err := composeHeader(buf, TypeSourceDescription, uint8(len(p.Chunks)), size)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := HeaderLength
+	for _, c := range p.Chunks {
+		binary.BigEndian.PutUint32(raw[offset:], c.Source)
+		pos := offset + 4
+		for _, item := range c.Items {
+			raw[pos] = uint8(item.Type)
+			raw[pos+1] = uint8(len(item.Text))
+			copy(raw[pos+2:], item.Text)
+			pos += 2 + len(item.Text)
+		}
+		// END item and padding are zeroes already
+		offset += c.size()
+	}
+
+	return size, nil
+}
+
+func (p *SourceDescription) Parse(data []byte) error {
+	raw, payload, err := parseTypedHeader(data, TypeSourceDescription)
+	if err != nil {
+		return err
+	}
+
+	malformed := func(reason string) error {
+		return ErrMalformedPacket{Type: TypeSourceDescription, Reason: reason}
+	}
+
+	count := int(raw.Count())
+	p.Chunks = nil
+	if count != 0 {
+		p.Chunks = make([]SDESChunk, 0, count)
+	}
+
+	offset := 0
+	for i := 0; i < count; i++ {
+		if len(payload) < offset+4 {
+			return malformed("chunk is truncated")
+		}
+
+		chunk := SDESChunk{Source: binary.BigEndian.Uint32(payload[offset:])}
+		pos := offset + 4
+		for {
+			if pos >= len(payload) {
+				return malformed("END item is missing")
+			}
+			t := SDESType(payload[pos])
+			if t == SDESEnd {
+				break
+			}
+			if pos+2 > len(payload) || pos+2+int(payload[pos+1]) > len(payload) {
+				return malformed("item is truncated")
+			}
+			length := int(payload[pos+1])
+			chunk.Items = append(chunk.Items, SDESItem{Type: t, Text: string(payload[pos+2 : pos+2+length])})
+			pos += 2 + length
+		}
+
+		// skip END item and padding up to 32-bit boundary
+		offset = align(pos + 1)
+		p.Chunks = append(p.Chunks, chunk)
+	}
+
+	return nil
+}
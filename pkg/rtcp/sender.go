@@ -0,0 +1,148 @@
+package rtcp
+
+import (
+	"time"
+
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// lowerLayerOverhead is a size of UDP and IP headers which is counted in average RTCP packet size
+const lowerLayerOverhead = 28
+
+// SenderStats are statistics of outgoing stream
+type SenderStats struct {
+	SSRC        uint32
+	PacketCount uint32
+	OctetCount  uint32
+}
+
+// Sender counts packets and octets of outgoing stream and builds sender reports (RFC 3550 section 6.4.1)
+type Sender struct {
+	// SSRC is an identifier of the stream
+	SSRC uint32
+
+	// ClockRate is an RTP clock rate of the stream
+	ClockRate int
+
+	// CNAME is a canonical name of the sender, it is sent in SDES packet with every report
+	CNAME string
+
+	// Bandwidth is a session bandwidth in bits per second, RTCP interval is scaled to 5% of it.
+	// Only minimal interval is applied if it is zero
+	Bandwidth int
+
+	// Members is a count of session participants including the sender, 2 if zero
+	Members int
+
+	stats SenderStats
+
+	// the last sent packet
+	timestamp uint32
+	sent      time.Time
+	started   bool
+
+	// schedule of reports
+	avgSize  float64
+	next     time.Time
+	reported bool
+}
+
+// NewSender creates sender of the stream
+func NewSender(ssrc uint32, clockRate int, cname string) *Sender {
+	return &Sender{
+		SSRC:      ssrc,
+		ClockRate: clockRate,
+		CNAME:     cname,
+	}
+}
+
+// ProcessRTP counts sent packet
+func (s *Sender) ProcessRTP(p *rtp.Packet, sent time.Time) {
+	s.stats.PacketCount++
+	s.stats.OctetCount += uint32(len(p.Payload))
+	s.timestamp = p.Header.Timestamp
+	s.sent = sent
+
+	if !s.started {
+		s.started = true
+		s.next = sent.Add(s.interval())
+	}
+}
+
+// Due returns true if it's time to send the next report. Reports aren't sent until the first packet
+func (s *Sender) Due(now time.Time) bool {
+	return s.started && !now.Before(s.next)
+}
+
+// Next returns time of the next report, it is zero until the first packet
+func (s *Sender) Next() time.Time {
+	return s.next
+}
+
+// Report builds compound packet of SR and SDES with CNAME and schedules the next report
+func (s *Sender) Report(now time.Time) []Packet {
+	packets := []Packet{s.senderReport(now), NewCNAME(s.SSRC, s.CNAME)}
+
+	size := lowerLayerOverhead
+	for _, p := range packets {
+		size += p.Size()
+	}
+	if s.avgSize == 0 {
+		s.avgSize = float64(size)
+	} else {
+		s.avgSize += (float64(size) - s.avgSize) / 16
+	}
+	s.reported = true
+	s.next = now.Add(s.interval())
+
+	return packets
+}
+
+// Goodbye builds compound packet of SR, SDES and BYE which is sent when the stream is finished
+func (s *Sender) Goodbye(now time.Time, reason string) []Packet {
+	return []Packet{
+		s.senderReport(now),
+		NewCNAME(s.SSRC, s.CNAME),
+		&Goodbye{Sources: []uint32{s.SSRC}, Reason: reason},
+	}
+}
+
+// Stats returns statistics of the stream
+func (s *Sender) Stats() SenderStats {
+	stats := s.stats
+	stats.SSRC = s.SSRC
+	return stats
+}
+
+func (s *Sender) senderReport(now time.Time) *SenderReport {
+	// RTP timestamp corresponds to the report time, not to the last packet time
+	timestamp := s.timestamp
+	if s.started && s.ClockRate > 0 {
+		elapsed := now.Sub(s.sent)
+		rate := int64(s.ClockRate)
+		timestamp += uint32(int64(elapsed/time.Second)*rate + int64(elapsed%time.Second)*rate/int64(time.Second))
+	}
+
+	return &SenderReport{
+		SSRC:         s.SSRC,
+		NTPTimestamp: NewNTPTime(now),
+		RTPTimestamp: timestamp,
+		PacketCount:  s.stats.PacketCount,
+		OctetCount:   s.stats.OctetCount,
+	}
+}
+
+func (s *Sender) interval() time.Duration {
+	members := s.Members
+	if members == 0 {
+		members = 2
+	}
+
+	// the first report isn't sent yet, so its size is estimated by the minimal compound packet
+	avgSize := s.avgSize
+	if avgSize == 0 {
+		avgSize = float64(lowerLayerOverhead + HeaderLength + 4 + SenderInfoLength + NewCNAME(s.SSRC, s.CNAME).Size())
+	}
+
+	return Interval(members, 1, float64(s.Bandwidth)/8*BandwidthFraction, true, avgSize, !s.reported)
+}
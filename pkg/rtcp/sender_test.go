@@ -0,0 +1,78 @@
+package rtcp
+
+import (
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+	type testCase struct {
+		members   int
+		senders   int
+		bandwidth float64
+		weSent    bool
+		initial   bool
+		min       time.Duration
+		max       time.Duration
+	}
+
+	testCases := []testCase{
+		// minimal interval
+		{members: 2, senders: 1, bandwidth: 1000, weSent: true, min: 2052 * time.Millisecond, max: 6157 * time.Millisecond},
+		{members: 2, initial: true, min: 1026 * time.Millisecond, max: 3079 * time.Millisecond},
+		// 100 octets * 4 senders / (100 * 0.25) octets per second
+		{members: 100, senders: 4, bandwidth: 100, weSent: true, min: 6567 * time.Millisecond, max: 19700 * time.Millisecond},
+		// 100 octets * 96 receivers / (100 * 0.75) octets per second
+		{members: 100, senders: 4, bandwidth: 100, min: 52538 * time.Millisecond, max: 157614 * time.Millisecond},
+	}
+
+	for i, c := range testCases {
+		for j := 0; j < 100; j++ {
+			interval := Interval(c.members, c.senders, c.bandwidth, c.weSent, 100, c.initial)
+			assert.GreaterOrEqual(t, interval, c.min, "testCase : %d", i+1)
+			assert.LessOrEqual(t, interval, c.max, "testCase : %d", i+1)
+		}
+	}
+}
+
+func TestSender_Report(t *testing.T) {
+	start := time.Now()
+	s := NewSender(0x1000, 90000, "camera@example.com")
+	assert.False(t, s.Due(start.Add(time.Hour)))
+
+	for i := 0; i < 3; i++ {
+		s.ProcessRTP(&rtp.Packet{
+			Header:  rtp.Header{SSRC: 0x1000, Timestamp: 0xFFFFFFFF - 3000 + uint32(i)*3000},
+			Payload: make([]byte, 100),
+		}, start.Add(time.Duration(i)*time.Second/30))
+	}
+	assert.Equal(t, SenderStats{SSRC: 0x1000, PacketCount: 3, OctetCount: 300}, s.Stats())
+
+	// the first report is sent after the half of minimal interval at least
+	assert.False(t, s.Due(start.Add(time.Second)))
+	assert.True(t, s.Due(start.Add(4*time.Second)))
+
+	now := start.Add(time.Second)
+	packets := s.Report(now)
+	if assert.Len(t, packets, 2) {
+		assert.Equal(t, &SenderReport{
+			SSRC:         0x1000,
+			NTPTimestamp: NewNTPTime(now),
+			RTPTimestamp: 2999 + 84000,
+			PacketCount:  3,
+			OctetCount:   300,
+		}, packets[0])
+		assert.Equal(t, NewCNAME(0x1000, "camera@example.com"), packets[1])
+	}
+	assert.False(t, s.Due(now.Add(2*time.Second)))
+	assert.True(t, s.Due(now.Add(7*time.Second)))
+
+	packets = s.Goodbye(now, "teardown")
+	if assert.Len(t, packets, 3) {
+		assert.Equal(t, &Goodbye{Sources: []uint32{0x1000}, Reason: "teardown"}, packets[2])
+	}
+	_, err := Compose(packets...)
+	assert.NoError(t, err)
+}
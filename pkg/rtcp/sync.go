@@ -0,0 +1,87 @@
+package rtcp
+
+import (
+	"time"
+
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// syncStream is a timeline of single stream
+type syncStream struct {
+	clockRate int
+	timestamp rtp.TimestampUnwrapper
+
+	// reference pair of the last sender report
+	wallClock time.Time
+	reference int64
+	synced    bool
+}
+
+// Synchronizer maps RTP timestamps of streams to wall clock time by NTP/RTP timestamp pairs of sender reports
+// (RFC 3550 section 6.4.1). Streams of the same sender share wall clock, so mapped times of different streams
+// are comparable and may be used for lip-sync
+type Synchronizer struct {
+	streams map[uint32]*syncStream
+}
+
+// NewSynchronizer creates synchronizer without streams
+func NewSynchronizer() *Synchronizer {
+	return &Synchronizer{streams: map[uint32]*syncStream{}}
+}
+
+func (s *Synchronizer) stream(ssrc uint32) *syncStream {
+	st, ok := s.streams[ssrc]
+	if !ok {
+		st = &syncStream{}
+		s.streams[ssrc] = st
+	}
+	return st
+}
+
+// SetClockRate sets RTP clock rate of the stream, timestamps of the stream aren't mapped until it is set
+func (s *Synchronizer) SetClockRate(ssrc uint32, clockRate int) {
+	s.stream(ssrc).clockRate = clockRate
+}
+
+// Update takes reference timestamps of the sender report
+func (s *Synchronizer) Update(sr *SenderReport) {
+	st := s.stream(sr.SSRC)
+	st.wallClock = sr.NTPTimestamp.Time()
+	st.reference = st.timestamp.Unwrap(sr.RTPTimestamp)
+	st.synced = true
+}
+
+// Process takes reference timestamps of all sender reports of compound packet
+func (s *Synchronizer) Process(packets []Packet) {
+	for _, p := range packets {
+		if sr, ok := p.(*SenderReport); ok {
+			s.Update(sr)
+		}
+	}
+}
+
+// Synced returns true if timestamps of the stream can be mapped
+func (s *Synchronizer) Synced(ssrc uint32) bool {
+	st, ok := s.streams[ssrc]
+	return ok && st.synced && st.clockRate > 0
+}
+
+// Time maps RTP timestamp of the stream to wall clock time of the sender, false if sender report
+// or clock rate of the stream is unknown yet
+func (s *Synchronizer) Time(ssrc uint32, timestamp uint32) (time.Time, bool) {
+	st, ok := s.streams[ssrc]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	// timestamps are unwrapped even before the first report to keep track of wraparounds
+	ext := st.timestamp.Unwrap(timestamp)
+	if !st.synced || st.clockRate <= 0 {
+		return time.Time{}, false
+	}
+
+	ticks := ext - st.reference
+	rate := int64(st.clockRate)
+	elapsed := time.Duration(ticks/rate)*time.Second + time.Duration(ticks%rate)*time.Second/time.Duration(rate)
+	return st.wallClock.Add(elapsed), true
+}
@@ -0,0 +1,48 @@
+package rtcp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSynchronizer_Time(t *testing.T) {
+	base := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	s := NewSynchronizer()
+	s.SetClockRate(1, 90000)
+	s.SetClockRate(2, 8000)
+
+	_, ok := s.Time(1, 0xFFFFFFFF-89999)
+	assert.False(t, ok)
+	_, ok = s.Time(3, 0)
+	assert.False(t, ok)
+
+	s.Process([]Packet{
+		&SenderReport{SSRC: 1, NTPTimestamp: NewNTPTime(base), RTPTimestamp: 0xFFFFFFFF - 89999},
+		&SourceDescription{},
+		&SenderReport{SSRC: 2, NTPTimestamp: NewNTPTime(base.Add(time.Second)), RTPTimestamp: 1000},
+	})
+	assert.True(t, s.Synced(1))
+	assert.True(t, s.Synced(2))
+
+	type testCase struct {
+		ssrc      uint32
+		timestamp uint32
+		time      time.Time
+	}
+
+	testCases := []testCase{
+		{ssrc: 1, timestamp: 0xFFFFFFFF - 89999, time: base},
+		// timestamp wraps around
+		{ssrc: 1, timestamp: 45000, time: base.Add(1500 * time.Millisecond)},
+		{ssrc: 1, timestamp: 0xFFFFFFFF - 89999 - 9000, time: base.Add(-100 * time.Millisecond)},
+		{ssrc: 2, timestamp: 1000 + 4000, time: base.Add(1500 * time.Millisecond)},
+		{ssrc: 2, timestamp: 0, time: base.Add(875 * time.Millisecond)},
+	}
+
+	for i, c := range testCases {
+		wallClock, ok := s.Time(c.ssrc, c.timestamp)
+		assert.True(t, ok, "testCase : %d", i+1)
+		assert.WithinDuration(t, c.time, wallClock, time.Microsecond, "testCase : %d", i+1)
+	}
+}
@@ -82,3 +82,21 @@ type ErrPayloadIsMissing struct {
 func (e ErrPayloadIsMissing) Error() string {
 	return "payload is missing"
 }
+
+// ErrDuplicatePacket happens when jitter buffer receives packet which is already received
+type ErrDuplicatePacket struct {
+	SequenceNumber uint16
+}
+
+func (e ErrDuplicatePacket) Error() string {
+	return fmt.Sprintf("duplicate packet: %d", e.SequenceNumber)
+}
+
+// ErrLatePacket happens when jitter buffer receives packet after its place has been released
+type ErrLatePacket struct {
+	SequenceNumber uint16
+}
+
+func (e ErrLatePacket) Error() string {
+	return fmt.Sprintf("late packet: %d", e.SequenceNumber)
+}
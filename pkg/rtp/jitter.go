@@ -0,0 +1,227 @@
+package rtp
+
+import "time"
+
+const (
+	// DefaultJitterLatency is a default time of waiting for reordered packets
+	DefaultJitterLatency = 200 * time.Millisecond
+
+	// DefaultJitterCapacity is a default maximum distance between the oldest awaited and the newest packets
+	DefaultJitterCapacity = 512
+
+	// maxMisorder is a distance to released packets after which the stream is considered restarted
+	// (RFC 3550 appendix A.1)
+	maxMisorder = 100
+
+	// releasedWindow is a count of the last released packets which are remembered to detect duplicates
+	releasedWindow = 128
+)
+
+// Gap is a range of lost packets by extended sequence numbers
+type Gap struct {
+	// From is an extended sequence number of the first lost packet
+	From int64
+	// Count is a count of lost packets
+	Count int
+}
+
+// SequenceNumbers returns sequence numbers of lost packets
+func (g Gap) SequenceNumbers() []uint16 {
+	seqs := make([]uint16, g.Count)
+	for i := range seqs {
+		seqs[i] = uint16(g.From + int64(i))
+	}
+	return seqs
+}
+
+// JitterEntry is released by jitter buffer in sequence order, it is either packet or gap of lost packets
+type JitterEntry struct {
+	// Packet is nil if the entry is a gap
+	Packet *Packet
+
+	// SequenceNumber is an extended sequence number of the packet or the first lost packet of the gap
+	SequenceNumber int64
+
+	// Lost is a count of lost packets of the gap
+	Lost int
+}
+
+// IsGap returns true if the entry reports lost packets
+func (e JitterEntry) IsGap() bool {
+	return e.Packet == nil
+}
+
+// Gap returns range of lost packets of the gap entry
+func (e JitterEntry) Gap() Gap {
+	return Gap{From: e.SequenceNumber, Count: e.Lost}
+}
+
+// JitterStats are counters of jitter buffer
+type JitterStats struct {
+	Received   int
+	Lost       int
+	Duplicates int
+	Late       int
+}
+
+type jitterSlot struct {
+	packet  *Packet
+	arrival time.Time
+}
+
+// JitterBuffer reorders packets of single stream. Missing packets are awaited until the next packet is held
+// longer than Latency or the distance to the newest packet exceeds Capacity, after that they are reported as gap
+type JitterBuffer struct {
+	// Latency is a maximum time of waiting for missing packets
+	Latency time.Duration
+
+	// Capacity is a maximum count of packets which are awaited or held
+	Capacity int
+
+	seq   SequenceUnwrapper
+	slots map[int64]jitterSlot
+	// next is an extended sequence number of the next released packet
+	next    int64
+	started bool
+	// badSeq is an expected sequence number of the restarted stream
+	badSeq int64
+	// released keeps extended sequence numbers of the last released packets incremented by one
+	released [releasedWindow]int64
+
+	stats JitterStats
+}
+
+// NewJitterBuffer creates jitter buffer with specified latency and default capacity
+func NewJitterBuffer(latency time.Duration) *JitterBuffer {
+	return &JitterBuffer{
+		Latency:  latency,
+		Capacity: DefaultJitterCapacity,
+	}
+}
+
+// Push puts received packet to the buffer. Duplicate and late packets are rejected
+func (b *JitterBuffer) Push(p *Packet, arrival time.Time) error {
+	if b.slots == nil {
+		b.slots = map[int64]jitterSlot{}
+	}
+
+	ext := b.seq.Unwrap(p.Header.SequenceNumber)
+	if !b.started {
+		b.started = true
+		b.next = ext
+		b.badSeq = -1
+	}
+
+	if ext < b.next {
+		if b.next-ext <= maxMisorder && b.isReleased(ext) {
+			b.stats.Duplicates++
+			return ErrDuplicatePacket{SequenceNumber: p.Header.SequenceNumber}
+		}
+		if b.next-ext <= maxMisorder || ext != b.badSeq {
+			// two sequential packets far from released ones mean the sender has restarted the stream
+			b.badSeq = ext + 1
+			b.stats.Late++
+			return ErrLatePacket{SequenceNumber: p.Header.SequenceNumber}
+		}
+		b.restart()
+		ext = b.seq.Unwrap(p.Header.SequenceNumber)
+		b.next = ext
+	}
+
+	if _, ok := b.slots[ext]; ok {
+		b.stats.Duplicates++
+		return ErrDuplicatePacket{SequenceNumber: p.Header.SequenceNumber}
+	}
+
+	b.slots[ext] = jitterSlot{packet: p, arrival: arrival}
+	b.stats.Received++
+	return nil
+}
+
+// restart drops held packets, the next packet starts the stream
+func (b *JitterBuffer) restart() {
+	b.stats.Lost += len(b.slots)
+	b.slots = map[int64]jitterSlot{}
+	b.released = [releasedWindow]int64{}
+	b.seq.Reset()
+	b.badSeq = -1
+}
+
+// Pop releases the next entry in sequence order if it is ready at the moment
+func (b *JitterBuffer) Pop(now time.Time) (JitterEntry, bool) {
+	if len(b.slots) == 0 {
+		return JitterEntry{}, false
+	}
+
+	if slot, ok := b.slots[b.next]; ok {
+		delete(b.slots, b.next)
+		b.released[b.next%releasedWindow] = b.next + 1
+		e := JitterEntry{Packet: slot.packet, SequenceNumber: b.next}
+		b.next++
+		return e, true
+	}
+
+	ext, slot := b.first()
+	if now.Sub(slot.arrival) < b.Latency && b.seq.Highest()-b.next < int64(b.capacity()) {
+		return JitterEntry{}, false
+	}
+
+	e := JitterEntry{SequenceNumber: b.next, Lost: int(ext - b.next)}
+	b.stats.Lost += e.Lost
+	b.next = ext
+	return e, true
+}
+
+// Deadline returns time when the next entry is ready, false if there are no held packets
+func (b *JitterBuffer) Deadline() (time.Time, bool) {
+	if len(b.slots) == 0 {
+		return time.Time{}, false
+	}
+	if slot, ok := b.slots[b.next]; ok {
+		return slot.arrival, true
+	}
+	_, slot := b.first()
+	return slot.arrival.Add(b.Latency), true
+}
+
+// Missing returns gaps of packets which are still awaited
+func (b *JitterBuffer) Missing() []Gap {
+	var gaps []Gap
+	for ext := b.next; len(b.slots) != 0 && ext < b.seq.Highest(); ext++ {
+		if _, ok := b.slots[ext]; ok {
+			continue
+		}
+		if n := len(gaps); n != 0 && gaps[n-1].From+int64(gaps[n-1].Count) == ext {
+			gaps[n-1].Count++
+		} else {
+			gaps = append(gaps, Gap{From: ext, Count: 1})
+		}
+	}
+	return gaps
+}
+
+// Stats returns counters of the buffer
+func (b *JitterBuffer) Stats() JitterStats {
+	return b.stats
+}
+
+// isReleased returns true if the packet has been released recently
+func (b *JitterBuffer) isReleased(ext int64) bool {
+	return ext >= 0 && b.released[ext%releasedWindow] == ext+1
+}
+
+// first returns the held packet with the lowest sequence number
+func (b *JitterBuffer) first() (int64, jitterSlot) {
+	for ext := b.next; ; ext++ {
+		if slot, ok := b.slots[ext]; ok {
+			return ext, slot
+		}
+	}
+}
+
+func (b *JitterBuffer) capacity() int {
+	if b.Capacity <= 0 {
+		return DefaultJitterCapacity
+	}
+	return b.Capacity
+}
@@ -0,0 +1,127 @@
+package rtp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func testPacket(seq uint16) *Packet {
+	return &Packet{Header: Header{SequenceNumber: seq}, Payload: []byte{byte(seq)}}
+}
+
+// popAll returns sequence numbers of released packets, gaps are represented by negative count of lost packets
+func popAll(b *JitterBuffer, now time.Time) []int {
+	var result []int
+	for {
+		e, ok := b.Pop(now)
+		if !ok {
+			return result
+		}
+		if e.IsGap() {
+			result = append(result, -e.Lost)
+		} else {
+			result = append(result, int(e.Packet.Header.SequenceNumber))
+		}
+	}
+}
+
+func TestJitterBuffer_Reorder(t *testing.T) {
+	start := time.Now()
+	b := NewJitterBuffer(100 * time.Millisecond)
+
+	for i, seq := range []uint16{65534, 0, 65535, 2, 3} {
+		assert.NoError(t, b.Push(testPacket(seq), start.Add(time.Duration(i)*time.Millisecond)))
+	}
+
+	assert.Equal(t, []int{65534, 65535, 0}, popAll(b, start.Add(10*time.Millisecond)))
+	assert.Equal(t, []Gap{{From: 65537, Count: 1}}, b.Missing())
+	assert.Equal(t, []uint16{1}, b.Missing()[0].SequenceNumbers())
+
+	deadline, ok := b.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(103*time.Millisecond), deadline)
+
+	// missing packet arrives in time
+	assert.NoError(t, b.Push(testPacket(1), start.Add(50*time.Millisecond)))
+	assert.Equal(t, []int{1, 2, 3}, popAll(b, start.Add(50*time.Millisecond)))
+
+	_, ok = b.Deadline()
+	assert.False(t, ok)
+	assert.Equal(t, JitterStats{Received: 6}, b.Stats())
+}
+
+func TestJitterBuffer_Loss(t *testing.T) {
+	start := time.Now()
+	b := NewJitterBuffer(100 * time.Millisecond)
+
+	for _, seq := range []uint16{10, 13, 15} {
+		assert.NoError(t, b.Push(testPacket(seq), start))
+	}
+
+	assert.Equal(t, []int{10}, popAll(b, start.Add(99*time.Millisecond)))
+	assert.Equal(t, []Gap{{From: 11, Count: 2}, {From: 14, Count: 1}}, b.Missing())
+	assert.Equal(t, []int{-2, 13, -1, 15}, popAll(b, start.Add(100*time.Millisecond)))
+
+	// packet of the released gap is late
+	assert.ErrorIs(t, b.Push(testPacket(12), start), ErrLatePacket{SequenceNumber: 12})
+	assert.Equal(t, JitterStats{Received: 3, Lost: 3, Late: 1}, b.Stats())
+}
+
+func TestJitterBuffer_Capacity(t *testing.T) {
+	start := time.Now()
+	b := NewJitterBuffer(time.Second)
+	b.Capacity = 4
+
+	assert.NoError(t, b.Push(testPacket(1), start))
+	assert.NoError(t, b.Push(testPacket(3), start))
+	assert.Equal(t, []int{1}, popAll(b, start))
+
+	assert.NoError(t, b.Push(testPacket(5), start))
+	assert.Empty(t, popAll(b, start))
+
+	assert.NoError(t, b.Push(testPacket(6), start))
+	assert.Equal(t, []int{-1, 3}, popAll(b, start))
+
+	assert.NoError(t, b.Push(testPacket(8), start))
+	assert.Equal(t, []int{-1, 5, 6}, popAll(b, start))
+}
+
+func TestJitterBuffer_Duplicate(t *testing.T) {
+	start := time.Now()
+	b := NewJitterBuffer(time.Second)
+
+	assert.NoError(t, b.Push(testPacket(1), start))
+	assert.NoError(t, b.Push(testPacket(3), start))
+	assert.ErrorIs(t, b.Push(testPacket(3), start), ErrDuplicatePacket{SequenceNumber: 3})
+	assert.Equal(t, []int{1}, popAll(b, start))
+	assert.ErrorIs(t, b.Push(testPacket(1), start), ErrDuplicatePacket{SequenceNumber: 1})
+	assert.Equal(t, JitterStats{Received: 2, Duplicates: 2}, b.Stats())
+
+	// packet before the first one is late even if its sequence number wraps around
+	b = NewJitterBuffer(time.Second)
+	assert.NoError(t, b.Push(testPacket(0), start))
+	assert.Equal(t, []int{0}, popAll(b, start))
+	assert.ErrorIs(t, b.Push(testPacket(65535), start), ErrLatePacket{SequenceNumber: 65535})
+	assert.Equal(t, JitterStats{Received: 1, Late: 1}, b.Stats())
+}
+
+func TestJitterBuffer_Restart(t *testing.T) {
+	start := time.Now()
+	b := NewJitterBuffer(time.Second)
+
+	for seq := uint16(5000); seq < 5010; seq++ {
+		assert.NoError(t, b.Push(testPacket(seq), start))
+	}
+	assert.Len(t, popAll(b, start), 10)
+
+	// held packet is dropped on restart
+	assert.NoError(t, b.Push(testPacket(5011), start))
+
+	// sender restarts the stream from the other sequence number
+	assert.ErrorIs(t, b.Push(testPacket(100), start), ErrLatePacket{SequenceNumber: 100})
+	assert.NoError(t, b.Push(testPacket(101), start))
+	assert.NoError(t, b.Push(testPacket(102), start))
+	assert.Equal(t, []int{101, 102}, popAll(b, start))
+	assert.Equal(t, JitterStats{Received: 13, Lost: 1, Late: 1}, b.Stats())
+}
@@ -0,0 +1,70 @@
+package rtp
+
+// Static payload types (RFC 3551 section 6)
+const (
+	PayloadTypePCMU uint8 = 0
+	PayloadTypeGSM  uint8 = 3
+	PayloadTypeG723 uint8 = 4
+	PayloadTypePCMA uint8 = 8
+	PayloadTypeG722 uint8 = 9
+	PayloadTypeL16S uint8 = 10
+	PayloadTypeL16  uint8 = 11
+	PayloadTypeCN   uint8 = 13
+	PayloadTypeMPA  uint8 = 14
+	PayloadTypeG728 uint8 = 15
+	PayloadTypeG729 uint8 = 18
+	PayloadTypeJPEG uint8 = 26
+	PayloadTypeH261 uint8 = 31
+	PayloadTypeMPV  uint8 = 32
+	PayloadTypeMP2T uint8 = 33
+	PayloadTypeH263 uint8 = 34
+
+	// PayloadTypeDynamic is the first payload type of dynamic range
+	PayloadTypeDynamic uint8 = 96
+)
+
+// PayloadFormat describes encoding of payload type
+type PayloadFormat struct {
+	EncodingName string
+	ClockRate    int
+	// Channels is a count of audio channels, 0 for video
+	Channels int
+}
+
+var staticPayloadTypes = map[uint8]PayloadFormat{
+	0:  {EncodingName: "PCMU", ClockRate: 8000, Channels: 1},
+	3:  {EncodingName: "GSM", ClockRate: 8000, Channels: 1},
+	4:  {EncodingName: "G723", ClockRate: 8000, Channels: 1},
+	5:  {EncodingName: "DVI4", ClockRate: 8000, Channels: 1},
+	6:  {EncodingName: "DVI4", ClockRate: 16000, Channels: 1},
+	7:  {EncodingName: "LPC", ClockRate: 8000, Channels: 1},
+	8:  {EncodingName: "PCMA", ClockRate: 8000, Channels: 1},
+	9:  {EncodingName: "G722", ClockRate: 8000, Channels: 1},
+	10: {EncodingName: "L16", ClockRate: 44100, Channels: 2},
+	11: {EncodingName: "L16", ClockRate: 44100, Channels: 1},
+	12: {EncodingName: "QCELP", ClockRate: 8000, Channels: 1},
+	13: {EncodingName: "CN", ClockRate: 8000, Channels: 1},
+	14: {EncodingName: "MPA", ClockRate: 90000},
+	15: {EncodingName: "G728", ClockRate: 8000, Channels: 1},
+	16: {EncodingName: "DVI4", ClockRate: 11025, Channels: 1},
+	17: {EncodingName: "DVI4", ClockRate: 22050, Channels: 1},
+	18: {EncodingName: "G729", ClockRate: 8000, Channels: 1},
+	25: {EncodingName: "CelB", ClockRate: 90000},
+	26: {EncodingName: "JPEG", ClockRate: 90000},
+	28: {EncodingName: "nv", ClockRate: 90000},
+	31: {EncodingName: "H261", ClockRate: 90000},
+	32: {EncodingName: "MPV", ClockRate: 90000},
+	33: {EncodingName: "MP2T", ClockRate: 90000},
+	34: {EncodingName: "H263", ClockRate: 90000},
+}
+
+// StaticPayloadFormat returns encoding of statically assigned payload type
+func StaticPayloadFormat(payloadType uint8) (PayloadFormat, bool) {
+	f, ok := staticPayloadTypes[payloadType]
+	return f, ok
+}
+
+// IsDynamicPayloadType returns true if payload type belongs to dynamic range 96-127
+func IsDynamicPayloadType(payloadType uint8) bool {
+	return payloadType >= PayloadTypeDynamic && payloadType <= MaxPayloadType
+}
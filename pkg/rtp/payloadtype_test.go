@@ -0,0 +1,33 @@
+package rtp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStaticPayloadFormat(t *testing.T) {
+	type testCase struct {
+		pt     uint8
+		format PayloadFormat
+		ok     bool
+	}
+
+	testCases := []testCase{
+		{pt: PayloadTypePCMU, format: PayloadFormat{EncodingName: "PCMU", ClockRate: 8000, Channels: 1}, ok: true},
+		{pt: PayloadTypeG722, format: PayloadFormat{EncodingName: "G722", ClockRate: 8000, Channels: 1}, ok: true},
+		{pt: PayloadTypeL16S, format: PayloadFormat{EncodingName: "L16", ClockRate: 44100, Channels: 2}, ok: true},
+		{pt: PayloadTypeMP2T, format: PayloadFormat{EncodingName: "MP2T", ClockRate: 90000}, ok: true},
+		{pt: 2},
+		{pt: 96},
+	}
+
+	for i, c := range testCases {
+		format, ok := StaticPayloadFormat(c.pt)
+		assert.Equal(t, c.ok, ok, "testCase : %d", i+1)
+		assert.Equal(t, c.format, format, "testCase : %d", i+1)
+	}
+
+	assert.True(t, IsDynamicPayloadType(96))
+	assert.True(t, IsDynamicPayloadType(MaxPayloadType))
+	assert.False(t, IsDynamicPayloadType(PayloadTypeH263))
+}
@@ -0,0 +1,33 @@
+package rtp
+
+// SequenceUnwrapper extends 16-bit sequence numbers of single stream to 64-bit ones, which don't wrap around.
+// Packets reordered by less than half of sequence number space are placed correctly
+type SequenceUnwrapper struct {
+	highest int64
+	started bool
+}
+
+// Unwrap returns extended sequence number. The first sequence number is extended as is
+func (u *SequenceUnwrapper) Unwrap(seq uint16) int64 {
+	if !u.started {
+		u.started = true
+		u.highest = int64(seq)
+		return u.highest
+	}
+
+	ext := u.highest + int64(int16(seq-uint16(u.highest)))
+	if ext > u.highest {
+		u.highest = ext
+	}
+	return ext
+}
+
+// Highest returns the highest extended sequence number
+func (u *SequenceUnwrapper) Highest() int64 {
+	return u.highest
+}
+
+// Reset forgets the stream, the next sequence number is extended as the first one
+func (u *SequenceUnwrapper) Reset() {
+	*u = SequenceUnwrapper{}
+}
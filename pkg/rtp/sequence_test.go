@@ -0,0 +1,45 @@
+package rtp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSequenceUnwrapper_Unwrap(t *testing.T) {
+	type testCase struct {
+		seqs     []uint16
+		extended []int64
+	}
+
+	testCases := []testCase{
+		{
+			seqs:     []uint16{100, 101, 103, 102, 104},
+			extended: []int64{100, 101, 103, 102, 104},
+		},
+		{
+			seqs:     []uint16{65534, 65535, 0, 1, 65533, 2},
+			extended: []int64{65534, 65535, 65536, 65537, 65533, 65538},
+		},
+		{
+			seqs:     []uint16{1, 0, 65535, 3},
+			extended: []int64{1, 0, -1, 3},
+		},
+	}
+
+	for i, c := range testCases {
+		var u SequenceUnwrapper
+		for j, seq := range c.seqs {
+			assert.Equal(t, c.extended[j], u.Unwrap(seq), "testCase : %d", i+1)
+		}
+	}
+}
+
+func TestTimestampUnwrapper_Unwrap(t *testing.T) {
+	var u TimestampUnwrapper
+	timestamps := []uint32{0xFFFFFF00, 0x00000100, 0xFFFFFFF0, 0x800000FF}
+	extended := []int64{0xFFFFFF00, 0x100000100, 0xFFFFFFF0, 0x1800000FF}
+
+	for i, ts := range timestamps {
+		assert.Equal(t, extended[i], u.Unwrap(ts), "timestamp : %d", i+1)
+	}
+}
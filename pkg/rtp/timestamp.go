@@ -0,0 +1,23 @@
+package rtp
+
+// TimestampUnwrapper extends 32-bit timestamps of single stream to 64-bit ones, which don't wrap around.
+// Timestamps preceding the highest one by less than half of timestamp space are placed correctly
+type TimestampUnwrapper struct {
+	highest int64
+	started bool
+}
+
+// Unwrap returns extended timestamp. The first timestamp is extended as is
+func (u *TimestampUnwrapper) Unwrap(ts uint32) int64 {
+	if !u.started {
+		u.started = true
+		u.highest = int64(ts)
+		return u.highest
+	}
+
+	ext := u.highest + int64(int32(ts-uint32(u.highest)))
+	if ext > u.highest {
+		u.highest = ext
+	}
+	return ext
+}
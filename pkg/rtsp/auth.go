@@ -0,0 +1,254 @@
+package rtsp
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// Authentication schemes
+const (
+	AuthBasic  = "Basic"
+	AuthDigest = "Digest"
+)
+
+// Digest algorithms (RFC 7616)
+const (
+	DigestMD5    = "MD5"
+	DigestSHA256 = "SHA-256"
+)
+
+// Challenge represents WWW-Authenticate header value
+type Challenge struct {
+	Scheme    string
+	Realm     string
+	Nonce     string
+	Opaque    string
+	Algorithm string
+	// QOP is a list of supported quality of protection values, e.g. auth
+	QOP   []string
+	Stale bool
+}
+
+// ParseChallenge parses WWW-Authenticate header value
+func ParseChallenge(header string) (Challenge, error) {
+	header = strings.TrimSpace(header)
+	scheme := header
+	params := ""
+	if i := strings.IndexByte(header, ' '); i >= 0 {
+		scheme, params = header[:i], header[i+1:]
+	}
+
+	c := Challenge{}
+	switch {
+	case strings.EqualFold(scheme, AuthBasic):
+		c.Scheme = AuthBasic
+	case strings.EqualFold(scheme, AuthDigest):
+		c.Scheme = AuthDigest
+	default:
+		return Challenge{}, fmt.Errorf("unsupported authentication scheme: %q", scheme)
+	}
+
+	for _, param := range splitQuoted(params, ',') {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(kv[1]), "\"")
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "realm":
+			c.Realm = value
+		case "nonce":
+			c.Nonce = value
+		case "opaque":
+			c.Opaque = value
+		case "algorithm":
+			c.Algorithm = strings.ToUpper(value)
+		case "qop":
+			for _, qop := range strings.Split(value, ",") {
+				c.QOP = append(c.QOP, strings.TrimSpace(qop))
+			}
+		case "stale":
+			c.Stale = strings.EqualFold(value, "true")
+		}
+	}
+
+	if c.Scheme == AuthDigest {
+		if c.Nonce == "" {
+			return Challenge{}, fmt.Errorf("digest challenge without nonce")
+		}
+		if c.Algorithm == "" {
+			c.Algorithm = DigestMD5
+		}
+		if c.newHash() == nil {
+			return Challenge{}, fmt.Errorf("unsupported digest algorithm: %s", c.Algorithm)
+		}
+	}
+
+	return c, nil
+}
+
+// strength is used to choose the most secure challenge
+func (c Challenge) strength() int {
+	switch {
+	case c.Scheme == AuthBasic:
+		return 1
+	case strings.HasPrefix(c.Algorithm, DigestSHA256):
+		return 3
+	default:
+		return 2
+	}
+}
+
+func (c Challenge) newHash() func() hash.Hash {
+	switch strings.TrimSuffix(c.Algorithm, "-SESS") {
+	case DigestMD5:
+		return md5.New
+	case DigestSHA256:
+		return sha256.New
+	}
+	return nil
+}
+
+func (c Challenge) hasQOP(qop string) bool {
+	for _, v := range c.QOP {
+		if v == qop {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorization builds Authorization header value for specified request
+func (c Challenge) Authorization(user, password string, method Method, uri string, nc uint32, cnonce string) string {
+	if c.Scheme == AuthBasic {
+		return AuthBasic + " " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+	}
+
+	h := c.newHash()
+	digest := func(s string) string {
+		d := h()
+		d.Write([]byte(s))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	ha1 := digest(user + ":" + c.Realm + ":" + password)
+	if strings.HasSuffix(c.Algorithm, "-SESS") {
+		ha1 = digest(ha1 + ":" + c.Nonce + ":" + cnonce)
+	}
+	ha2 := digest(string(method) + ":" + uri)
+
+	params := []string{
+		fmt.Sprintf("username=%q", user),
+		fmt.Sprintf("realm=%q", c.Realm),
+		fmt.Sprintf("nonce=%q", c.Nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+
+	var response string
+	if c.hasQOP("auth") {
+		ncValue := fmt.Sprintf("%08x", nc)
+		response = digest(ha1 + ":" + c.Nonce + ":" + ncValue + ":" + cnonce + ":auth:" + ha2)
+		params = append(params,
+			fmt.Sprintf("response=%q", response),
+			"qop=auth",
+			"nc="+ncValue,
+			fmt.Sprintf("cnonce=%q", cnonce),
+		)
+	} else {
+		response = digest(ha1 + ":" + c.Nonce + ":" + ha2)
+		params = append(params, fmt.Sprintf("response=%q", response))
+	}
+
+	if c.Algorithm != DigestMD5 {
+		params = append(params, "algorithm="+c.Algorithm)
+	}
+	if c.Opaque != "" {
+		params = append(params, fmt.Sprintf("opaque=%q", c.Opaque))
+	}
+
+	return AuthDigest + " " + strings.Join(params, ", ")
+}
+
+// authenticator keeps credentials and the last challenge to authorize session requests
+type authenticator struct {
+	mu        sync.Mutex
+	user      string
+	password  string
+	challenge *Challenge
+	nc        uint32
+}
+
+// authorize adds Authorization header if credentials and challenge are known
+func (a *authenticator) authorize(r *Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if r.URL != nil && r.URL.User != nil {
+		a.user = r.URL.User.Username()
+		a.password, _ = r.URL.User.Password()
+	}
+
+	if a.challenge == nil {
+		return
+	}
+
+	a.nc++
+	if r.Header == nil {
+		r.Header = make(map[string][]string)
+	}
+	r.Header.Set("Authorization", a.challenge.Authorization(a.user, a.password, r.Method, requestURI(r), a.nc, newCNonce()))
+}
+
+// update selects the strongest challenge of the response.
+// Returns true if the request should be repeated with new credentials
+func (a *authenticator) update(r *Request, resp *Response) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.user == "" {
+		return false
+	}
+
+	var best *Challenge
+	for _, header := range resp.Header.Values("WWW-Authenticate") {
+		c, err := ParseChallenge(header)
+		if err != nil {
+			continue
+		}
+		if best == nil || c.strength() > best.strength() {
+			best = &c
+		}
+	}
+	if best == nil {
+		return false
+	}
+
+	// credentials have been rejected, if the nonce is not stale
+	if r.Header.Get("Authorization") != "" && !best.Stale {
+		return false
+	}
+
+	a.challenge = best
+	a.nc = 0
+	return true
+}
+
+// requestURI returns request URL without user info
+func requestURI(r *Request) string {
+	u := *r.URL
+	u.User = nil
+	return u.String()
+}
+
+func newCNonce() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -5,6 +5,8 @@ import (
 )
 
 var (
-	ErrInvalidURL      = errors.New("URL must be rtsp://host:port/path")
-	ErrMethodMustBeSet = errors.New("method must be set")
+	ErrInvalidURL       = errors.New("URL must be rtsp://host:port/path")
+	ErrMethodMustBeSet  = errors.New("method must be set")
+	ErrSessionClosed    = errors.New("session closed")
+	ErrInvalidTransport = errors.New("invalid Transport header")
 )
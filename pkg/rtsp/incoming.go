@@ -1,6 +1,9 @@
 package rtsp
 
-import "github.com/racoon-devel/gortsp/pkg/rtp"
+import (
+	"github.com/racoon-devel/gortsp/pkg/rtcp"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
 
 // IncomingRTP helps to receive RTP packet from stream
 type IncomingRTP struct {
@@ -11,5 +14,5 @@ type IncomingRTP struct {
 // IncomingRTCP helps to receive RTCP packet from stream
 type IncomingRTCP struct {
 	Channel uint8
-	Packet  []byte
+	Packet  rtcp.RawCompound
 }
@@ -0,0 +1,62 @@
+package rtsp
+
+import (
+	"fmt"
+	"net"
+)
+
+// MulticastGroup describes multicast group membership of the media
+type MulticastGroup struct {
+	// Group is a multicast group address
+	Group net.IP
+	// Ports is RTP/RTCP port pair of the group
+	Ports PortRange
+	// Interface is a network interface to join the group on, system default is used if nil
+	Interface *net.Interface
+	// Source is an address of the sender. Datagrams from other hosts are dropped by the session if it is set.
+	// It is only an application level filter: the group is joined for any source, so the host still
+	// receives traffic of all senders of the group
+	Source net.IP
+}
+
+// JoinMulticast joins multicast group on RTP and RTCP ports. Received packets are forwarded to Incoming()
+// like ones received by ListenUDP. WritePacket sends RTCP packets of the channel to the group
+func (s *Session) JoinMulticast(channel uint8, m MulticastGroup) error {
+	if m.Group == nil || !m.Group.IsMulticast() {
+		return fmt.Errorf("invalid multicast group: %s", m.Group)
+	}
+
+	network := "udp4"
+	if m.Group.To4() == nil {
+		network = "udp6"
+	}
+
+	rtpAddr := &net.UDPAddr{IP: m.Group, Port: int(m.Ports.From)}
+	rtcpAddr := &net.UDPAddr{IP: m.Group, Port: int(m.Ports.To)}
+
+	rtpConn, err := net.ListenMulticastUDP(network, m.Interface, rtpAddr)
+	if err != nil {
+		return fmt.Errorf("join %s failed: %w", rtpAddr, err)
+	}
+
+	rtcpConn, err := net.ListenMulticastUDP(network, m.Interface, rtcpAddr)
+	if err != nil {
+		_ = rtpConn.Close()
+		return fmt.Errorf("join %s failed: %w", rtcpAddr, err)
+	}
+
+	pair := &udpPair{
+		rtp:      rtpConn,
+		rtcp:     rtcpConn,
+		rtpAddr:  rtpAddr,
+		rtcpAddr: rtcpAddr,
+		source:   m.Source,
+	}
+	if err = s.addUDP(channel, pair); err != nil {
+		pair.close()
+		return err
+	}
+
+	s.startUDP(channel, pair)
+	return nil
+}
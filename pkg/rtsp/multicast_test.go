@@ -0,0 +1,33 @@
+package rtsp
+
+import (
+	"context"
+	"github.com/racoon-devel/gortsp/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+func TestSession_JoinMulticast(t *testing.T) {
+	mock := mocks.NewConnMock()
+	defer mock.Close()
+
+	s := NewSession(mock.Client(), context.Background())
+	defer s.Close()
+
+	assert.Error(t, s.JoinMulticast(0, MulticastGroup{Group: net.IPv4(192, 168, 1, 1)}))
+
+	group := net.IPv4(239, 255, 42, 99)
+	ports := PortRange{From: 47770, To: 47771}
+	if err := s.JoinMulticast(0, MulticastGroup{Group: group, Ports: ports}); err != nil {
+		t.Skipf("multicast is not available: %s", err)
+	}
+
+	sender, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: group, Port: int(ports.From)})
+	assert.NoError(t, err)
+	defer sender.Close()
+
+	_, err = sender.Write([]byte{0x80, 0x60})
+	assert.NoError(t, err)
+	assert.Equal(t, &IncomingRTP{Channel: 0, Packet: []byte{0x80, 0x60}}, waitIncoming(t, s))
+}
@@ -63,11 +63,11 @@ func (r Request) Write(w io.Writer) error {
 	}
 
 	if len(r.Body) != 0 {
-		r.Header.Add("Content-Length", strconv.Itoa(len(r.Body)))
+		r.Header.Set("Content-Length", strconv.Itoa(len(r.Body)))
 	}
 
 	bw := bufio.NewWriter(w)
-	_, err := bw.WriteString(fmt.Sprintf("%s %s RTSP/1.0\r\n", r.Method, r.URL))
+	_, err := bw.WriteString(fmt.Sprintf("%s %s RTSP/1.0\r\n", r.Method, requestURI(&r)))
 	if err != nil {
 		return err
 	}
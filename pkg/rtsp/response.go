@@ -11,7 +11,7 @@ import (
 )
 
 var (
-	statusLineRegEx = regexp.MustCompile("^RTSP\\/(\\d).(\\d)[\\s]+(\\d\\d\\d)[\\s]+([\\w\\s]+)$")
+	statusLineRegEx = regexp.MustCompile("^RTSP\\/(\\d).(\\d)[\\s]+(\\d\\d\\d)[\\s]+([\\w\\s-]+)$")
 )
 
 // Response represents RTSP response
@@ -70,7 +70,7 @@ func (r Response) Write(w io.Writer) error {
 	}
 
 	if len(r.Body) > 0 {
-		r.Header.Add("Content-Length", strconv.Itoa(len(r.Body)))
+		r.Header.Set("Content-Length", strconv.Itoa(len(r.Body)))
 	}
 
 	if err := r.Header.Write(bw); err != nil {
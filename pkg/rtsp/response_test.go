@@ -43,6 +43,18 @@ func TestResponse_Read(t *testing.T) {
 				},
 			},
 		},
+		{
+			raw: "RTSP/1.0 414 Request-URI Too Large\r\nCseq: 1\r\n\r\n",
+			r: Response{
+				Status:     "Request-URI Too Large",
+				StatusCode: RequestURITooLarge,
+				ProtoMajor: 1,
+				ProtoMinor: 0,
+				Header: http.Header{
+					"Cseq": {"1"},
+				},
+			},
+		},
 		{
 			raw: "RTSP/1.0 200 OKCseq: 1\r\nPublic: DESCRIBE, GET_PARAMETER, SET_PARAMETER, SETUP, TEARDOWN, PLAY\r\n\r\n",
 			err: true,
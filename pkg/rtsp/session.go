@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
+	"net/http"
 	"sync"
 )
 
@@ -17,6 +19,8 @@ type Session struct {
 
 	// channel for requests
 	reqCh chan *request
+	// channel for outgoing responses and interleaved packets
+	writeCh chan *write
 	// channel for receiving items such as packets, requests, etc
 	recvCh chan interface{}
 	// receiving items from connection
@@ -25,15 +29,27 @@ type Session struct {
 	seq  uint64
 	creq map[uint64]*request
 	wg   sync.WaitGroup
+
+	auth authenticator
+
+	// UDP sockets by RTP channel
+	udpMu sync.Mutex
+	udp   map[uint8]*udpPair
+
+	// error which has terminated the session
+	errMu sync.Mutex
+	err   error
 }
 
 // NewSession creates new session
 func NewSession(conn net.Conn, ctx context.Context) *Session {
 	s := &Session{
-		reqCh:  make(chan *request),
-		recvCh: make(chan interface{}, incomingItemsCapacity),
-		readCh: make(chan interface{}, incomingItemsCapacity),
-		creq:   map[uint64]*request{},
+		reqCh:   make(chan *request),
+		writeCh: make(chan *write),
+		recvCh:  make(chan interface{}, incomingItemsCapacity),
+		readCh:  make(chan interface{}, incomingItemsCapacity),
+		creq:    map[uint64]*request{},
+		udp:     map[uint8]*udpPair{},
 	}
 
 	s.ctx, s.cancel = context.WithCancel(ctx)
@@ -53,15 +69,37 @@ func NewSession(conn net.Conn, ctx context.Context) *Session {
 	return s
 }
 
-// Do performs request and wait response
+// Do performs request and wait response. If request URL contains user info credentials will be used to
+// pass Basic or Digest authentication. Credentials are remembered for further requests
 func (s *Session) Do(r *Request) (*Response, error) {
+	s.auth.authorize(r)
+
+	resp, err := s.do(r)
+	if err != nil || resp.StatusCode != Unauthorized {
+		return resp, err
+	}
+
+	if !s.auth.update(r, resp) {
+		return resp, nil
+	}
+
+	s.auth.authorize(r)
+	return s.do(r)
+}
+
+func (s *Session) do(r *Request) (*Response, error) {
 	in := &request{
 		req:  r,
-		resp: make(chan interface{}),
+		resp: make(chan interface{}, 1),
 	}
-	s.reqCh <- in
 
-	out, _ := <-in.resp
+	select {
+	case s.reqCh <- in:
+	case <-s.ctx.Done():
+		return nil, ErrSessionClosed
+	}
+
+	out := <-in.resp
 	switch t := out.(type) {
 	case *Response:
 		return t, nil
@@ -72,6 +110,46 @@ func (s *Session) Do(r *Request) (*Response, error) {
 	}
 }
 
+// WriteResponse sends response to the remote side
+func (s *Session) WriteResponse(r *Response) error {
+	return s.write(r)
+}
+
+// WritePacket sends RTP or RTCP packet interleaved with RTSP messages to specified channel.
+// If the channel has been bound by ListenUDP, the packet is sent over UDP
+func (s *Session) WritePacket(channel uint8, packet []byte) error {
+	if ok, err := s.writeUDP(channel, packet); ok {
+		return err
+	}
+
+	if len(packet) > math.MaxUint16 {
+		return fmt.Errorf("packet too large: %d", len(packet))
+	}
+
+	return s.write(&interleavedPacket{
+		InterleavedHeader: InterleavedHeader{
+			Channel: channel,
+			Length:  uint16(len(packet)),
+		},
+		data: packet,
+	})
+}
+
+func (s *Session) write(data writable) error {
+	w := &write{
+		data:   data,
+		result: make(chan error, 1),
+	}
+
+	select {
+	case s.writeCh <- w:
+	case <-s.ctx.Done():
+		return ErrSessionClosed
+	}
+
+	return <-w.result
+}
+
 // Incoming gets channel which can forward any of item:
 // 1) *IncomingRTP - incoming RTP packet
 // 2) *IncomingRTCP - incoming RTCP packet
@@ -81,6 +159,15 @@ func (s *Session) Incoming() <-chan interface{} {
 	return s.recvCh
 }
 
+// Err returns error which has terminated the session, nil while the session is alive. The error is
+// available after the channel of incoming items is closed even if it hasn't been passed through the channel
+func (s *Session) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return s.err
+}
+
 func (s *Session) Close() {
 	s.cancel()
 	s.wg.Wait()
@@ -94,17 +181,29 @@ func (s *Session) eventsProcess(conn net.Conn) {
 			err = s.processIncoming(data)
 		case req := <-s.reqCh:
 			err = s.sendRequest(conn, req)
+		case w := <-s.writeCh:
+			err = w.data.Write(conn)
+			w.result <- err
 		case <-s.ctx.Done():
 			err = s.ctx.Err()
 		}
 	}
 
-	s.recvCh <- err
+	s.cancel()
+
+	s.errMu.Lock()
+	s.err = err
+	s.errMu.Unlock()
+
+	// the error is dropped if the consumer lags behind, but it is kept for Err
+	select {
+	case s.recvCh <- err:
+	default:
+	}
 
 	_ = conn.Close()
-	close(s.reqCh)
+	s.closeUDP()
 	close(s.recvCh)
-	close(s.readCh)
 	for _, r := range s.creq {
 		r.resp <- err
 		close(r.resp)
@@ -115,15 +214,26 @@ func (s *Session) sendRequest(conn net.Conn, req *request) error {
 	// set sequence number
 	s.seq++
 	req.seq = s.seq
-	req.req.Header.Add("Cseq", fmt.Sprintf("%d", s.seq))
-
-	// serialize and send request
-	if err := req.req.Write(conn); err != nil {
-		return err
+	if req.req.Header == nil {
+		req.req.Header = make(http.Header)
 	}
+	req.req.Header.Set("Cseq", fmt.Sprintf("%d", s.seq))
 
+	// the request will get an error if the session fails
 	s.creq[req.seq] = req
-	return nil
+
+	// serialize and send request
+	return req.req.Write(conn)
+}
+
+// push forwards item from the reading goroutine until the session is alive
+func (s *Session) push(item interface{}) bool {
+	select {
+	case s.readCh <- item:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
 }
 
 // reads all incoming messages
@@ -132,52 +242,60 @@ func (s *Session) parseProcess(conn net.Conn) {
 	for {
 		b, err := r.Peek(4)
 		if err != nil {
-			s.readCh <- fmt.Errorf("receive RTSP data failed: %w", err)
+			s.push(fmt.Errorf("receive RTSP data failed: %w", err))
 			return
 		}
 		switch {
 		case b[0] == MagicSymbol: // parse interleaved packet
 			h := InterleavedHeader{}
 			if err = h.Read(r); err != nil {
-				s.readCh <- fmt.Errorf("read interleaved header failed: %w", err)
+				s.push(fmt.Errorf("read interleaved header failed: %w", err))
 				return
 			}
 			// todo: mempool
 			buf := make([]byte, h.Length)
 			if _, err = io.ReadFull(r, buf); err != nil {
-				s.readCh <- fmt.Errorf("read packet failed: %w", err)
+				s.push(fmt.Errorf("read packet failed: %w", err))
 				return
 			}
+			var item interface{}
 			if h.Channel%2 == 0 {
-				s.readCh <- &IncomingRTP{
+				item = &IncomingRTP{
 					Channel: h.Channel,
 					Packet:  buf,
 				}
 			} else {
-				s.readCh <- &IncomingRTCP{
+				item = &IncomingRTCP{
 					Channel: h.Channel,
 					Packet:  buf,
 				}
 			}
+			if !s.push(item) {
+				return
+			}
 
 		case b[0] == 'R' && b[1] == 'T' && b[2] == 'S' && b[3] == 'P': // parse response
 			var resp Response
 			if err = resp.Read(r); err != nil {
-				s.readCh <- fmt.Errorf("read RTSP response failed: %w", err)
+				s.push(fmt.Errorf("read RTSP response failed: %w", err))
+				return
+			}
+			if !s.push(&resp) {
 				return
 			}
-			s.readCh <- &resp
 
 		case b[0] >= 'A' && b[0] <= 'Z': // parse request
 			var req Request
 			if err = req.Read(r); err != nil {
-				s.readCh <- fmt.Errorf("read RTSP request failed: %w", err)
+				s.push(fmt.Errorf("read RTSP request failed: %w", err))
+				return
+			}
+			if !s.push(&req) {
 				return
 			}
-			s.readCh <- &req
 
 		default:
-			s.readCh <- errors.New("parse RTSP stream failed")
+			s.push(errors.New("parse RTSP stream failed"))
 			return
 		}
 	}
@@ -198,12 +316,12 @@ func (s *Session) processIncoming(data interface{}) error {
 
 		req.resp <- t
 		delete(s.creq, seq)
-	case *IncomingRTP:
-		s.recvCh <- t
-	case *IncomingRTCP:
-		s.recvCh <- t
-	case *Request:
-		s.recvCh <- t
+	case *IncomingRTP, *IncomingRTCP, *Request:
+		select {
+		case s.recvCh <- t:
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
 	case error:
 		return t
 	}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"github.com/racoon-devel/gortsp/internal/mocks"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestSession_Do(t *testing.T) {
@@ -44,3 +47,61 @@ func TestSession_Do(t *testing.T) {
 	assert.Equal(t, expected, resp)
 
 }
+
+func TestSession_WritePacket(t *testing.T) {
+	mock := mocks.NewConnMock()
+	defer mock.Close()
+
+	s := NewSession(mock.Client(), context.Background())
+	defer s.Close()
+
+	mock.ExpectWrite(t, []byte{MagicSymbol, 0x01, 0x00, 0x03, 0x0a, 0x0b, 0x0c})
+	assert.NoError(t, s.WritePacket(1, []byte{0x0a, 0x0b, 0x0c}))
+}
+
+func TestSession_WriteResponse(t *testing.T) {
+	mock := mocks.NewConnMock()
+	defer mock.Close()
+
+	s := NewSession(mock.Client(), context.Background())
+	defer s.Close()
+
+	resp := Response{
+		Status:     "OK",
+		StatusCode: Ok,
+		Header: http.Header{
+			"Cseq": {"1"},
+		},
+	}
+
+	mock.ExpectWrite(t, []byte("RTSP/1.0 200 OK\r\nCseq: 1\r\n\r\n"))
+	assert.NoError(t, s.WriteResponse(&resp))
+}
+
+func TestSession_Err(t *testing.T) {
+	client, server := net.Pipe()
+
+	s := NewSession(client, context.Background())
+	defer s.Close()
+	assert.NoError(t, s.Err())
+
+	// incoming items fill the channel, so the error can't be passed through it
+	for i := 0; i < incomingItemsCapacity; i++ {
+		_, err := server.Write([]byte{MagicSymbol, 0x00, 0x00, 0x01, 0x0a})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, server.Close())
+
+	assert.Eventually(t, func() bool {
+		return s.Err() != nil
+	}, time.Second, time.Millisecond)
+	assert.ErrorIs(t, s.Err(), io.EOF)
+
+	count := 0
+	for item := range s.Incoming() {
+		_, ok := item.(*IncomingRTP)
+		assert.True(t, ok)
+		count++
+	}
+	assert.Equal(t, incomingItemsCapacity, count)
+}
@@ -1,5 +1,10 @@
 package rtsp
 
+import (
+	"encoding/binary"
+	"io"
+)
+
 const (
 	incomingItemsCapacity = 100
 )
@@ -9,3 +14,28 @@ type request struct {
 	resp chan interface{}
 	seq  uint64
 }
+
+type writable interface {
+	Write(w io.Writer) error
+}
+
+type write struct {
+	data   writable
+	result chan error
+}
+
+type interleavedPacket struct {
+	InterleavedHeader
+	data []byte
+}
+
+func (p interleavedPacket) Write(w io.Writer) error {
+	buf := make([]byte, InterleavedHeaderSize+len(p.data))
+	buf[0] = MagicSymbol
+	buf[1] = p.Channel
+	binary.BigEndian.PutUint16(buf[2:InterleavedHeaderSize], p.Length)
+	copy(buf[InterleavedHeaderSize:], p.data)
+
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,345 @@
+package rtsp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Delivery represents unicast or multicast parameter of Transport header
+type Delivery string
+
+const (
+	Unicast   Delivery = "unicast"
+	Multicast Delivery = "multicast"
+)
+
+// Lower transport protocols
+const (
+	TransportUDP = "UDP"
+	TransportTCP = "TCP"
+)
+
+// PortRange represents port or channel pair, e.g. client_port=5000-5001 or interleaved=0-1.
+// Single value is represented by equal From and To
+type PortRange struct {
+	From uint16
+	To   uint16
+}
+
+func (r PortRange) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(int(r.From))
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// ParsePortRange parses single value or value pair
+func ParsePortRange(s string) (PortRange, error) {
+	values := strings.SplitN(s, "-", 2)
+	from, err := strconv.ParseUint(values[0], 10, 16)
+	if err != nil {
+		return PortRange{}, err
+	}
+
+	r := PortRange{From: uint16(from), To: uint16(from)}
+	if len(values) == 2 {
+		to, err := strconv.ParseUint(values[1], 10, 16)
+		if err != nil {
+			return PortRange{}, err
+		}
+		r.To = uint16(to)
+	}
+
+	return r, nil
+}
+
+// Transport represents one alternative of Transport header (RFC 2326 section 12.39, RFC 7826 section 18.54):
+// RTP/AVP/TCP;unicast;interleaved=0-1
+type Transport struct {
+	// Protocol is a transport protocol, e.g. RTP
+	Protocol string
+	// Profile is a protocol profile, e.g. AVP
+	Profile string
+	// LowerTransport is TCP or UDP. Empty value means UDP
+	LowerTransport string
+
+	// Delivery is unicast or multicast, it is not presented if empty
+	Delivery Delivery
+
+	Destination string
+	Source      string
+	Layers      int
+	TTL         int
+	Append      bool
+	// Mode is a list of methods to be supported, e.g. PLAY or RECORD
+	Mode string
+
+	Interleaved *PortRange
+	Port        *PortRange
+	ClientPort  *PortRange
+	ServerPort  *PortRange
+	SSRC        []uint32
+
+	// RFC 7826 parameters
+	DestinationAddresses []string
+	SourceAddresses      []string
+	RTCPMux              bool
+	Setup                string
+	Connection           string
+
+	// Extensions contains unknown parameters as is
+	Extensions []string
+}
+
+// IsTCP returns true if interleaved mode is used
+func (t Transport) IsTCP() bool {
+	return t.LowerTransport == TransportTCP
+}
+
+// IsMulticast returns true if multicast delivery is requested
+func (t Transport) IsMulticast() bool {
+	return t.Delivery == Multicast
+}
+
+func (t Transport) String() string {
+	spec := t.Protocol + "/" + t.Profile
+	if t.LowerTransport != "" {
+		spec += "/" + t.LowerTransport
+	}
+
+	params := []string{spec}
+	add := func(key string, value string) {
+		params = append(params, key+"="+value)
+	}
+	addRange := func(key string, r *PortRange) {
+		if r != nil {
+			add(key, r.String())
+		}
+	}
+
+	if t.Delivery != "" {
+		params = append(params, string(t.Delivery))
+	}
+	if t.Destination != "" {
+		add("destination", t.Destination)
+	}
+	if t.Source != "" {
+		add("source", t.Source)
+	}
+	if t.Layers != 0 {
+		add("layers", strconv.Itoa(t.Layers))
+	}
+	if t.Append {
+		params = append(params, "append")
+	}
+	if t.TTL != 0 {
+		add("ttl", strconv.Itoa(t.TTL))
+	}
+	addRange("interleaved", t.Interleaved)
+	addRange("port", t.Port)
+	addRange("client_port", t.ClientPort)
+	addRange("server_port", t.ServerPort)
+	if len(t.SSRC) != 0 {
+		ssrc := make([]string, len(t.SSRC))
+		for i, v := range t.SSRC {
+			ssrc[i] = fmt.Sprintf("%08X", v)
+		}
+		add("ssrc", strings.Join(ssrc, "/"))
+	}
+	if len(t.DestinationAddresses) != 0 {
+		add("dest_addr", strings.Join(quoteAll(t.DestinationAddresses), "/"))
+	}
+	if len(t.SourceAddresses) != 0 {
+		add("src_addr", strings.Join(quoteAll(t.SourceAddresses), "/"))
+	}
+	if t.RTCPMux {
+		params = append(params, "RTCP-mux")
+	}
+	if t.Setup != "" {
+		add("setup", t.Setup)
+	}
+	if t.Connection != "" {
+		add("connection", t.Connection)
+	}
+	if t.Mode != "" {
+		add("mode", t.Mode)
+	}
+	params = append(params, t.Extensions...)
+
+	return strings.Join(params, ";")
+}
+
+// Parse parses single transport alternative
+func (t *Transport) Parse(s string) error {
+	*t = Transport{}
+
+	params := strings.Split(strings.TrimSpace(s), ";")
+	spec := strings.Split(params[0], "/")
+	if len(spec) < 2 || len(spec) > 3 || spec[0] == "" || spec[1] == "" {
+		return fmt.Errorf("%w: transport spec: %q", ErrInvalidTransport, params[0])
+	}
+
+	t.Protocol = spec[0]
+	t.Profile = spec[1]
+	if len(spec) == 3 {
+		t.LowerTransport = strings.ToUpper(spec[2])
+	}
+
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+
+		kv := strings.SplitN(param, "=", 2)
+		key := strings.ToLower(kv[0])
+		value := ""
+		if len(kv) == 2 {
+			value = strings.Trim(kv[1], "\"")
+		}
+
+		if err := t.parseParam(key, value); err != nil {
+			return fmt.Errorf("%w: parameter %q: %s", ErrInvalidTransport, param, err)
+		}
+		if isUnknownTransportParam(key) {
+			t.Extensions = append(t.Extensions, param)
+		}
+	}
+
+	return nil
+}
+
+func isUnknownTransportParam(key string) bool {
+	switch key {
+	case "unicast", "multicast", "destination", "source", "layers", "append", "ttl", "interleaved", "port",
+		"client_port", "server_port", "ssrc", "dest_addr", "src_addr", "rtcp-mux", "setup", "connection", "mode":
+		return false
+	}
+	return true
+}
+
+func (t *Transport) parseParam(key string, value string) (err error) {
+	parseRange := func() (*PortRange, error) {
+		r, err := ParsePortRange(value)
+		if err != nil {
+			return nil, err
+		}
+		return &r, nil
+	}
+
+	switch key {
+	case "unicast":
+		t.Delivery = Unicast
+	case "multicast":
+		t.Delivery = Multicast
+	case "destination":
+		t.Destination = value
+	case "source":
+		t.Source = value
+	case "layers":
+		t.Layers, err = strconv.Atoi(value)
+	case "append":
+		t.Append = true
+	case "ttl":
+		t.TTL, err = strconv.Atoi(value)
+	case "interleaved":
+		t.Interleaved, err = parseRange()
+	case "port":
+		t.Port, err = parseRange()
+	case "client_port":
+		t.ClientPort, err = parseRange()
+	case "server_port":
+		t.ServerPort, err = parseRange()
+	case "ssrc":
+		for _, v := range strings.Split(value, "/") {
+			var ssrc uint64
+			if ssrc, err = strconv.ParseUint(strings.TrimSpace(v), 16, 32); err != nil {
+				return
+			}
+			t.SSRC = append(t.SSRC, uint32(ssrc))
+		}
+	case "dest_addr":
+		t.DestinationAddresses = unquoteAll(strings.Split(value, "/"))
+	case "src_addr":
+		t.SourceAddresses = unquoteAll(strings.Split(value, "/"))
+	case "rtcp-mux":
+		t.RTCPMux = true
+	case "setup":
+		t.Setup = value
+	case "connection":
+		t.Connection = value
+	case "mode":
+		t.Mode = value
+	}
+
+	return
+}
+
+// Transports represents Transport header with list of alternatives in preference order
+type Transports []Transport
+
+// ParseTransports parses Transport header value
+func ParseTransports(header string) (Transports, error) {
+	var transports Transports
+	for _, alternative := range splitQuoted(header, ',') {
+		if strings.TrimSpace(alternative) == "" {
+			continue
+		}
+
+		var t Transport
+		if err := t.Parse(alternative); err != nil {
+			return nil, err
+		}
+		transports = append(transports, t)
+	}
+
+	if len(transports) == 0 {
+		return nil, fmt.Errorf("%w: empty header", ErrInvalidTransport)
+	}
+
+	return transports, nil
+}
+
+func (t Transports) String() string {
+	alternatives := make([]string, len(t))
+	for i, transport := range t {
+		alternatives[i] = transport.String()
+	}
+	return strings.Join(alternatives, ",")
+}
+
+// splitQuoted splits string by separator which is not enclosed in quotes
+func splitQuoted(s string, sep byte) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return quoted
+}
+
+func unquoteAll(values []string) []string {
+	unquoted := make([]string, len(values))
+	for i, v := range values {
+		unquoted[i] = strings.Trim(v, "\"")
+	}
+	return unquoted
+}
@@ -0,0 +1,122 @@
+package rtsp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTransport_Parse(t *testing.T) {
+	type testCase struct {
+		raw       string
+		transport Transport
+		composed  string
+		err       bool
+	}
+
+	testCases := []testCase{
+		{
+			raw: "RTP/AVP/TCP;unicast;interleaved=0-1",
+			transport: Transport{
+				Protocol:       "RTP",
+				Profile:        "AVP",
+				LowerTransport: TransportTCP,
+				Delivery:       Unicast,
+				Interleaved:    &PortRange{From: 0, To: 1},
+			},
+		},
+		{
+			raw: "RTP/AVP;unicast;client_port=5000-5001;server_port=6256-6257;ssrc=2A3F0C1D;mode=record",
+			transport: Transport{
+				Protocol:   "RTP",
+				Profile:    "AVP",
+				Delivery:   Unicast,
+				ClientPort: &PortRange{From: 5000, To: 5001},
+				ServerPort: &PortRange{From: 6256, To: 6257},
+				SSRC:       []uint32{0x2A3F0C1D},
+				Mode:       "record",
+			},
+		},
+		{
+			raw: "RTP/AVP;multicast;destination=224.2.0.1;port=3456-3457;ttl=16",
+			transport: Transport{
+				Protocol:    "RTP",
+				Profile:     "AVP",
+				Delivery:    Multicast,
+				Destination: "224.2.0.1",
+				Port:        &PortRange{From: 3456, To: 3457},
+				TTL:         16,
+			},
+			composed: "RTP/AVP;multicast;destination=224.2.0.1;ttl=16;port=3456-3457",
+		},
+		{
+			raw: "RTP/AVP/UDP;unicast;source=192.168.1.64;mode=\"PLAY\";x-custom=1",
+			transport: Transport{
+				Protocol:       "RTP",
+				Profile:        "AVP",
+				LowerTransport: TransportUDP,
+				Delivery:       Unicast,
+				Source:         "192.168.1.64",
+				Mode:           "PLAY",
+				Extensions:     []string{"x-custom=1"},
+			},
+			composed: "RTP/AVP/UDP;unicast;source=192.168.1.64;mode=PLAY;x-custom=1",
+		},
+		{
+			raw: "RTP/AVPF/UDP;unicast;dest_addr=\"192.0.2.5:3456\"/\"192.0.2.5:3457\";RTCP-mux;ssrc=93CB001E/93CB001F",
+			transport: Transport{
+				Protocol:             "RTP",
+				Profile:              "AVPF",
+				LowerTransport:       TransportUDP,
+				Delivery:             Unicast,
+				DestinationAddresses: []string{"192.0.2.5:3456", "192.0.2.5:3457"},
+				RTCPMux:              true,
+				SSRC:                 []uint32{0x93CB001E, 0x93CB001F},
+			},
+			composed: "RTP/AVPF/UDP;unicast;ssrc=93CB001E/93CB001F;dest_addr=\"192.0.2.5:3456\"/\"192.0.2.5:3457\";RTCP-mux",
+		},
+		{
+			raw: "RTP",
+			err: true,
+		},
+		{
+			raw: "RTP/AVP;client_port=abc",
+			err: true,
+		},
+		{
+			raw: "RTP/AVP;ssrc=XYZ",
+			err: true,
+		},
+	}
+
+	for i, c := range testCases {
+		var tr Transport
+		err := tr.Parse(c.raw)
+		if c.err {
+			assert.ErrorIs(t, err, ErrInvalidTransport, "testCase : %d", i+1)
+			continue
+		}
+
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.transport, tr, "testCase : %d", i+1)
+
+		composed := c.composed
+		if composed == "" {
+			composed = c.raw
+		}
+		assert.Equal(t, composed, tr.String(), "testCase : %d", i+1)
+	}
+}
+
+func TestParseTransports(t *testing.T) {
+	header := "RTP/AVP/TCP;unicast;interleaved=0-1,RTP/AVP;unicast;client_port=5000-5001"
+	transports, err := ParseTransports(header)
+	assert.NoError(t, err)
+	assert.Len(t, transports, 2)
+	assert.True(t, transports[0].IsTCP())
+	assert.False(t, transports[1].IsTCP())
+	assert.Equal(t, &PortRange{From: 5000, To: 5001}, transports[1].ClientPort)
+	assert.Equal(t, header, transports.String())
+
+	_, err = ParseTransports("")
+	assert.ErrorIs(t, err, ErrInvalidTransport)
+}
@@ -0,0 +1,208 @@
+package rtsp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const (
+	// maximum size of UDP datagram
+	maxDatagramSize = 65535
+	// attempts to find free even/odd port pair
+	udpListenAttempts = 100
+)
+
+// udpPair is a pair of sockets which are used for RTP and RTCP delivery of single media
+type udpPair struct {
+	rtp  *net.UDPConn
+	rtcp *net.UDPConn
+
+	// remote addresses, nil until destination is set
+	rtpAddr  *net.UDPAddr
+	rtcpAddr *net.UDPAddr
+
+	// datagrams from other hosts are dropped if it is set
+	source net.IP
+}
+
+func (p *udpPair) close() {
+	_ = p.rtp.Close()
+	_ = p.rtcp.Close()
+}
+
+// ListenUDP opens even/odd UDP port pair for RTP and RTCP. Received packets are forwarded to Incoming()
+// as IncomingRTP and IncomingRTCP like interleaved ones: RTP packets with specified channel, RTCP packets
+// with channel + 1. Returned port range should be advertised in client_port parameter of Transport header
+func (s *Session) ListenUDP(channel uint8) (PortRange, error) {
+	rtpConn, rtcpConn, err := listenUDPPair()
+	if err != nil {
+		return PortRange{}, err
+	}
+
+	pair := &udpPair{rtp: rtpConn, rtcp: rtcpConn}
+	if err = s.addUDP(channel, pair); err != nil {
+		pair.close()
+		return PortRange{}, err
+	}
+
+	s.startUDP(channel, pair)
+
+	return PortRange{
+		From: uint16(rtpConn.LocalAddr().(*net.UDPAddr).Port),
+		To:   uint16(rtcpConn.LocalAddr().(*net.UDPAddr).Port),
+	}, nil
+}
+
+// ConnectUDP sets remote RTP/RTCP port pair of the media opened by ListenUDP. After that WritePacket sends
+// packets of the channel to the remote side over UDP and packets from other hosts are dropped
+func (s *Session) ConnectUDP(channel uint8, host string, ports PortRange) error {
+	rtpAddr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(int(ports.From))))
+	if err != nil {
+		return err
+	}
+	rtcpAddr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(int(ports.To))))
+	if err != nil {
+		return err
+	}
+
+	s.udpMu.Lock()
+	defer s.udpMu.Unlock()
+
+	pair, ok := s.udp[channel&^1]
+	if !ok {
+		return fmt.Errorf("UDP is not listened on channel %d", channel)
+	}
+	pair.rtpAddr = rtpAddr
+	pair.rtcpAddr = rtcpAddr
+	pair.source = rtpAddr.IP
+
+	return nil
+}
+
+func (s *Session) addUDP(channel uint8, pair *udpPair) error {
+	if channel%2 != 0 {
+		return fmt.Errorf("RTP channel must be even: %d", channel)
+	}
+
+	s.udpMu.Lock()
+	defer s.udpMu.Unlock()
+
+	select {
+	case <-s.ctx.Done():
+		return ErrSessionClosed
+	default:
+	}
+
+	if _, ok := s.udp[channel]; ok {
+		return fmt.Errorf("channel %d is already in use", channel)
+	}
+	s.udp[channel] = pair
+
+	// readers are counted under the lock, so Close can't start waiting for them before they're added
+	s.wg.Add(2)
+
+	return nil
+}
+
+// startUDP runs readers of the pair added by addUDP
+func (s *Session) startUDP(channel uint8, pair *udpPair) {
+	go func() {
+		defer s.wg.Done()
+		s.readUDP(pair.rtp, pair, func(data []byte) interface{} {
+			return &IncomingRTP{Channel: channel, Packet: data}
+		})
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.readUDP(pair.rtcp, pair, func(data []byte) interface{} {
+			return &IncomingRTCP{Channel: channel + 1, Packet: data}
+		})
+	}()
+}
+
+// readUDP reads datagrams until the socket is closed
+func (s *Session) readUDP(conn *net.UDPConn, pair *udpPair, item func(data []byte) interface{}) {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		if !s.acceptUDP(pair, addr) {
+			continue
+		}
+
+		// todo: mempool
+		data := make([]byte, n)
+		copy(data, buf[:n])
+		if !s.push(item(data)) {
+			return
+		}
+	}
+}
+
+// acceptUDP filters datagrams by remote host
+func (s *Session) acceptUDP(pair *udpPair, addr *net.UDPAddr) bool {
+	s.udpMu.Lock()
+	defer s.udpMu.Unlock()
+
+	return pair.source == nil || pair.source.Equal(addr.IP)
+}
+
+// writeUDP sends packet over UDP if the channel is bound to UDP
+func (s *Session) writeUDP(channel uint8, packet []byte) (bool, error) {
+	s.udpMu.Lock()
+	pair, ok := s.udp[channel&^1]
+	if !ok {
+		s.udpMu.Unlock()
+		return false, nil
+	}
+	conn, addr := pair.rtp, pair.rtpAddr
+	if channel%2 != 0 {
+		conn, addr = pair.rtcp, pair.rtcpAddr
+	}
+	s.udpMu.Unlock()
+
+	if addr == nil {
+		return true, fmt.Errorf("destination of channel %d is unknown", channel)
+	}
+
+	_, err := conn.WriteToUDP(packet, addr)
+	return true, err
+}
+
+func (s *Session) closeUDP() {
+	s.udpMu.Lock()
+	defer s.udpMu.Unlock()
+
+	for _, pair := range s.udp {
+		pair.close()
+	}
+}
+
+func listenUDPPair() (*net.UDPConn, *net.UDPConn, error) {
+	for i := 0; i < udpListenAttempts; i++ {
+		rtpConn, err := net.ListenUDP("udp", &net.UDPAddr{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		port := rtpConn.LocalAddr().(*net.UDPAddr).Port
+		if port%2 != 0 {
+			_ = rtpConn.Close()
+			continue
+		}
+
+		rtcpConn, err := net.ListenUDP("udp", &net.UDPAddr{Port: port + 1})
+		if err != nil {
+			_ = rtpConn.Close()
+			continue
+		}
+
+		return rtpConn, rtcpConn, nil
+	}
+
+	return nil, nil, fmt.Errorf("cannot allocate UDP port pair")
+}
@@ -0,0 +1,100 @@
+package rtsp
+
+import (
+	"context"
+	"github.com/racoon-devel/gortsp/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSession_ListenUDP(t *testing.T) {
+	mock := mocks.NewConnMock()
+	defer mock.Close()
+
+	s := NewSession(mock.Client(), context.Background())
+	defer s.Close()
+
+	ports, err := s.ListenUDP(2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), ports.From%2)
+	assert.Equal(t, ports.From+1, ports.To)
+
+	_, err = s.ListenUDP(2)
+	assert.Error(t, err)
+	_, err = s.ListenUDP(3)
+	assert.Error(t, err)
+
+	remote, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+	defer remote.Close()
+
+	// receiving
+	_, err = remote.WriteToUDP([]byte{0x80, 0x60}, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: int(ports.From)})
+	assert.NoError(t, err)
+	assert.Equal(t, &IncomingRTP{Channel: 2, Packet: []byte{0x80, 0x60}}, waitIncoming(t, s))
+
+	_, err = remote.WriteToUDP([]byte{0x81, 0xc9}, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: int(ports.To)})
+	assert.NoError(t, err)
+	assert.Equal(t, &IncomingRTCP{Channel: 3, Packet: []byte{0x81, 0xc9}}, waitIncoming(t, s))
+
+	// sending
+	assert.Error(t, s.WritePacket(3, []byte{0x81, 0xc9}))
+
+	remotePort := uint16(remote.LocalAddr().(*net.UDPAddr).Port)
+	assert.NoError(t, s.ConnectUDP(2, "127.0.0.1", PortRange{From: remotePort, To: remotePort}))
+	assert.NoError(t, s.WritePacket(3, []byte{0x81, 0xc9}))
+
+	buf := make([]byte, maxDatagramSize)
+	_ = remote.SetReadDeadline(time.Now().Add(time.Second))
+	n, addr, err := remote.ReadFromUDP(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x81, 0xc9}, buf[:n])
+	assert.Equal(t, int(ports.To), addr.Port)
+}
+
+func TestSession_ConnectUDPWhileWriting(t *testing.T) {
+	mock := mocks.NewConnMock()
+	defer mock.Close()
+
+	s := NewSession(mock.Client(), context.Background())
+	defer s.Close()
+
+	_, err := s.ListenUDP(0)
+	assert.NoError(t, err)
+
+	remote, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+	defer remote.Close()
+	remotePort := uint16(remote.LocalAddr().(*net.UDPAddr).Port)
+
+	// packets are rejected until the destination is set
+	writing := make(chan struct{})
+	connected := make(chan struct{})
+	go func() {
+		defer close(connected)
+		assert.Error(t, s.WritePacket(1, []byte{0x81, 0xc9}))
+		close(writing)
+		for s.WritePacket(1, []byte{0x81, 0xc9}) != nil {
+		}
+	}()
+
+	<-writing
+	assert.NoError(t, s.ConnectUDP(0, "127.0.0.1", PortRange{From: remotePort, To: remotePort}))
+	select {
+	case <-connected:
+	case <-time.After(time.Second):
+		assert.Fail(t, "packet is not sent after connect")
+	}
+}
+
+func waitIncoming(t *testing.T, s *Session) interface{} {
+	select {
+	case item := <-s.Incoming():
+		return item
+	case <-time.After(time.Second):
+		assert.Fail(t, "incoming item is not received")
+		return nil
+	}
+}
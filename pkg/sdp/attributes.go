@@ -0,0 +1,225 @@
+package sdp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Well-known attributes keys
+const (
+	AttrControl   = "control"
+	AttrRTPMap    = "rtpmap"
+	AttrFMTP      = "fmtp"
+	AttrRange     = "range"
+	AttrFramerate = "framerate"
+)
+
+// RTPMap represents a=rtpmap attribute: <payload type> <encoding name>/<clock rate>[/<channels>]
+type RTPMap struct {
+	PayloadType  uint8
+	EncodingName string
+	ClockRate    int
+	// Channels is a count of audio channels, 0 means it is not presented
+	Channels int
+}
+
+func (r RTPMap) String() string {
+	s := fmt.Sprintf("%d %s/%d", r.PayloadType, r.EncodingName, r.ClockRate)
+	if r.Channels != 0 {
+		s += "/" + strconv.Itoa(r.Channels)
+	}
+	return s
+}
+
+// Parse parses a=rtpmap attribute value
+func (r *RTPMap) Parse(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return fmt.Errorf("malformed rtpmap: %q", value)
+	}
+
+	pt, err := strconv.ParseUint(fields[0], 10, 7)
+	if err != nil {
+		return fmt.Errorf("malformed rtpmap payload type: %w", err)
+	}
+
+	parts := strings.Split(fields[1], "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("malformed rtpmap encoding: %q", fields[1])
+	}
+
+	*r = RTPMap{
+		PayloadType:  uint8(pt),
+		EncodingName: parts[0],
+	}
+
+	if r.ClockRate, err = strconv.Atoi(parts[1]); err != nil {
+		return fmt.Errorf("malformed rtpmap clock rate: %w", err)
+	}
+
+	if len(parts) == 3 {
+		if r.Channels, err = strconv.Atoi(parts[2]); err != nil {
+			return fmt.Errorf("malformed rtpmap channels: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FMTP represents format specific parameters of a=fmtp attribute: key1=value1;key2=value2.
+// Keys are stored in lower case, because they are case-insensitive
+type FMTP map[string]string
+
+// ParseFMTP parses parameters part of a=fmtp attribute value
+func ParseFMTP(params string) FMTP {
+	f := FMTP{}
+	for _, param := range strings.Split(params, ";") {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+		kv := strings.SplitN(param, "=", 2)
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		if len(kv) == 2 {
+			f[key] = strings.TrimSpace(kv[1])
+		} else {
+			f[key] = ""
+		}
+	}
+	return f
+}
+
+// Get returns parameter value by case-insensitive key
+func (f FMTP) Get(key string) (string, bool) {
+	v, ok := f[strings.ToLower(key)]
+	return v, ok
+}
+
+// Int returns integer parameter value
+func (f FMTP) Int(key string) (int, bool) {
+	v, ok := f.Get(key)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(v)
+	return i, err == nil
+}
+
+// String composes parameters in stable order
+func (f FMTP) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(f))
+	for _, k := range keys {
+		if f[k] == "" {
+			params = append(params, k)
+		} else {
+			params = append(params, k+"="+f[k])
+		}
+	}
+	return strings.Join(params, ";")
+}
+
+// Direction represents media direction attribute
+type Direction string
+
+const (
+	SendRecv Direction = "sendrecv"
+	SendOnly Direction = "sendonly"
+	RecvOnly Direction = "recvonly"
+	Inactive Direction = "inactive"
+)
+
+func directionOf(attrs Attributes) (Direction, bool) {
+	for _, a := range attrs {
+		switch d := Direction(a.Key); d {
+		case SendRecv, SendOnly, RecvOnly, Inactive:
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// Range represents a=range attribute in NPT format (RFC 2326): npt=0-34.5
+type Range struct {
+	Start time.Duration
+	// End is zero for open ranges, e.g. npt=0-
+	End time.Duration
+}
+
+func (r Range) String() string {
+	s := "npt=" + formatNPT(r.Start) + "-"
+	if r.End != 0 {
+		s += formatNPT(r.End)
+	}
+	return s
+}
+
+// Parse parses a=range attribute value
+func (r *Range) Parse(value string) error {
+	if !strings.HasPrefix(value, "npt=") {
+		return fmt.Errorf("unsupported range: %q", value)
+	}
+
+	bounds := strings.SplitN(value[len("npt="):], "-", 2)
+	if len(bounds) != 2 {
+		return fmt.Errorf("malformed range: %q", value)
+	}
+
+	var err error
+	if r.Start, err = parseNPT(bounds[0]); err != nil {
+		return err
+	}
+	if r.End, err = parseNPT(bounds[1]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func rangeOf(attrs Attributes) (Range, bool) {
+	value, ok := attrs.Get(AttrRange)
+	if !ok {
+		return Range{}, false
+	}
+
+	var r Range
+	if err := r.Parse(value); err != nil {
+		return Range{}, false
+	}
+	return r, true
+}
+
+// parseNPT parses NPT time: seconds (34.5), hh:mm:ss[.fraction] or "now"
+func parseNPT(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "now" {
+		return 0, nil
+	}
+
+	var seconds float64
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("malformed NPT time: %q", s)
+	}
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed NPT time: %w", err)
+		}
+		seconds = seconds*60 + v
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func formatNPT(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
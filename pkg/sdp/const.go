@@ -0,0 +1,11 @@
+package sdp
+
+const (
+	// Version is a supported SDP version (v= field)
+	Version = 0
+
+	// ContentType is a MIME type of session description
+	ContentType = "application/sdp"
+
+	lineDelimiter = "\r\n"
+)
@@ -0,0 +1,45 @@
+package sdp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoVersion happens when SDP is empty
+var ErrNoVersion = errors.New("SDP version is not presented")
+
+// ErrMalformedLine describes error when SDP line cannot be parsed
+type ErrMalformedLine struct {
+	Number int
+	Line   string
+}
+
+func (e ErrMalformedLine) Error() string {
+	return fmt.Sprintf("malformed SDP line %d: %q", e.Number, e.Line)
+}
+
+func newErrMalformedLine(number int, line string) error {
+	return ErrMalformedLine{
+		Number: number,
+		Line:   line,
+	}
+}
+
+// ErrUnexpectedLine happens when SDP line is placed out of RFC 8866 order
+type ErrUnexpectedLine struct {
+	Number int
+	Type   byte
+}
+
+func (e ErrUnexpectedLine) Error() string {
+	return fmt.Sprintf("unexpected SDP line %d: type '%c'", e.Number, e.Type)
+}
+
+// ErrVersionMismatch describes error when SDP has an unknown version
+type ErrVersionMismatch struct {
+	Version int
+}
+
+func (e ErrVersionMismatch) Error() string {
+	return fmt.Sprintf("SDP version mismatch: %d != %d", e.Version, Version)
+}
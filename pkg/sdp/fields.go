@@ -0,0 +1,203 @@
+package sdp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Origin represents o= field
+type Origin struct {
+	Username       string
+	SessionID      string
+	SessionVersion string
+	NetworkType    string
+	AddressType    string
+	Address        string
+}
+
+func (o Origin) String() string {
+	return fmt.Sprintf("%s %s %s %s %s %s", o.Username, o.SessionID, o.SessionVersion, o.NetworkType, o.AddressType, o.Address)
+}
+
+func (o *Origin) parse(value string) bool {
+	fields := strings.Fields(value)
+	if len(fields) != 6 {
+		return false
+	}
+
+	*o = Origin{
+		Username:       fields[0],
+		SessionID:      fields[1],
+		SessionVersion: fields[2],
+		NetworkType:    fields[3],
+		AddressType:    fields[4],
+		Address:        fields[5],
+	}
+	return true
+}
+
+// Connection represents c= field. TTL and Count are optional multicast parameters
+// and zero values mean they are absent: IN IP4 224.2.1.1/127/3
+type Connection struct {
+	NetworkType string
+	AddressType string
+	Address     string
+	TTL         int
+	Count       int
+}
+
+func (c Connection) String() string {
+	address := c.Address
+	if c.TTL != 0 {
+		address += "/" + strconv.Itoa(c.TTL)
+	}
+	if c.Count != 0 {
+		address += "/" + strconv.Itoa(c.Count)
+	}
+	return fmt.Sprintf("%s %s %s", c.NetworkType, c.AddressType, address)
+}
+
+func (c *Connection) parse(value string) bool {
+	fields := strings.Fields(value)
+	if len(fields) != 3 {
+		return false
+	}
+
+	*c = Connection{
+		NetworkType: fields[0],
+		AddressType: fields[1],
+	}
+
+	parts := strings.Split(fields[2], "/")
+	c.Address = parts[0]
+
+	var err error
+	switch {
+	case len(parts) > 3:
+		return false
+	case len(parts) == 3:
+		if c.Count, err = strconv.Atoi(parts[2]); err != nil {
+			return false
+		}
+		fallthrough
+	case len(parts) == 2:
+		// IPv6 multicast address hasn't TTL: IN IP6 FF15::101/3
+		if c.AddressType == "IP6" && len(parts) == 2 {
+			c.Count, err = strconv.Atoi(parts[1])
+		} else {
+			c.TTL, err = strconv.Atoi(parts[1])
+		}
+		if err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bandwidth represents b= field, e.g. AS:256
+type Bandwidth struct {
+	Type  string
+	Value int
+}
+
+func (b Bandwidth) String() string {
+	return fmt.Sprintf("%s:%d", b.Type, b.Value)
+}
+
+func (b *Bandwidth) parse(value string) bool {
+	kv := strings.SplitN(value, ":", 2)
+	if len(kv) != 2 {
+		return false
+	}
+
+	v, err := strconv.Atoi(kv[1])
+	if err != nil {
+		return false
+	}
+
+	*b = Bandwidth{Type: kv[0], Value: v}
+	return true
+}
+
+// Timing represents t= field with following r= fields
+type Timing struct {
+	Start uint64
+	Stop  uint64
+
+	// Repeats contains raw values of r= fields
+	Repeats []string
+}
+
+func (t Timing) String() string {
+	return fmt.Sprintf("%d %d", t.Start, t.Stop)
+}
+
+func (t *Timing) parse(value string) bool {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return false
+	}
+
+	var err error
+	if t.Start, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return false
+	}
+	if t.Stop, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+		return false
+	}
+	t.Repeats = nil
+
+	return true
+}
+
+// Attribute represents a= field. Property attributes have empty value: a=recvonly
+type Attribute struct {
+	Key   string
+	Value string
+
+	// HasValue is true if the key is followed by colon, so empty value is kept: a=control:
+	HasValue bool
+}
+
+func (a Attribute) String() string {
+	if a.Value == "" && !a.HasValue {
+		return a.Key
+	}
+	return a.Key + ":" + a.Value
+}
+
+func (a *Attribute) parse(value string) bool {
+	kv := strings.SplitN(value, ":", 2)
+	if kv[0] == "" {
+		return false
+	}
+
+	a.Key = kv[0]
+	a.Value = ""
+	a.HasValue = len(kv) == 2
+	if a.HasValue {
+		a.Value = kv[1]
+	}
+	return true
+}
+
+// Attributes is a list of a= fields in order of appearance
+type Attributes []Attribute
+
+// Get returns the first attribute value with specified key
+func (a Attributes) Get(key string) (string, bool) {
+	for _, attr := range a {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Has returns true if the attribute with specified key is presented
+func (a Attributes) Has(key string) bool {
+	_, ok := a.Get(key)
+	return ok
+}
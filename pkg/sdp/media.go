@@ -0,0 +1,131 @@
+package sdp
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Media represents media description (m= section)
+type Media struct {
+	Type          string       // e.g. video, audio, application
+	Port          int          // transport port
+	PortCount     int          // count of ports if presented (e.g. 49170/2), otherwise 0
+	Protocol      string       // e.g. RTP/AVP
+	Formats       []string     // payload types for RTP
+	Information   string       // i=
+	Connections   []Connection // c=
+	Bandwidths    []Bandwidth  // b=
+	EncryptionKey string       // k=
+	Attributes    Attributes   // a=
+}
+
+func (m *Media) parse(value string) bool {
+	// at least one media format is required (RFC 4566 section 5.14)
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return false
+	}
+
+	m.Type = fields[0]
+	m.Protocol = fields[2]
+	m.Formats = fields[3:]
+
+	ports := strings.SplitN(fields[1], "/", 2)
+	var err error
+	if m.Port, err = strconv.Atoi(ports[0]); err != nil {
+		return false
+	}
+	if len(ports) == 2 {
+		if m.PortCount, err = strconv.Atoi(ports[1]); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m Media) compose(buf *bytes.Buffer) {
+	port := strconv.Itoa(m.Port)
+	if m.PortCount != 0 {
+		port += "/" + strconv.Itoa(m.PortCount)
+	}
+	fields := append([]string{m.Type, port, m.Protocol}, m.Formats...)
+	writeLine(buf, 'm', strings.Join(fields, " "))
+
+	if m.Information != "" {
+		writeLine(buf, 'i', m.Information)
+	}
+	for _, c := range m.Connections {
+		writeLine(buf, 'c', c.String())
+	}
+	for _, b := range m.Bandwidths {
+		writeLine(buf, 'b', b.String())
+	}
+	if m.EncryptionKey != "" {
+		writeLine(buf, 'k', m.EncryptionKey)
+	}
+	for _, a := range m.Attributes {
+		writeLine(buf, 'a', a.String())
+	}
+}
+
+// Control returns a=control attribute value
+func (m Media) Control() string {
+	control, _ := m.Attributes.Get(AttrControl)
+	return control
+}
+
+// RTPMaps returns all a=rtpmap attributes of the media
+func (m Media) RTPMaps() []RTPMap {
+	var maps []RTPMap
+	for _, a := range m.Attributes {
+		if a.Key != AttrRTPMap {
+			continue
+		}
+		var r RTPMap
+		if err := r.Parse(a.Value); err == nil {
+			maps = append(maps, r)
+		}
+	}
+	return maps
+}
+
+// RTPMap returns a=rtpmap attribute for specified payload type
+func (m Media) RTPMap(pt uint8) (RTPMap, bool) {
+	for _, r := range m.RTPMaps() {
+		if r.PayloadType == pt {
+			return r, true
+		}
+	}
+	return RTPMap{}, false
+}
+
+// FMTP returns parsed a=fmtp parameters for specified payload type
+func (m Media) FMTP(pt uint8) (FMTP, bool) {
+	prefix := strconv.Itoa(int(pt)) + " "
+	for _, a := range m.Attributes {
+		if a.Key == AttrFMTP && strings.HasPrefix(a.Value, prefix) {
+			return ParseFMTP(a.Value[len(prefix):]), true
+		}
+	}
+	return nil, false
+}
+
+// Direction returns media direction. Session level direction is used if media hasn't own
+func (m Media) Direction(session *SessionDescription) Direction {
+	if d, ok := directionOf(m.Attributes); ok {
+		return d
+	}
+	if session != nil {
+		if d, ok := directionOf(session.Attributes); ok {
+			return d
+		}
+	}
+	return SendRecv
+}
+
+// Range returns parsed a=range attribute
+func (m Media) Range() (Range, bool) {
+	return rangeOf(m.Attributes)
+}
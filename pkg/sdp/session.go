@@ -0,0 +1,269 @@
+package sdp
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// SessionDescription represents session description (RFC 8866)
+type SessionDescription struct {
+	Version       int         // v=
+	Origin        Origin      // o=
+	SessionName   string      // s=
+	Information   string      // i=
+	URI           string      // u=
+	Emails        []string    // e=
+	Phones        []string    // p=
+	Connection    *Connection // c=
+	Bandwidths    []Bandwidth // b=
+	Timings       []Timing    // t= and r=
+	TimeZones     string      // z=
+	EncryptionKey string      // k=
+	Attributes    Attributes  // a=
+	Medias        []*Media    // m= sections
+}
+
+// Parse parses session description
+func Parse(data []byte) (*SessionDescription, error) {
+	var s SessionDescription
+	if err := s.Parse(data); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Parse parses data buffer and fills session description fields
+func (s *SessionDescription) Parse(data []byte) error {
+	*s = SessionDescription{}
+
+	var (
+		media     *Media
+		versioned bool
+	)
+	for i, line := range strings.Split(string(data), "\n") {
+		number := i + 1
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+
+		if len(line) < 2 || line[1] != '=' {
+			return newErrMalformedLine(number, line)
+		}
+
+		// v= must be the first line and it can't be repeated
+		if versioned == (line[0] == 'v') {
+			return ErrUnexpectedLine{Number: number, Type: line[0]}
+		}
+		versioned = true
+
+		var ok bool
+		if media != nil {
+			ok = s.parseMediaLine(media, line[0], line[2:])
+		} else {
+			ok = s.parseSessionLine(line[0], line[2:])
+		}
+
+		if !ok {
+			if !isKnownType(line[0]) || (media != nil && isSessionOnly(line[0])) {
+				return ErrUnexpectedLine{Number: number, Type: line[0]}
+			}
+			return newErrMalformedLine(number, line)
+		}
+
+		if line[0] == 'm' {
+			media = s.Medias[len(s.Medias)-1]
+		}
+	}
+
+	if !versioned {
+		return ErrNoVersion
+	}
+	if s.Version != Version {
+		return ErrVersionMismatch{Version: s.Version}
+	}
+
+	return nil
+}
+
+func (s *SessionDescription) parseSessionLine(t byte, value string) bool {
+	var err error
+	switch t {
+	case 'v':
+		s.Version, err = strconv.Atoi(value)
+		return err == nil
+	case 'o':
+		return s.Origin.parse(value)
+	case 's':
+		s.SessionName = value
+	case 'i':
+		s.Information = value
+	case 'u':
+		s.URI = value
+	case 'e':
+		s.Emails = append(s.Emails, value)
+	case 'p':
+		s.Phones = append(s.Phones, value)
+	case 'c':
+		s.Connection = &Connection{}
+		return s.Connection.parse(value)
+	case 'b':
+		var b Bandwidth
+		if !b.parse(value) {
+			return false
+		}
+		s.Bandwidths = append(s.Bandwidths, b)
+	case 't':
+		var timing Timing
+		if !timing.parse(value) {
+			return false
+		}
+		s.Timings = append(s.Timings, timing)
+	case 'r':
+		if len(s.Timings) == 0 {
+			return false
+		}
+		last := &s.Timings[len(s.Timings)-1]
+		last.Repeats = append(last.Repeats, value)
+	case 'z':
+		s.TimeZones = value
+	case 'k':
+		s.EncryptionKey = value
+	case 'a':
+		var a Attribute
+		if !a.parse(value) {
+			return false
+		}
+		s.Attributes = append(s.Attributes, a)
+	case 'm':
+		m := &Media{}
+		if !m.parse(value) {
+			return false
+		}
+		s.Medias = append(s.Medias, m)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *SessionDescription) parseMediaLine(m *Media, t byte, value string) bool {
+	switch t {
+	case 'm':
+		return s.parseSessionLine(t, value)
+	case 'i':
+		m.Information = value
+	case 'c':
+		var c Connection
+		if !c.parse(value) {
+			return false
+		}
+		m.Connections = append(m.Connections, c)
+	case 'b':
+		var b Bandwidth
+		if !b.parse(value) {
+			return false
+		}
+		m.Bandwidths = append(m.Bandwidths, b)
+	case 'k':
+		m.EncryptionKey = value
+	case 'a':
+		var a Attribute
+		if !a.parse(value) {
+			return false
+		}
+		m.Attributes = append(m.Attributes, a)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func isKnownType(t byte) bool {
+	return strings.IndexByte("vosiuepcbtrzkam", t) >= 0
+}
+
+func isSessionOnly(t byte) bool {
+	return strings.IndexByte("vosuepztr", t) >= 0
+}
+
+// Compose builds session description
+func (s SessionDescription) Compose() []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, 'v', strconv.Itoa(s.Version))
+	writeLine(&buf, 'o', s.Origin.String())
+	// RFC 8866 requires non-empty session name
+	if s.SessionName == "" {
+		writeLine(&buf, 's', " ")
+	} else {
+		writeLine(&buf, 's', s.SessionName)
+	}
+	if s.Information != "" {
+		writeLine(&buf, 'i', s.Information)
+	}
+	if s.URI != "" {
+		writeLine(&buf, 'u', s.URI)
+	}
+	for _, e := range s.Emails {
+		writeLine(&buf, 'e', e)
+	}
+	for _, p := range s.Phones {
+		writeLine(&buf, 'p', p)
+	}
+	if s.Connection != nil {
+		writeLine(&buf, 'c', s.Connection.String())
+	}
+	for _, b := range s.Bandwidths {
+		writeLine(&buf, 'b', b.String())
+	}
+	if len(s.Timings) == 0 {
+		writeLine(&buf, 't', Timing{}.String())
+	}
+	for _, t := range s.Timings {
+		writeLine(&buf, 't', t.String())
+		for _, r := range t.Repeats {
+			writeLine(&buf, 'r', r)
+		}
+	}
+	if s.TimeZones != "" {
+		writeLine(&buf, 'z', s.TimeZones)
+	}
+	if s.EncryptionKey != "" {
+		writeLine(&buf, 'k', s.EncryptionKey)
+	}
+	for _, a := range s.Attributes {
+		writeLine(&buf, 'a', a.String())
+	}
+	for _, m := range s.Medias {
+		m.compose(&buf)
+	}
+
+	return buf.Bytes()
+}
+
+// String returns composed session description
+func (s SessionDescription) String() string {
+	return string(s.Compose())
+}
+
+func writeLine(buf *bytes.Buffer, t byte, value string) {
+	buf.WriteByte(t)
+	buf.WriteByte('=')
+	buf.WriteString(value)
+	buf.WriteString(lineDelimiter)
+}
+
+// Control returns session level a=control attribute value
+func (s SessionDescription) Control() string {
+	control, _ := s.Attributes.Get(AttrControl)
+	return control
+}
+
+// Range returns parsed session level a=range attribute
+func (s SessionDescription) Range() (Range, bool) {
+	return rangeOf(s.Attributes)
+}
@@ -0,0 +1,207 @@
+package sdp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const cameraSDP = "v=0\r\n" +
+	"o=- 1681696377 1 IN IP4 192.168.1.64\r\n" +
+	"s=Media Presentation\r\n" +
+	"e=NONE\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"b=AS:5100\r\n" +
+	"t=0 0\r\n" +
+	"a=control:rtsp://192.168.1.64:554/Streaming/Channels/101/\r\n" +
+	"a=range:npt=now-\r\n" +
+	"m=video 0 RTP/AVP 96\r\n" +
+	"i=Video Media\r\n" +
+	"b=AS:5000\r\n" +
+	"a=recvonly\r\n" +
+	"a=x-dimensions:1920,1080\r\n" +
+	"a=control:trackID=1\r\n" +
+	"a=rtpmap:96 H264/90000\r\n" +
+	"a=fmtp:96 profile-level-id=420029; packetization-mode=1; sprop-parameter-sets=Z01AKI2NQDwBE/LCAAADAAIAAAMAZQQ=,aO44gA==\r\n" +
+	"m=audio 0 RTP/AVP 8\r\n" +
+	"i=Audio Media\r\n" +
+	"b=AS:50\r\n" +
+	"a=recvonly\r\n" +
+	"a=control:trackID=2\r\n" +
+	"a=rtpmap:8 PCMA/8000\r\n"
+
+func TestSessionDescription_Parse(t *testing.T) {
+	s, err := Parse([]byte(cameraSDP))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Origin{
+		Username:       "-",
+		SessionID:      "1681696377",
+		SessionVersion: "1",
+		NetworkType:    "IN",
+		AddressType:    "IP4",
+		Address:        "192.168.1.64",
+	}, s.Origin)
+	assert.Equal(t, "Media Presentation", s.SessionName)
+	assert.Equal(t, []string{"NONE"}, s.Emails)
+	assert.Equal(t, &Connection{NetworkType: "IN", AddressType: "IP4", Address: "0.0.0.0"}, s.Connection)
+	assert.Equal(t, []Bandwidth{{Type: "AS", Value: 5100}}, s.Bandwidths)
+	assert.Equal(t, []Timing{{}}, s.Timings)
+	assert.Equal(t, "rtsp://192.168.1.64:554/Streaming/Channels/101/", s.Control())
+
+	r, ok := s.Range()
+	assert.True(t, ok)
+	assert.Equal(t, Range{}, r)
+
+	assert.Len(t, s.Medias, 2)
+
+	video := s.Medias[0]
+	assert.Equal(t, "video", video.Type)
+	assert.Equal(t, "RTP/AVP", video.Protocol)
+	assert.Equal(t, []string{"96"}, video.Formats)
+	assert.Equal(t, "trackID=1", video.Control())
+	assert.Equal(t, RecvOnly, video.Direction(s))
+
+	rtpMap, ok := video.RTPMap(96)
+	assert.True(t, ok)
+	assert.Equal(t, RTPMap{PayloadType: 96, EncodingName: "H264", ClockRate: 90000}, rtpMap)
+
+	fmtp, ok := video.FMTP(96)
+	assert.True(t, ok)
+	mode, ok := fmtp.Int("packetization-mode")
+	assert.True(t, ok)
+	assert.Equal(t, 1, mode)
+	sprop, _ := fmtp.Get("sprop-parameter-sets")
+	assert.Equal(t, "Z01AKI2NQDwBE/LCAAADAAIAAAMAZQQ=,aO44gA==", sprop)
+
+	audio := s.Medias[1]
+	_, ok = audio.FMTP(8)
+	assert.False(t, ok)
+	rtpMap, ok = audio.RTPMap(8)
+	assert.True(t, ok)
+	assert.Equal(t, RTPMap{PayloadType: 8, EncodingName: "PCMA", ClockRate: 8000}, rtpMap)
+}
+
+func TestSessionDescription_Compose(t *testing.T) {
+	type testCase struct {
+		raw string
+	}
+
+	testCases := []testCase{
+		{raw: cameraSDP},
+		{
+			raw: "v=0\r\n" +
+				"o=jdoe 2890844526 2890842807 IN IP4 10.47.16.5\r\n" +
+				"s=SDP Seminar\r\n" +
+				"i=A Seminar on the session description protocol\r\n" +
+				"u=http://www.example.com/seminars/sdp.pdf\r\n" +
+				"e=j.doe@example.com (Jane Doe)\r\n" +
+				"p=+1 617 555-6011\r\n" +
+				"c=IN IP4 224.2.17.12/127\r\n" +
+				"t=2873397496 2873404696\r\n" +
+				"r=7d 1h 0 25h\r\n" +
+				"z=2882844526 -1h 2898848070 0\r\n" +
+				"k=clear:secret\r\n" +
+				"a=recvonly\r\n" +
+				"m=audio 49170 RTP/AVP 0\r\n" +
+				"m=video 51372/2 RTP/AVP 99\r\n" +
+				"c=IN IP6 FF15::101/3\r\n" +
+				"a=rtpmap:99 h263-1998/90000\r\n",
+		},
+		{
+			raw: "v=0\r\n" +
+				"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+				"s=-\r\n" +
+				"t=0 0\r\n" +
+				"a=control:\r\n" +
+				"m=video 0 RTP/AVP 96\r\n" +
+				"a=recvonly\r\n",
+		},
+	}
+
+	for i, c := range testCases {
+		s, err := Parse([]byte(c.raw))
+		assert.NoError(t, err, "testCase : %d", i+1)
+		assert.Equal(t, c.raw, string(s.Compose()), "testCase : %d", i+1)
+	}
+}
+
+func TestSessionDescription_ParseErrors(t *testing.T) {
+	type testCase struct {
+		raw string
+		err error
+	}
+
+	testCases := []testCase{
+		{
+			raw: "o=- 0 0 IN IP4 127.0.0.1\r\n",
+			err: ErrUnexpectedLine{Number: 1, Type: 'o'},
+		},
+		{
+			raw: "\r\no=- 0 0 IN IP4 127.0.0.1\r\n",
+			err: ErrUnexpectedLine{Number: 2, Type: 'o'},
+		},
+		{
+			raw: "v=0\r\nv=0\r\n",
+			err: ErrUnexpectedLine{Number: 2, Type: 'v'},
+		},
+		{
+			raw: "\r\n",
+			err: ErrNoVersion,
+		},
+		{
+			raw: "v=1\r\n",
+			err: ErrVersionMismatch{Version: 1},
+		},
+		{
+			raw: "v=0\r\nbroken\r\n",
+			err: ErrMalformedLine{Number: 2, Line: "broken"},
+		},
+		{
+			raw: "v=0\r\no=- 0 0 IN IP4\r\n",
+			err: ErrMalformedLine{Number: 2, Line: "o=- 0 0 IN IP4"},
+		},
+		{
+			raw: "v=0\r\nm=video 0 RTP/AVP 96\r\nt=0 0\r\n",
+			err: ErrUnexpectedLine{Number: 3, Type: 't'},
+		},
+		{
+			raw: "v=0\r\nm=video 0 RTP/AVP\r\n",
+			err: ErrMalformedLine{Number: 2, Line: "m=video 0 RTP/AVP"},
+		},
+		{
+			raw: "v=0\r\nx=unknown\r\n",
+			err: ErrUnexpectedLine{Number: 2, Type: 'x'},
+		},
+	}
+
+	for i, c := range testCases {
+		_, err := Parse([]byte(c.raw))
+		assert.Equal(t, c.err, err, "testCase : %d", i+1)
+	}
+}
+
+func TestRange(t *testing.T) {
+	type testCase struct {
+		raw   string
+		r     Range
+		again string
+	}
+
+	testCases := []testCase{
+		{raw: "npt=0-", r: Range{}, again: "npt=0-"},
+		{raw: "npt=now-", r: Range{}, again: "npt=0-"},
+		{raw: "npt=0.000-34.5", r: Range{End: 34500 * time.Millisecond}, again: "npt=0-34.5"},
+		{raw: "npt=00:01:02.5-01:00:00", r: Range{Start: 62500 * time.Millisecond, End: time.Hour}, again: "npt=62.5-3600"},
+	}
+
+	for i, c := range testCases {
+		var r Range
+		assert.NoError(t, r.Parse(c.raw), "testCase : %d", i+1)
+		assert.Equal(t, c.r, r, "testCase : %d", i+1)
+		assert.Equal(t, c.again, r.String(), "testCase : %d", i+1)
+	}
+
+	var r Range
+	assert.Error(t, r.Parse("clock=19961108T142300Z-"))
+}
@@ -0,0 +1,264 @@
+package gortsp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/racoon-devel/gortsp/pkg/rtsp"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrServerClosed is returned by Serve after the server has been closed
+var ErrServerClosed = errors.New("rtsp: server closed")
+
+// Handler responds to an RTSP request
+type Handler interface {
+	ServeRTSP(w ResponseWriter, r *rtsp.Request)
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as RTSP handlers
+type HandlerFunc func(w ResponseWriter, r *rtsp.Request)
+
+// ServeRTSP calls f(w, r)
+func (f HandlerFunc) ServeRTSP(w ResponseWriter, r *rtsp.Request) {
+	f(w, r)
+}
+
+// ResponseWriter is used by Handler to construct an RTSP response. The response is sent
+// after ServeRTSP returns. CSeq header is set automatically
+type ResponseWriter interface {
+	// Header returns the header map that will be sent
+	Header() http.Header
+
+	// WriteHeader sets response status code. 200 OK is used by default
+	WriteHeader(code rtsp.StatusCode)
+
+	// Write appends data to the response body
+	Write(data []byte) (int, error)
+
+	// Conn returns connection the request has been received from
+	Conn() *Conn
+}
+
+// Conn represents server side of client connection
+type Conn struct {
+	s      *rtsp.Session
+	remote net.Addr
+}
+
+// RemoteAddr returns client network address
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// WritePacket sends interleaved RTP or RTCP packet to the client
+func (c *Conn) WritePacket(channel uint8, packet []byte) error {
+	return c.s.WritePacket(channel, packet)
+}
+
+// Close closes the connection
+func (c *Conn) Close() {
+	c.s.Close()
+}
+
+// Server accepts RTSP connections and forwards requests to Handler
+type Server struct {
+	// Addr is a TCP address to listen on, ":554" if empty
+	Addr string
+
+	// Handler to invoke for every request
+	Handler Handler
+
+	// ServerName is sent in Server header if not empty
+	ServerName string
+
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// ListenAndServe listens on TCP address and handles incoming connections
+func (s *Server) ListenAndServe() error {
+	addr := s.Addr
+	if addr == "" {
+		addr = ":" + defaultPort
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener. It always returns non-nil error, ErrServerClosed after Close.
+// Serve may be called again after other errors
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.init()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	if s.listener != nil {
+		s.mu.Unlock()
+		return errors.New("rtsp: server is already serving")
+	}
+	ctx := s.ctx
+	s.listener = l
+	s.mu.Unlock()
+
+	var delay time.Duration
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ErrServerClosed
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				delay = acceptDelay(delay)
+				select {
+				case <-time.After(delay):
+					continue
+				case <-ctx.Done():
+					return ErrServerClosed
+				}
+			}
+
+			s.mu.Lock()
+			if s.listener == l {
+				s.listener = nil
+			}
+			s.mu.Unlock()
+			return err
+		}
+		delay = 0
+
+		// connections must not be added to the wait group after Close has started waiting for them
+		s.mu.Lock()
+		if ctx.Err() != nil {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		c := &Conn{
+			s:      rtsp.NewSession(conn, ctx),
+			remote: conn.RemoteAddr(),
+		}
+
+		go func() {
+			defer s.wg.Done()
+			s.serve(c)
+		}()
+	}
+}
+
+// Close stops listening and closes all active connections. The server can't be used after Close
+func (s *Server) Close() error {
+	var err error
+
+	s.mu.Lock()
+	s.init()
+	s.cancel()
+	if s.listener != nil {
+		err = s.listener.Close()
+		s.listener = nil
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// init creates context of the server connections, it must be called under the lock
+func (s *Server) init() {
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
+}
+
+// acceptDelay doubles delay before the next Accept after temporary error
+func acceptDelay(delay time.Duration) time.Duration {
+	if delay == 0 {
+		return minAcceptDelay
+	}
+	if delay *= 2; delay > maxAcceptDelay {
+		return maxAcceptDelay
+	}
+	return delay
+}
+
+func (s *Server) serve(c *Conn) {
+	defer c.Close()
+
+	for item := range c.s.Incoming() {
+		req, ok := item.(*rtsp.Request)
+		if !ok {
+			continue
+		}
+
+		if err := c.s.WriteResponse(s.handle(c, req)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(c *Conn, req *rtsp.Request) *rtsp.Response {
+	w := &response{
+		conn:   c,
+		header: make(http.Header),
+		code:   rtsp.Ok,
+	}
+
+	if s.Handler != nil {
+		s.Handler.ServeRTSP(w, req)
+	} else {
+		w.code = rtsp.NotImplemented
+	}
+
+	if s.ServerName != "" {
+		w.header.Set("Server", s.ServerName)
+	}
+	w.header.Set("Cseq", req.Header.Get("Cseq"))
+	w.header.Del("Content-Length")
+
+	return &rtsp.Response{
+		Status:     w.code.String(),
+		StatusCode: w.code,
+		ProtoMajor: 1,
+		Header:     w.header,
+		Body:       w.body.Bytes(),
+	}
+}
+
+type response struct {
+	conn   *Conn
+	header http.Header
+	code   rtsp.StatusCode
+	body   bytes.Buffer
+}
+
+func (r *response) Header() http.Header {
+	return r.header
+}
+
+func (r *response) WriteHeader(code rtsp.StatusCode) {
+	r.code = code
+}
+
+func (r *response) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *response) Conn() *Conn {
+	return r.conn
+}
@@ -0,0 +1,334 @@
+package gortsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/racoon-devel/gortsp/pkg/format"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/racoon-devel/gortsp/pkg/rtsp"
+	"github.com/racoon-devel/gortsp/pkg/sdp"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSDP = "v=0\r\n" +
+	"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+	"s=test\r\n" +
+	"t=0 0\r\n" +
+	"a=control:*\r\n" +
+	"m=video 0 RTP/AVP 96\r\n" +
+	"a=rtpmap:96 H264/90000\r\n" +
+	"a=control:trackID=1\r\n"
+
+// testGroup is a multicast group of the test stream
+var testGroup = &net.UDPAddr{IP: net.IPv4(239, 255, 42, 98), Port: 47780}
+
+// testHandler serves single H.264 stream and sends one RTP packet after PLAY
+type testHandler struct {
+	mu          sync.Mutex
+	clientPorts map[*Conn]rtsp.PortRange
+	multicast   map[*Conn]bool
+}
+
+func newTestHandler() *testHandler {
+	return &testHandler{
+		clientPorts: map[*Conn]rtsp.PortRange{},
+		multicast:   map[*Conn]bool{},
+	}
+}
+
+func (h *testHandler) ServeRTSP(w ResponseWriter, r *rtsp.Request) {
+	switch r.Method {
+	case rtsp.Options:
+		w.Header().Set("Public", "OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN")
+	case rtsp.Describe:
+		w.Header().Set("Content-Type", sdp.ContentType)
+		w.Header().Set("Content-Base", r.URL.String()+"/")
+		_, _ = w.Write([]byte(testSDP))
+	case rtsp.Setup:
+		transports, err := rtsp.ParseTransports(r.Header.Get("Transport"))
+		if err != nil {
+			w.WriteHeader(rtsp.UnsupportedTransport)
+			return
+		}
+		transport := transports[0]
+		if transport.Delivery == rtsp.Multicast {
+			h.mu.Lock()
+			h.multicast[w.Conn()] = true
+			h.mu.Unlock()
+			transport.Destination = testGroup.IP.String()
+			transport.Port = &rtsp.PortRange{From: uint16(testGroup.Port), To: uint16(testGroup.Port + 1)}
+		}
+		if transport.ClientPort != nil {
+			h.mu.Lock()
+			h.clientPorts[w.Conn()] = *transport.ClientPort
+			h.mu.Unlock()
+			transport.ServerPort = &rtsp.PortRange{From: 6970, To: 6971}
+		}
+		w.Header().Set("Session", "12345678;timeout=60")
+		w.Header().Set("Transport", transport.String())
+	case rtsp.Play:
+		conn := w.Conn()
+		h.mu.Lock()
+		ports, udp := h.clientPorts[conn]
+		multicast := h.multicast[conn]
+		h.mu.Unlock()
+		go func() {
+			p := rtp.Packet{
+				Header: rtp.Header{
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 9164,
+					Timestamp:      1681696377,
+				},
+				Payload: []byte{0x01, 0x02, 0x03},
+			}
+			buf, _ := p.Compose()
+			if multicast {
+				if udpConn, err := net.DialUDP("udp4", nil, testGroup); err == nil {
+					_, _ = udpConn.Write(buf)
+					_ = udpConn.Close()
+				}
+				return
+			}
+			if !udp {
+				_ = conn.WritePacket(0, buf)
+				return
+			}
+			host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+			if udpConn, err := net.Dial("udp", net.JoinHostPort(host, strconv.Itoa(int(ports.From)))); err == nil {
+				_, _ = udpConn.Write(buf)
+				_ = udpConn.Close()
+			}
+		}()
+	default:
+		w.WriteHeader(rtsp.MethodNotAllowed)
+	}
+}
+
+func startServer(t *testing.T, h Handler) (*Server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	s := &Server{Handler: h, ServerName: "gortsp"}
+	go func() {
+		assert.ErrorIs(t, s.Serve(l), ErrServerClosed)
+	}()
+
+	return s, l.Addr().String()
+}
+
+func TestServer_Serve(t *testing.T) {
+	srv, addr := startServer(t, newTestHandler())
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+
+	s := rtsp.NewSession(conn, context.Background())
+	defer s.Close()
+
+	req, err := rtsp.NewRequest(rtsp.Options, fmt.Sprintf("rtsp://%s/stream", addr))
+	assert.NoError(t, err)
+	req.Header = http.Header{}
+
+	resp, err := s.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, rtsp.Ok, resp.StatusCode)
+	assert.Equal(t, "gortsp", resp.Header.Get("Server"))
+	assert.Equal(t, "OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN", resp.Header.Get("Public"))
+
+	req.Method = rtsp.Record
+	req.Header = http.Header{}
+	resp, err = s.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, rtsp.MethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "Method Not Allowed", resp.Status)
+}
+
+// errListener returns queued errors from Accept before accepting connections
+type errListener struct {
+	net.Listener
+	errs []error
+}
+
+func (l *errListener) Accept() (net.Conn, error) {
+	if len(l.errs) != 0 {
+		err := l.errs[0]
+		l.errs = l.errs[1:]
+		return nil, err
+	}
+	return l.Listener.Accept()
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary error" }
+func (temporaryError) Timeout() bool   { return false }
+func (temporaryError) Temporary() bool { return true }
+
+func TestServer_ServeErrors(t *testing.T) {
+	s := &Server{Handler: newTestHandler()}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	fatal := errors.New("fatal error")
+	assert.ErrorIs(t, s.Serve(&errListener{Listener: l, errs: []error{temporaryError{}, temporaryError{}, fatal}}), fatal)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- s.Serve(l)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	session := rtsp.NewSession(conn, context.Background())
+	defer session.Close()
+
+	req, err := rtsp.NewRequest(rtsp.Options, fmt.Sprintf("rtsp://%s/stream", l.Addr()))
+	assert.NoError(t, err)
+	req.Header = http.Header{}
+	resp, err := session.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, rtsp.Ok, resp.StatusCode)
+
+	assert.NoError(t, s.Close())
+	assert.ErrorIs(t, <-served, ErrServerClosed)
+	assert.ErrorIs(t, s.Serve(l), ErrServerClosed)
+}
+
+func TestClient_Receive(t *testing.T) {
+	for _, mode := range []TransportMode{TransportTCP, TransportUDP} {
+		testClientReceive(t, mode)
+	}
+}
+
+func testClientReceive(t *testing.T, mode TransportMode) {
+	srv, addr := startServer(t, newTestHandler())
+	defer srv.Close()
+
+	packets := make(chan *rtp.Packet, 1)
+	c := Client{
+		UserAgent: "gortsp",
+		Transport: mode,
+		OnPacket: func(media int, p *rtp.Packet) {
+			assert.Equal(t, 0, media)
+			packets <- p
+		},
+	}
+
+	assert.NoError(t, c.Run(fmt.Sprintf("rtsp://%s/stream", addr)))
+	defer c.Close()
+
+	go func() {
+		_ = c.Receive()
+	}()
+
+	select {
+	case p := <-packets:
+		assert.Equal(t, uint16(9164), p.Header.SequenceNumber)
+		assert.Equal(t, []byte{0x01, 0x02, 0x03}, p.Payload)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "packet is not received", "transport mode: %d", mode)
+	}
+
+	assert.Len(t, c.Description().Medias, 1)
+}
+
+func TestClient_ReceiveMulticast(t *testing.T) {
+	srv, addr := startServer(t, newTestHandler())
+	defer srv.Close()
+
+	packets := make(chan *rtp.Packet, 1)
+	c := Client{
+		UserAgent: "gortsp",
+		Transport: TransportMulticast,
+		OnPacket: func(media int, p *rtp.Packet) {
+			packets <- p
+		},
+	}
+
+	if err := c.Run(fmt.Sprintf("rtsp://%s/stream", addr)); err != nil {
+		t.Skipf("multicast is not available: %s", err)
+	}
+	defer c.Close()
+
+	go func() {
+		_ = c.Receive()
+	}()
+
+	select {
+	case p := <-packets:
+		assert.Equal(t, uint16(9164), p.Header.SequenceNumber)
+		assert.Equal(t, []byte{0x01, 0x02, 0x03}, p.Payload)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "packet is not received")
+	}
+}
+
+func TestClient_ReceiveFrames(t *testing.T) {
+	srv, addr := startServer(t, newTestHandler())
+	defer srv.Close()
+
+	frames := make(chan *format.Frame, 1)
+	c := Client{
+		UserAgent: "gortsp",
+		OnFrame: func(media int, f format.Format, frame *format.Frame) {
+			assert.Equal(t, 0, media)
+			assert.Equal(t, "H264", f.Codec())
+			frames <- frame
+		},
+	}
+
+	assert.NoError(t, c.Run(fmt.Sprintf("rtsp://%s/stream", addr)))
+	defer c.Close()
+
+	go func() {
+		_ = c.Receive()
+	}()
+
+	select {
+	case f := <-frames:
+		assert.Equal(t, uint32(1681696377), f.Timestamp)
+		assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x01, 0x01, 0x02, 0x03}, f.Data)
+		assert.False(t, f.Key)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "frame is not received")
+	}
+}
+
+func TestClient_ReceiveWithoutHandlers(t *testing.T) {
+	srv, addr := startServer(t, newTestHandler())
+	defer srv.Close()
+
+	c := Client{UserAgent: "gortsp"}
+	assert.NoError(t, c.Run(fmt.Sprintf("rtsp://%s/stream", addr)))
+	defer c.Close()
+
+	go func() {
+		_ = c.Receive()
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(c.Stats(0)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stats := c.Stats(0)
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, uint32(9164), stats[0].HighestSequence)
+		assert.Equal(t, uint64(1), stats[0].Received)
+	}
+
+	c.rtcpMu.Lock()
+	rr := c.receivers[0].Report(time.Now())
+	c.rtcpMu.Unlock()
+	assert.Len(t, rr.Reports, 1)
+}
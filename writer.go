@@ -0,0 +1,118 @@
+package gortsp
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/racoon-devel/gortsp/pkg/rtcp"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+)
+
+// ErrWriterClosed is returned by MediaWriter after Close
+var ErrWriterClosed = errors.New("media writer closed")
+
+// PacketWriter sends RTP or RTCP packet to the channel, it is implemented by Conn and rtsp.Session
+type PacketWriter interface {
+	WritePacket(channel uint8, packet []byte) error
+}
+
+// MediaWriter sends RTP packets of single media and RTCP sender reports of the stream. RTP packets are sent
+// to the channel and RTCP packets are sent to the next one. Reports are sent on schedule even if no packets are written
+type MediaWriter struct {
+	w       PacketWriter
+	channel uint8
+	sender  *rtcp.Sender
+
+	mu     sync.Mutex
+	buf    []byte
+	timer  *time.Timer
+	closed bool
+}
+
+// NewMediaWriter creates writer of the media stream described by sender
+func NewMediaWriter(w PacketWriter, channel uint8, sender *rtcp.Sender) *MediaWriter {
+	return &MediaWriter{
+		w:       w,
+		channel: channel,
+		sender:  sender,
+	}
+}
+
+// WritePacket sends RTP packet, it is followed by sender report when the report is due
+func (m *MediaWriter) WritePacket(p *rtp.Packet) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrWriterClosed
+	}
+	if size := p.Size(); cap(m.buf) < size {
+		m.buf = make([]byte, size)
+	}
+	n, err := p.ComposeTo(m.buf[:cap(m.buf)])
+	if err != nil {
+		return err
+	}
+	if err = m.w.WritePacket(m.channel, m.buf[:n]); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	m.sender.ProcessRTP(p, now)
+	if m.timer == nil {
+		m.timer = time.AfterFunc(time.Until(m.sender.Next()), m.report)
+	}
+	if !m.sender.Due(now) {
+		return nil
+	}
+	return m.writeRTCP(m.sender.Report(now))
+}
+
+// Stats returns statistics of sent packets
+func (m *MediaWriter) Stats() rtcp.SenderStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.sender.Stats()
+}
+
+// Close stops reports and sends BYE packet to notify receivers that the stream is finished.
+// Subsequent calls do nothing
+func (m *MediaWriter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+
+	return m.writeRTCP(m.sender.Goodbye(time.Now(), ""))
+}
+
+// report sends sender report when it is due and schedules the next one. Failed report isn't retried,
+// the next one is sent on schedule
+func (m *MediaWriter) report() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	if now := time.Now(); m.sender.Due(now) {
+		_ = m.writeRTCP(m.sender.Report(now))
+	}
+	m.timer.Reset(time.Until(m.sender.Next()))
+}
+
+func (m *MediaWriter) writeRTCP(packets []rtcp.Packet) error {
+	data, err := rtcp.Compose(packets...)
+	if err != nil {
+		return err
+	}
+	return m.w.WritePacket(m.channel+1, data)
+}
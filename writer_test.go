@@ -0,0 +1,116 @@
+package gortsp
+
+import (
+	"errors"
+	"github.com/racoon-devel/gortsp/pkg/rtcp"
+	"github.com/racoon-devel/gortsp/pkg/rtp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// rtcpFailer accepts RTP packets and fails to send RTCP ones
+type rtcpFailer struct {
+	attempts int
+}
+
+func (f *rtcpFailer) WritePacket(channel uint8, packet []byte) error {
+	if channel%2 == 0 {
+		return nil
+	}
+	f.attempts++
+	return errors.New("RTCP is not delivered")
+}
+
+type packetRecorder map[uint8][][]byte
+
+func (r packetRecorder) WritePacket(channel uint8, packet []byte) error {
+	r[channel] = append(r[channel], append([]byte(nil), packet...))
+	return nil
+}
+
+func TestMediaWriter(t *testing.T) {
+	recorder := packetRecorder{}
+	sender := rtcp.NewSender(0x1000, 90000, "gortsp")
+	w := NewMediaWriter(recorder, 2, sender)
+
+	p := rtp.Packet{Header: rtp.Header{PayloadType: 96, SSRC: 0x1000}, Payload: []byte{0x01, 0x02}}
+	for i := 0; i < 2; i++ {
+		p.Header.SequenceNumber = uint16(i)
+		assert.NoError(t, w.WritePacket(&p))
+	}
+
+	if assert.Len(t, recorder[2], 2) {
+		received, err := rtp.Parse(recorder[2][1])
+		assert.NoError(t, err)
+		assert.Equal(t, &p, received)
+	}
+	assert.Empty(t, recorder[3])
+	assert.Equal(t, rtcp.SenderStats{SSRC: 0x1000, PacketCount: 2, OctetCount: 4}, w.Stats())
+
+	assert.NoError(t, w.Close())
+	if assert.Len(t, recorder[3], 1) {
+		packets, err := rtcp.Parse(recorder[3][0])
+		assert.NoError(t, err)
+		if assert.Len(t, packets, 3) {
+			assert.Equal(t, uint32(2), packets[0].(*rtcp.SenderReport).PacketCount)
+			assert.Equal(t, []uint32{0x1000}, packets[2].(*rtcp.Goodbye).Sources)
+		}
+	}
+
+	assert.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WritePacket(&p), ErrWriterClosed)
+	assert.Len(t, recorder[3], 1)
+	assert.Len(t, recorder[2], 2)
+}
+
+func TestMediaWriter_Idle(t *testing.T) {
+	recorder := packetRecorder{}
+	sender := rtcp.NewSender(0x1000, 90000, "gortsp")
+	w := NewMediaWriter(recorder, 0, sender)
+
+	p := rtp.Packet{Header: rtp.Header{PayloadType: 96, SSRC: 0x1000}, Payload: []byte{0x01, 0x02}}
+	assert.NoError(t, w.WritePacket(&p))
+
+	defer w.Close()
+
+	reported := assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(recorder[1]) != 0
+	}, 2*rtcp.MinInterval, 10*time.Millisecond)
+	if !reported {
+		return
+	}
+
+	w.mu.Lock()
+	packets, err := rtcp.Parse(recorder[1][0])
+	w.mu.Unlock()
+	assert.NoError(t, err)
+	if assert.Len(t, packets, 2) {
+		assert.Equal(t, uint32(1), packets[0].(*rtcp.SenderReport).PacketCount)
+	}
+}
+
+func TestMediaWriter_ReportFailed(t *testing.T) {
+	failer := &rtcpFailer{}
+	sender := rtcp.NewSender(0x1000, 90000, "gortsp")
+	w := NewMediaWriter(failer, 0, sender)
+
+	p := rtp.Packet{Header: rtp.Header{PayloadType: 96, SSRC: 0x1000}, Payload: []byte{0x01, 0x02}}
+	assert.NoError(t, w.WritePacket(&p))
+
+	failed := assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return failer.attempts != 0
+	}, 2*rtcp.MinInterval, 10*time.Millisecond)
+	if !failed {
+		return
+	}
+
+	// the next report is scheduled anyway
+	w.mu.Lock()
+	assert.True(t, w.timer.Stop())
+	w.mu.Unlock()
+}